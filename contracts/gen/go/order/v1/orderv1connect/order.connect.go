@@ -0,0 +1,546 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: order/v1/order.proto
+
+package orderv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/demo/contracts/gen/go/order/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// OrderServiceName is the fully-qualified name of the OrderService service.
+	OrderServiceName = "order.v1.OrderService"
+	// WebhookServiceName is the fully-qualified name of the WebhookService service.
+	WebhookServiceName = "order.v1.WebhookService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// OrderServiceCreateOrderProcedure is the fully-qualified name of the OrderService's CreateOrder
+	// RPC.
+	OrderServiceCreateOrderProcedure = "/order.v1.OrderService/CreateOrder"
+	// OrderServiceGetOrderProcedure is the fully-qualified name of the OrderService's GetOrder RPC.
+	OrderServiceGetOrderProcedure = "/order.v1.OrderService/GetOrder"
+	// OrderServiceListOrdersProcedure is the fully-qualified name of the OrderService's ListOrders RPC.
+	OrderServiceListOrdersProcedure = "/order.v1.OrderService/ListOrders"
+	// OrderServiceCheckOrderOwnerProcedure is the fully-qualified name of the OrderService's
+	// CheckOrderOwner RPC.
+	OrderServiceCheckOrderOwnerProcedure = "/order.v1.OrderService/CheckOrderOwner"
+	// OrderServiceUpdateOrderStatusProcedure is the fully-qualified name of the OrderService's
+	// UpdateOrderStatus RPC.
+	OrderServiceUpdateOrderStatusProcedure = "/order.v1.OrderService/UpdateOrderStatus"
+	// OrderServiceCancelOrderProcedure is the fully-qualified name of the OrderService's CancelOrder
+	// RPC.
+	OrderServiceCancelOrderProcedure = "/order.v1.OrderService/CancelOrder"
+	// OrderServiceWatchOrdersProcedure is the fully-qualified name of the OrderService's WatchOrders
+	// RPC.
+	OrderServiceWatchOrdersProcedure = "/order.v1.OrderService/WatchOrders"
+	// OrderServiceDeleteOrderProcedure is the fully-qualified name of the OrderService's DeleteOrder
+	// RPC.
+	OrderServiceDeleteOrderProcedure = "/order.v1.OrderService/DeleteOrder"
+	// OrderServiceRestoreOrderProcedure is the fully-qualified name of the OrderService's RestoreOrder
+	// RPC.
+	OrderServiceRestoreOrderProcedure = "/order.v1.OrderService/RestoreOrder"
+	// WebhookServiceCreateWebhookSubscriptionProcedure is the fully-qualified name of the
+	// WebhookService's CreateWebhookSubscription RPC.
+	WebhookServiceCreateWebhookSubscriptionProcedure = "/order.v1.WebhookService/CreateWebhookSubscription"
+	// WebhookServiceListWebhookSubscriptionsProcedure is the fully-qualified name of the
+	// WebhookService's ListWebhookSubscriptions RPC.
+	WebhookServiceListWebhookSubscriptionsProcedure = "/order.v1.WebhookService/ListWebhookSubscriptions"
+	// WebhookServiceDeleteWebhookSubscriptionProcedure is the fully-qualified name of the
+	// WebhookService's DeleteWebhookSubscription RPC.
+	WebhookServiceDeleteWebhookSubscriptionProcedure = "/order.v1.WebhookService/DeleteWebhookSubscription"
+	// WebhookServiceListWebhookDeliveriesProcedure is the fully-qualified name of the WebhookService's
+	// ListWebhookDeliveries RPC.
+	WebhookServiceListWebhookDeliveriesProcedure = "/order.v1.WebhookService/ListWebhookDeliveries"
+	// WebhookServiceReplayWebhookDeliveryProcedure is the fully-qualified name of the WebhookService's
+	// ReplayWebhookDelivery RPC.
+	WebhookServiceReplayWebhookDeliveryProcedure = "/order.v1.WebhookService/ReplayWebhookDelivery"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	orderServiceServiceDescriptor                           = v1.File_order_v1_order_proto.Services().ByName("OrderService")
+	orderServiceCreateOrderMethodDescriptor                 = orderServiceServiceDescriptor.Methods().ByName("CreateOrder")
+	orderServiceGetOrderMethodDescriptor                    = orderServiceServiceDescriptor.Methods().ByName("GetOrder")
+	orderServiceListOrdersMethodDescriptor                  = orderServiceServiceDescriptor.Methods().ByName("ListOrders")
+	orderServiceCheckOrderOwnerMethodDescriptor             = orderServiceServiceDescriptor.Methods().ByName("CheckOrderOwner")
+	orderServiceUpdateOrderStatusMethodDescriptor           = orderServiceServiceDescriptor.Methods().ByName("UpdateOrderStatus")
+	orderServiceCancelOrderMethodDescriptor                 = orderServiceServiceDescriptor.Methods().ByName("CancelOrder")
+	orderServiceWatchOrdersMethodDescriptor                 = orderServiceServiceDescriptor.Methods().ByName("WatchOrders")
+	orderServiceDeleteOrderMethodDescriptor                 = orderServiceServiceDescriptor.Methods().ByName("DeleteOrder")
+	orderServiceRestoreOrderMethodDescriptor                = orderServiceServiceDescriptor.Methods().ByName("RestoreOrder")
+	webhookServiceServiceDescriptor                         = v1.File_order_v1_order_proto.Services().ByName("WebhookService")
+	webhookServiceCreateWebhookSubscriptionMethodDescriptor = webhookServiceServiceDescriptor.Methods().ByName("CreateWebhookSubscription")
+	webhookServiceListWebhookSubscriptionsMethodDescriptor  = webhookServiceServiceDescriptor.Methods().ByName("ListWebhookSubscriptions")
+	webhookServiceDeleteWebhookSubscriptionMethodDescriptor = webhookServiceServiceDescriptor.Methods().ByName("DeleteWebhookSubscription")
+	webhookServiceListWebhookDeliveriesMethodDescriptor     = webhookServiceServiceDescriptor.Methods().ByName("ListWebhookDeliveries")
+	webhookServiceReplayWebhookDeliveryMethodDescriptor     = webhookServiceServiceDescriptor.Methods().ByName("ReplayWebhookDelivery")
+)
+
+// OrderServiceClient is a client for the order.v1.OrderService service.
+type OrderServiceClient interface {
+	CreateOrder(context.Context, *connect.Request[v1.CreateOrderRequest]) (*connect.Response[v1.CreateOrderResponse], error)
+	GetOrder(context.Context, *connect.Request[v1.GetOrderRequest]) (*connect.Response[v1.GetOrderResponse], error)
+	ListOrders(context.Context, *connect.Request[v1.ListOrdersRequest]) (*connect.Response[v1.ListOrdersResponse], error)
+	CheckOrderOwner(context.Context, *connect.Request[v1.CheckOrderOwnerRequest]) (*connect.Response[v1.CheckOrderOwnerResponse], error)
+	UpdateOrderStatus(context.Context, *connect.Request[v1.UpdateOrderStatusRequest]) (*connect.Response[v1.UpdateOrderStatusResponse], error)
+	CancelOrder(context.Context, *connect.Request[v1.CancelOrderRequest]) (*connect.Response[v1.CancelOrderResponse], error)
+	WatchOrders(context.Context, *connect.Request[v1.WatchOrdersRequest]) (*connect.ServerStreamForClient[v1.WatchOrdersResponse], error)
+	DeleteOrder(context.Context, *connect.Request[v1.DeleteOrderRequest]) (*connect.Response[v1.DeleteOrderResponse], error)
+	RestoreOrder(context.Context, *connect.Request[v1.RestoreOrderRequest]) (*connect.Response[v1.RestoreOrderResponse], error)
+}
+
+// NewOrderServiceClient constructs a client for the order.v1.OrderService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewOrderServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) OrderServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &orderServiceClient{
+		createOrder: connect.NewClient[v1.CreateOrderRequest, v1.CreateOrderResponse](
+			httpClient,
+			baseURL+OrderServiceCreateOrderProcedure,
+			connect.WithSchema(orderServiceCreateOrderMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getOrder: connect.NewClient[v1.GetOrderRequest, v1.GetOrderResponse](
+			httpClient,
+			baseURL+OrderServiceGetOrderProcedure,
+			connect.WithSchema(orderServiceGetOrderMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listOrders: connect.NewClient[v1.ListOrdersRequest, v1.ListOrdersResponse](
+			httpClient,
+			baseURL+OrderServiceListOrdersProcedure,
+			connect.WithSchema(orderServiceListOrdersMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		checkOrderOwner: connect.NewClient[v1.CheckOrderOwnerRequest, v1.CheckOrderOwnerResponse](
+			httpClient,
+			baseURL+OrderServiceCheckOrderOwnerProcedure,
+			connect.WithSchema(orderServiceCheckOrderOwnerMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		updateOrderStatus: connect.NewClient[v1.UpdateOrderStatusRequest, v1.UpdateOrderStatusResponse](
+			httpClient,
+			baseURL+OrderServiceUpdateOrderStatusProcedure,
+			connect.WithSchema(orderServiceUpdateOrderStatusMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		cancelOrder: connect.NewClient[v1.CancelOrderRequest, v1.CancelOrderResponse](
+			httpClient,
+			baseURL+OrderServiceCancelOrderProcedure,
+			connect.WithSchema(orderServiceCancelOrderMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		watchOrders: connect.NewClient[v1.WatchOrdersRequest, v1.WatchOrdersResponse](
+			httpClient,
+			baseURL+OrderServiceWatchOrdersProcedure,
+			connect.WithSchema(orderServiceWatchOrdersMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		deleteOrder: connect.NewClient[v1.DeleteOrderRequest, v1.DeleteOrderResponse](
+			httpClient,
+			baseURL+OrderServiceDeleteOrderProcedure,
+			connect.WithSchema(orderServiceDeleteOrderMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		restoreOrder: connect.NewClient[v1.RestoreOrderRequest, v1.RestoreOrderResponse](
+			httpClient,
+			baseURL+OrderServiceRestoreOrderProcedure,
+			connect.WithSchema(orderServiceRestoreOrderMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// orderServiceClient implements OrderServiceClient.
+type orderServiceClient struct {
+	createOrder       *connect.Client[v1.CreateOrderRequest, v1.CreateOrderResponse]
+	getOrder          *connect.Client[v1.GetOrderRequest, v1.GetOrderResponse]
+	listOrders        *connect.Client[v1.ListOrdersRequest, v1.ListOrdersResponse]
+	checkOrderOwner   *connect.Client[v1.CheckOrderOwnerRequest, v1.CheckOrderOwnerResponse]
+	updateOrderStatus *connect.Client[v1.UpdateOrderStatusRequest, v1.UpdateOrderStatusResponse]
+	cancelOrder       *connect.Client[v1.CancelOrderRequest, v1.CancelOrderResponse]
+	watchOrders       *connect.Client[v1.WatchOrdersRequest, v1.WatchOrdersResponse]
+	deleteOrder       *connect.Client[v1.DeleteOrderRequest, v1.DeleteOrderResponse]
+	restoreOrder      *connect.Client[v1.RestoreOrderRequest, v1.RestoreOrderResponse]
+}
+
+// CreateOrder calls order.v1.OrderService.CreateOrder.
+func (c *orderServiceClient) CreateOrder(ctx context.Context, req *connect.Request[v1.CreateOrderRequest]) (*connect.Response[v1.CreateOrderResponse], error) {
+	return c.createOrder.CallUnary(ctx, req)
+}
+
+// GetOrder calls order.v1.OrderService.GetOrder.
+func (c *orderServiceClient) GetOrder(ctx context.Context, req *connect.Request[v1.GetOrderRequest]) (*connect.Response[v1.GetOrderResponse], error) {
+	return c.getOrder.CallUnary(ctx, req)
+}
+
+// ListOrders calls order.v1.OrderService.ListOrders.
+func (c *orderServiceClient) ListOrders(ctx context.Context, req *connect.Request[v1.ListOrdersRequest]) (*connect.Response[v1.ListOrdersResponse], error) {
+	return c.listOrders.CallUnary(ctx, req)
+}
+
+// CheckOrderOwner calls order.v1.OrderService.CheckOrderOwner.
+func (c *orderServiceClient) CheckOrderOwner(ctx context.Context, req *connect.Request[v1.CheckOrderOwnerRequest]) (*connect.Response[v1.CheckOrderOwnerResponse], error) {
+	return c.checkOrderOwner.CallUnary(ctx, req)
+}
+
+// UpdateOrderStatus calls order.v1.OrderService.UpdateOrderStatus.
+func (c *orderServiceClient) UpdateOrderStatus(ctx context.Context, req *connect.Request[v1.UpdateOrderStatusRequest]) (*connect.Response[v1.UpdateOrderStatusResponse], error) {
+	return c.updateOrderStatus.CallUnary(ctx, req)
+}
+
+// CancelOrder calls order.v1.OrderService.CancelOrder.
+func (c *orderServiceClient) CancelOrder(ctx context.Context, req *connect.Request[v1.CancelOrderRequest]) (*connect.Response[v1.CancelOrderResponse], error) {
+	return c.cancelOrder.CallUnary(ctx, req)
+}
+
+// WatchOrders calls order.v1.OrderService.WatchOrders.
+func (c *orderServiceClient) WatchOrders(ctx context.Context, req *connect.Request[v1.WatchOrdersRequest]) (*connect.ServerStreamForClient[v1.WatchOrdersResponse], error) {
+	return c.watchOrders.CallServerStream(ctx, req)
+}
+
+// DeleteOrder calls order.v1.OrderService.DeleteOrder.
+func (c *orderServiceClient) DeleteOrder(ctx context.Context, req *connect.Request[v1.DeleteOrderRequest]) (*connect.Response[v1.DeleteOrderResponse], error) {
+	return c.deleteOrder.CallUnary(ctx, req)
+}
+
+// RestoreOrder calls order.v1.OrderService.RestoreOrder.
+func (c *orderServiceClient) RestoreOrder(ctx context.Context, req *connect.Request[v1.RestoreOrderRequest]) (*connect.Response[v1.RestoreOrderResponse], error) {
+	return c.restoreOrder.CallUnary(ctx, req)
+}
+
+// OrderServiceHandler is an implementation of the order.v1.OrderService service.
+type OrderServiceHandler interface {
+	CreateOrder(context.Context, *connect.Request[v1.CreateOrderRequest]) (*connect.Response[v1.CreateOrderResponse], error)
+	GetOrder(context.Context, *connect.Request[v1.GetOrderRequest]) (*connect.Response[v1.GetOrderResponse], error)
+	ListOrders(context.Context, *connect.Request[v1.ListOrdersRequest]) (*connect.Response[v1.ListOrdersResponse], error)
+	CheckOrderOwner(context.Context, *connect.Request[v1.CheckOrderOwnerRequest]) (*connect.Response[v1.CheckOrderOwnerResponse], error)
+	UpdateOrderStatus(context.Context, *connect.Request[v1.UpdateOrderStatusRequest]) (*connect.Response[v1.UpdateOrderStatusResponse], error)
+	CancelOrder(context.Context, *connect.Request[v1.CancelOrderRequest]) (*connect.Response[v1.CancelOrderResponse], error)
+	WatchOrders(context.Context, *connect.Request[v1.WatchOrdersRequest], *connect.ServerStream[v1.WatchOrdersResponse]) error
+	DeleteOrder(context.Context, *connect.Request[v1.DeleteOrderRequest]) (*connect.Response[v1.DeleteOrderResponse], error)
+	RestoreOrder(context.Context, *connect.Request[v1.RestoreOrderRequest]) (*connect.Response[v1.RestoreOrderResponse], error)
+}
+
+// NewOrderServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewOrderServiceHandler(svc OrderServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	orderServiceCreateOrderHandler := connect.NewUnaryHandler(
+		OrderServiceCreateOrderProcedure,
+		svc.CreateOrder,
+		connect.WithSchema(orderServiceCreateOrderMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	orderServiceGetOrderHandler := connect.NewUnaryHandler(
+		OrderServiceGetOrderProcedure,
+		svc.GetOrder,
+		connect.WithSchema(orderServiceGetOrderMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	orderServiceListOrdersHandler := connect.NewUnaryHandler(
+		OrderServiceListOrdersProcedure,
+		svc.ListOrders,
+		connect.WithSchema(orderServiceListOrdersMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	orderServiceCheckOrderOwnerHandler := connect.NewUnaryHandler(
+		OrderServiceCheckOrderOwnerProcedure,
+		svc.CheckOrderOwner,
+		connect.WithSchema(orderServiceCheckOrderOwnerMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	orderServiceUpdateOrderStatusHandler := connect.NewUnaryHandler(
+		OrderServiceUpdateOrderStatusProcedure,
+		svc.UpdateOrderStatus,
+		connect.WithSchema(orderServiceUpdateOrderStatusMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	orderServiceCancelOrderHandler := connect.NewUnaryHandler(
+		OrderServiceCancelOrderProcedure,
+		svc.CancelOrder,
+		connect.WithSchema(orderServiceCancelOrderMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	orderServiceWatchOrdersHandler := connect.NewServerStreamHandler(
+		OrderServiceWatchOrdersProcedure,
+		svc.WatchOrders,
+		connect.WithSchema(orderServiceWatchOrdersMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	orderServiceDeleteOrderHandler := connect.NewUnaryHandler(
+		OrderServiceDeleteOrderProcedure,
+		svc.DeleteOrder,
+		connect.WithSchema(orderServiceDeleteOrderMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	orderServiceRestoreOrderHandler := connect.NewUnaryHandler(
+		OrderServiceRestoreOrderProcedure,
+		svc.RestoreOrder,
+		connect.WithSchema(orderServiceRestoreOrderMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/order.v1.OrderService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case OrderServiceCreateOrderProcedure:
+			orderServiceCreateOrderHandler.ServeHTTP(w, r)
+		case OrderServiceGetOrderProcedure:
+			orderServiceGetOrderHandler.ServeHTTP(w, r)
+		case OrderServiceListOrdersProcedure:
+			orderServiceListOrdersHandler.ServeHTTP(w, r)
+		case OrderServiceCheckOrderOwnerProcedure:
+			orderServiceCheckOrderOwnerHandler.ServeHTTP(w, r)
+		case OrderServiceUpdateOrderStatusProcedure:
+			orderServiceUpdateOrderStatusHandler.ServeHTTP(w, r)
+		case OrderServiceCancelOrderProcedure:
+			orderServiceCancelOrderHandler.ServeHTTP(w, r)
+		case OrderServiceWatchOrdersProcedure:
+			orderServiceWatchOrdersHandler.ServeHTTP(w, r)
+		case OrderServiceDeleteOrderProcedure:
+			orderServiceDeleteOrderHandler.ServeHTTP(w, r)
+		case OrderServiceRestoreOrderProcedure:
+			orderServiceRestoreOrderHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedOrderServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedOrderServiceHandler struct{}
+
+func (UnimplementedOrderServiceHandler) CreateOrder(context.Context, *connect.Request[v1.CreateOrderRequest]) (*connect.Response[v1.CreateOrderResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("order.v1.OrderService.CreateOrder is not implemented"))
+}
+
+func (UnimplementedOrderServiceHandler) GetOrder(context.Context, *connect.Request[v1.GetOrderRequest]) (*connect.Response[v1.GetOrderResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("order.v1.OrderService.GetOrder is not implemented"))
+}
+
+func (UnimplementedOrderServiceHandler) ListOrders(context.Context, *connect.Request[v1.ListOrdersRequest]) (*connect.Response[v1.ListOrdersResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("order.v1.OrderService.ListOrders is not implemented"))
+}
+
+func (UnimplementedOrderServiceHandler) CheckOrderOwner(context.Context, *connect.Request[v1.CheckOrderOwnerRequest]) (*connect.Response[v1.CheckOrderOwnerResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("order.v1.OrderService.CheckOrderOwner is not implemented"))
+}
+
+func (UnimplementedOrderServiceHandler) UpdateOrderStatus(context.Context, *connect.Request[v1.UpdateOrderStatusRequest]) (*connect.Response[v1.UpdateOrderStatusResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("order.v1.OrderService.UpdateOrderStatus is not implemented"))
+}
+
+func (UnimplementedOrderServiceHandler) CancelOrder(context.Context, *connect.Request[v1.CancelOrderRequest]) (*connect.Response[v1.CancelOrderResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("order.v1.OrderService.CancelOrder is not implemented"))
+}
+
+func (UnimplementedOrderServiceHandler) WatchOrders(context.Context, *connect.Request[v1.WatchOrdersRequest], *connect.ServerStream[v1.WatchOrdersResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("order.v1.OrderService.WatchOrders is not implemented"))
+}
+
+func (UnimplementedOrderServiceHandler) DeleteOrder(context.Context, *connect.Request[v1.DeleteOrderRequest]) (*connect.Response[v1.DeleteOrderResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("order.v1.OrderService.DeleteOrder is not implemented"))
+}
+
+func (UnimplementedOrderServiceHandler) RestoreOrder(context.Context, *connect.Request[v1.RestoreOrderRequest]) (*connect.Response[v1.RestoreOrderResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("order.v1.OrderService.RestoreOrder is not implemented"))
+}
+
+// WebhookServiceClient is a client for the order.v1.WebhookService service.
+type WebhookServiceClient interface {
+	CreateWebhookSubscription(context.Context, *connect.Request[v1.CreateWebhookSubscriptionRequest]) (*connect.Response[v1.CreateWebhookSubscriptionResponse], error)
+	ListWebhookSubscriptions(context.Context, *connect.Request[v1.ListWebhookSubscriptionsRequest]) (*connect.Response[v1.ListWebhookSubscriptionsResponse], error)
+	DeleteWebhookSubscription(context.Context, *connect.Request[v1.DeleteWebhookSubscriptionRequest]) (*connect.Response[v1.DeleteWebhookSubscriptionResponse], error)
+	ListWebhookDeliveries(context.Context, *connect.Request[v1.ListWebhookDeliveriesRequest]) (*connect.Response[v1.ListWebhookDeliveriesResponse], error)
+	ReplayWebhookDelivery(context.Context, *connect.Request[v1.ReplayWebhookDeliveryRequest]) (*connect.Response[v1.ReplayWebhookDeliveryResponse], error)
+}
+
+// NewWebhookServiceClient constructs a client for the order.v1.WebhookService service. By default,
+// it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and
+// sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC()
+// or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewWebhookServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) WebhookServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &webhookServiceClient{
+		createWebhookSubscription: connect.NewClient[v1.CreateWebhookSubscriptionRequest, v1.CreateWebhookSubscriptionResponse](
+			httpClient,
+			baseURL+WebhookServiceCreateWebhookSubscriptionProcedure,
+			connect.WithSchema(webhookServiceCreateWebhookSubscriptionMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listWebhookSubscriptions: connect.NewClient[v1.ListWebhookSubscriptionsRequest, v1.ListWebhookSubscriptionsResponse](
+			httpClient,
+			baseURL+WebhookServiceListWebhookSubscriptionsProcedure,
+			connect.WithSchema(webhookServiceListWebhookSubscriptionsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		deleteWebhookSubscription: connect.NewClient[v1.DeleteWebhookSubscriptionRequest, v1.DeleteWebhookSubscriptionResponse](
+			httpClient,
+			baseURL+WebhookServiceDeleteWebhookSubscriptionProcedure,
+			connect.WithSchema(webhookServiceDeleteWebhookSubscriptionMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listWebhookDeliveries: connect.NewClient[v1.ListWebhookDeliveriesRequest, v1.ListWebhookDeliveriesResponse](
+			httpClient,
+			baseURL+WebhookServiceListWebhookDeliveriesProcedure,
+			connect.WithSchema(webhookServiceListWebhookDeliveriesMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		replayWebhookDelivery: connect.NewClient[v1.ReplayWebhookDeliveryRequest, v1.ReplayWebhookDeliveryResponse](
+			httpClient,
+			baseURL+WebhookServiceReplayWebhookDeliveryProcedure,
+			connect.WithSchema(webhookServiceReplayWebhookDeliveryMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// webhookServiceClient implements WebhookServiceClient.
+type webhookServiceClient struct {
+	createWebhookSubscription *connect.Client[v1.CreateWebhookSubscriptionRequest, v1.CreateWebhookSubscriptionResponse]
+	listWebhookSubscriptions  *connect.Client[v1.ListWebhookSubscriptionsRequest, v1.ListWebhookSubscriptionsResponse]
+	deleteWebhookSubscription *connect.Client[v1.DeleteWebhookSubscriptionRequest, v1.DeleteWebhookSubscriptionResponse]
+	listWebhookDeliveries     *connect.Client[v1.ListWebhookDeliveriesRequest, v1.ListWebhookDeliveriesResponse]
+	replayWebhookDelivery     *connect.Client[v1.ReplayWebhookDeliveryRequest, v1.ReplayWebhookDeliveryResponse]
+}
+
+// CreateWebhookSubscription calls order.v1.WebhookService.CreateWebhookSubscription.
+func (c *webhookServiceClient) CreateWebhookSubscription(ctx context.Context, req *connect.Request[v1.CreateWebhookSubscriptionRequest]) (*connect.Response[v1.CreateWebhookSubscriptionResponse], error) {
+	return c.createWebhookSubscription.CallUnary(ctx, req)
+}
+
+// ListWebhookSubscriptions calls order.v1.WebhookService.ListWebhookSubscriptions.
+func (c *webhookServiceClient) ListWebhookSubscriptions(ctx context.Context, req *connect.Request[v1.ListWebhookSubscriptionsRequest]) (*connect.Response[v1.ListWebhookSubscriptionsResponse], error) {
+	return c.listWebhookSubscriptions.CallUnary(ctx, req)
+}
+
+// DeleteWebhookSubscription calls order.v1.WebhookService.DeleteWebhookSubscription.
+func (c *webhookServiceClient) DeleteWebhookSubscription(ctx context.Context, req *connect.Request[v1.DeleteWebhookSubscriptionRequest]) (*connect.Response[v1.DeleteWebhookSubscriptionResponse], error) {
+	return c.deleteWebhookSubscription.CallUnary(ctx, req)
+}
+
+// ListWebhookDeliveries calls order.v1.WebhookService.ListWebhookDeliveries.
+func (c *webhookServiceClient) ListWebhookDeliveries(ctx context.Context, req *connect.Request[v1.ListWebhookDeliveriesRequest]) (*connect.Response[v1.ListWebhookDeliveriesResponse], error) {
+	return c.listWebhookDeliveries.CallUnary(ctx, req)
+}
+
+// ReplayWebhookDelivery calls order.v1.WebhookService.ReplayWebhookDelivery.
+func (c *webhookServiceClient) ReplayWebhookDelivery(ctx context.Context, req *connect.Request[v1.ReplayWebhookDeliveryRequest]) (*connect.Response[v1.ReplayWebhookDeliveryResponse], error) {
+	return c.replayWebhookDelivery.CallUnary(ctx, req)
+}
+
+// WebhookServiceHandler is an implementation of the order.v1.WebhookService service.
+type WebhookServiceHandler interface {
+	CreateWebhookSubscription(context.Context, *connect.Request[v1.CreateWebhookSubscriptionRequest]) (*connect.Response[v1.CreateWebhookSubscriptionResponse], error)
+	ListWebhookSubscriptions(context.Context, *connect.Request[v1.ListWebhookSubscriptionsRequest]) (*connect.Response[v1.ListWebhookSubscriptionsResponse], error)
+	DeleteWebhookSubscription(context.Context, *connect.Request[v1.DeleteWebhookSubscriptionRequest]) (*connect.Response[v1.DeleteWebhookSubscriptionResponse], error)
+	ListWebhookDeliveries(context.Context, *connect.Request[v1.ListWebhookDeliveriesRequest]) (*connect.Response[v1.ListWebhookDeliveriesResponse], error)
+	ReplayWebhookDelivery(context.Context, *connect.Request[v1.ReplayWebhookDeliveryRequest]) (*connect.Response[v1.ReplayWebhookDeliveryResponse], error)
+}
+
+// NewWebhookServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewWebhookServiceHandler(svc WebhookServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	webhookServiceCreateWebhookSubscriptionHandler := connect.NewUnaryHandler(
+		WebhookServiceCreateWebhookSubscriptionProcedure,
+		svc.CreateWebhookSubscription,
+		connect.WithSchema(webhookServiceCreateWebhookSubscriptionMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	webhookServiceListWebhookSubscriptionsHandler := connect.NewUnaryHandler(
+		WebhookServiceListWebhookSubscriptionsProcedure,
+		svc.ListWebhookSubscriptions,
+		connect.WithSchema(webhookServiceListWebhookSubscriptionsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	webhookServiceDeleteWebhookSubscriptionHandler := connect.NewUnaryHandler(
+		WebhookServiceDeleteWebhookSubscriptionProcedure,
+		svc.DeleteWebhookSubscription,
+		connect.WithSchema(webhookServiceDeleteWebhookSubscriptionMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	webhookServiceListWebhookDeliveriesHandler := connect.NewUnaryHandler(
+		WebhookServiceListWebhookDeliveriesProcedure,
+		svc.ListWebhookDeliveries,
+		connect.WithSchema(webhookServiceListWebhookDeliveriesMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	webhookServiceReplayWebhookDeliveryHandler := connect.NewUnaryHandler(
+		WebhookServiceReplayWebhookDeliveryProcedure,
+		svc.ReplayWebhookDelivery,
+		connect.WithSchema(webhookServiceReplayWebhookDeliveryMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/order.v1.WebhookService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case WebhookServiceCreateWebhookSubscriptionProcedure:
+			webhookServiceCreateWebhookSubscriptionHandler.ServeHTTP(w, r)
+		case WebhookServiceListWebhookSubscriptionsProcedure:
+			webhookServiceListWebhookSubscriptionsHandler.ServeHTTP(w, r)
+		case WebhookServiceDeleteWebhookSubscriptionProcedure:
+			webhookServiceDeleteWebhookSubscriptionHandler.ServeHTTP(w, r)
+		case WebhookServiceListWebhookDeliveriesProcedure:
+			webhookServiceListWebhookDeliveriesHandler.ServeHTTP(w, r)
+		case WebhookServiceReplayWebhookDeliveryProcedure:
+			webhookServiceReplayWebhookDeliveryHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedWebhookServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedWebhookServiceHandler struct{}
+
+func (UnimplementedWebhookServiceHandler) CreateWebhookSubscription(context.Context, *connect.Request[v1.CreateWebhookSubscriptionRequest]) (*connect.Response[v1.CreateWebhookSubscriptionResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("order.v1.WebhookService.CreateWebhookSubscription is not implemented"))
+}
+
+func (UnimplementedWebhookServiceHandler) ListWebhookSubscriptions(context.Context, *connect.Request[v1.ListWebhookSubscriptionsRequest]) (*connect.Response[v1.ListWebhookSubscriptionsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("order.v1.WebhookService.ListWebhookSubscriptions is not implemented"))
+}
+
+func (UnimplementedWebhookServiceHandler) DeleteWebhookSubscription(context.Context, *connect.Request[v1.DeleteWebhookSubscriptionRequest]) (*connect.Response[v1.DeleteWebhookSubscriptionResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("order.v1.WebhookService.DeleteWebhookSubscription is not implemented"))
+}
+
+func (UnimplementedWebhookServiceHandler) ListWebhookDeliveries(context.Context, *connect.Request[v1.ListWebhookDeliveriesRequest]) (*connect.Response[v1.ListWebhookDeliveriesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("order.v1.WebhookService.ListWebhookDeliveries is not implemented"))
+}
+
+func (UnimplementedWebhookServiceHandler) ReplayWebhookDelivery(context.Context, *connect.Request[v1.ReplayWebhookDeliveryRequest]) (*connect.Response[v1.ReplayWebhookDeliveryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("order.v1.WebhookService.ReplayWebhookDelivery is not implemented"))
+}
@@ -0,0 +1,10 @@
+module github.com/demo/contracts
+
+go 1.23
+
+require (
+	connectrpc.com/connect v1.16.2
+	google.golang.org/protobuf v1.36.10
+)
+
+require buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go v1.36.10-20250912141014-52f32327d4b0.1
@@ -0,0 +1,90 @@
+// Package authtest mints tokens accepted by auth.Verifier for tests.
+package authtest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Issuer signs tokens with an HMAC secret or an RSA key.
+type Issuer struct {
+	method jwt.SigningMethod
+	key    any
+	kid    string
+	public *rsa.PublicKey
+}
+
+// NewHMACIssuer signs HS256 tokens with secret, see auth.Config.HMACSecret.
+func NewHMACIssuer(secret string) *Issuer {
+	return &Issuer{method: jwt.SigningMethodHS256, key: []byte(secret)}
+}
+
+// NewRSAIssuer signs RS256 tokens with a new RSA key named kid, see JWKS.
+func NewRSAIssuer(kid string) *Issuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return &Issuer{method: jwt.SigningMethodRS256, key: key, kid: kid, public: &key.PublicKey}
+}
+
+// Token returns a token for subject with roles, valid for an hour.
+func (i *Issuer) Token(subject string, roles ...string) string {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub": subject,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	if len(roles) > 0 {
+		claims["roles"] = roles
+	}
+	return i.Sign(claims)
+}
+
+// Sign returns a token with claims as they are.
+func (i *Issuer) Sign(claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(i.method, claims)
+	if i.kid != "" {
+		token.Header["kid"] = i.kid
+	}
+	signed, err := token.SignedString(i.key)
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
+
+// JWKS returns a JWKS document with the public keys of RSA issuers.
+func JWKS(issuers ...*Issuer) []byte {
+	type jwk struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	}
+	var keys []jwk
+	for _, issuer := range issuers {
+		keys = append(keys, jwk{
+			Kty: "RSA",
+			Kid: issuer.kid,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(issuer.public.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(issuer.public.E)).Bytes()),
+		})
+	}
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
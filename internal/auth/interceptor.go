@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+)
+
+var errMissingToken = errors.New("missing bearer token")
+
+// Interceptor requires a valid bearer token in the Authorization header of
+// every RPC of the handlers it is added to, and puts its principal in the
+// context. The health endpoints are served without it.
+type Interceptor struct {
+	verifier *Verifier
+}
+
+var _ connect.Interceptor = (*Interceptor)(nil)
+
+func NewInterceptor(verifier *Verifier) *Interceptor {
+	return &Interceptor{verifier: verifier}
+}
+
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		principal, err := i.authenticate(ctx, req.Header())
+		if err != nil {
+			return nil, err
+		}
+		return next(WithPrincipal(ctx, principal), req)
+	}
+}
+
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		principal, err := i.authenticate(ctx, conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+		return next(WithPrincipal(ctx, principal), conn)
+	}
+}
+
+func (i *Interceptor) authenticate(ctx context.Context, header http.Header) (*Principal, error) {
+	scheme, token, _ := strings.Cut(header.Get("Authorization"), " ")
+	if !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, unauthenticated(errMissingToken)
+	}
+	principal, err := i.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, unauthenticated(fmt.Errorf("invalid bearer token: %w", err))
+	}
+	return principal, nil
+}
+
+func unauthenticated(err error) *connect.Error {
+	connectErr := connect.NewError(connect.CodeUnauthenticated, err)
+	connectErr.Meta().Set("WWW-Authenticate", "Bearer")
+	return connectErr
+}
+
+// DisabledInterceptor stands in for Interceptor when authentication is
+// disabled. It marks the context of every RPC with WithDisabled.
+type DisabledInterceptor struct{}
+
+var _ connect.Interceptor = DisabledInterceptor{}
+
+func NewDisabledInterceptor() DisabledInterceptor {
+	return DisabledInterceptor{}
+}
+
+func (DisabledInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		return next(WithDisabled(ctx), req)
+	}
+}
+
+func (DisabledInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (DisabledInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return next(WithDisabled(ctx), conn)
+	}
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// minKeyRefreshInterval limits the refreshes caused by tokens with an unknown
+// key ID, so that such tokens cannot be used to hammer the key source.
+const minKeyRefreshInterval = 10 * time.Second
+
+// maxJWKSSize bounds the JWKS documents read.
+const maxJWKSSize = 1 << 20
+
+// keySet holds the RSA public keys of a JWKS document by key ID. It is
+// refreshed periodically and when a token names an unknown key, so that keys
+// can be rotated without a restart.
+type keySet struct {
+	source string
+	load   func(ctx context.Context) ([]byte, error)
+
+	// refreshMu serializes refreshes and guards attempted.
+	refreshMu sync.Mutex
+	attempted time.Time
+
+	mu   sync.RWMutex
+	keys map[string]*rsa.PublicKey
+}
+
+func newFileKeySet(path string) *keySet {
+	return &keySet{
+		source: path,
+		load: func(context.Context) ([]byte, error) {
+			return os.ReadFile(path)
+		},
+	}
+}
+
+func newURLKeySet(url string, client *http.Client) *keySet {
+	return &keySet{
+		source: url,
+		load: func(ctx context.Context) ([]byte, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("unexpected status %s", resp.Status)
+			}
+			return io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+		},
+	}
+}
+
+// refresh reloads the keys. The previous keys are kept if that fails.
+func (k *keySet) refresh(ctx context.Context) error {
+	k.refreshMu.Lock()
+	defer k.refreshMu.Unlock()
+	return k.reload(ctx)
+}
+
+// refreshStale reloads the keys unless that was attempted within minKeyRefreshInterval.
+func (k *keySet) refreshStale(ctx context.Context) error {
+	k.refreshMu.Lock()
+	defer k.refreshMu.Unlock()
+	if time.Since(k.attempted) < minKeyRefreshInterval {
+		return nil
+	}
+	return k.reload(ctx)
+}
+
+func (k *keySet) reload(ctx context.Context) error {
+	k.attempted = time.Now()
+	data, err := k.load(ctx)
+	if err != nil {
+		return fmt.Errorf("load JWKS from %s: %w", k.source, err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return fmt.Errorf("load JWKS from %s: %w", k.source, err)
+	}
+	k.mu.Lock()
+	k.keys = keys
+	k.mu.Unlock()
+	return nil
+}
+
+func (k *keySet) lookup(kid string) (*rsa.PublicKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+// key returns the key with ID kid, refreshing the keys if it is unknown.
+func (k *keySet) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	if key, ok := k.lookup(kid); ok {
+		return key, nil
+	}
+	if err := k.refreshStale(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := k.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key ID %q", kid)
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// parseJWKS returns the RSA signing keys of a JWKS document by key ID. Keys of
+// other types and uses are skipped.
+func parseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, key := range set.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") || (key.Alg != "" && key.Alg != "RS256") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: invalid modulus: %w", key.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: invalid exponent: %w", key.Kid, err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("key %q: invalid exponent", key.Kid)
+		}
+		keys[key.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no RSA signing keys")
+	}
+	return keys, nil
+}
@@ -0,0 +1,65 @@
+// Package auth authenticates the callers of the Connect services with bearer
+// JWTs signed with HS256 or RS256.
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// Roles of Principal that grant access beyond the own orders of the user.
+const (
+	// RoleAdmin manages webhooks and may act on every order, including deleted ones.
+	RoleAdmin = "admin"
+	// RoleSupport may act on the orders of every user.
+	RoleSupport = "support"
+)
+
+// Principal is the authenticated caller of an RPC.
+type Principal struct {
+	// Subject is the sub claim of the token, the ID of the user.
+	Subject string
+	// Roles is the roles claim of the token.
+	Roles []string
+}
+
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+type (
+	contextKey         struct{}
+	disabledContextKey struct{}
+)
+
+// WithPrincipal returns a copy of ctx carrying principal.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal set by Interceptor, if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(contextKey{}).(*Principal)
+	return principal, ok
+}
+
+// CallerHasRole reports whether the caller of ctx is authenticated and has role.
+func CallerHasRole(ctx context.Context, role string) bool {
+	principal, ok := PrincipalFromContext(ctx)
+	return ok && principal.HasRole(role)
+}
+
+// WithDisabled returns a copy of ctx marked as served with authentication
+// disabled, see DisabledInterceptor.
+func WithDisabled(ctx context.Context) context.Context {
+	return context.WithValue(ctx, disabledContextKey{}, true)
+}
+
+// Disabled reports whether ctx is served with authentication disabled. Callers
+// without a principal are trusted only then: a context that is neither marked
+// nor carries a principal belongs to a handler mounted without authentication
+// by mistake.
+func Disabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(disabledContextKey{}).(bool)
+	return disabled
+}
@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallerHasRole(t *testing.T) {
+	admin := WithPrincipal(context.Background(), &Principal{Subject: "admin-1", Roles: []string{RoleAdmin}})
+	assert.True(t, CallerHasRole(admin, RoleAdmin))
+	assert.False(t, CallerHasRole(admin, RoleSupport))
+
+	assert.False(t, CallerHasRole(context.Background(), RoleAdmin), "Callers without a principal should have no role")
+	assert.False(t, CallerHasRole(WithDisabled(context.Background()), RoleAdmin),
+		"Callers should have no role when authentication is disabled")
+}
+
+func TestDisabled(t *testing.T) {
+	assert.False(t, Disabled(context.Background()))
+	assert.True(t, Disabled(WithDisabled(context.Background())))
+}
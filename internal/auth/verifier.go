@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type Config struct {
+	// HMACSecret verifies HS256 tokens. Empty rejects them.
+	HMACSecret string
+	// JWKSFile or JWKSURL holds the RSA keys verifying RS256 tokens, chosen
+	// by the kid header. Without either RS256 tokens are rejected.
+	JWKSFile string
+	JWKSURL  string
+	// JWKSRefreshInterval is how often the JWKS is reloaded to pick up
+	// rotated keys. Tokens signed with an unknown key trigger a reload too.
+	JWKSRefreshInterval time.Duration
+	// Issuer and Audience, when set, must match the iss and aud claims.
+	Issuer   string
+	Audience string
+	// Leeway tolerates clock skew when checking exp, nbf and iat.
+	Leeway time.Duration
+}
+
+// claims are the claims read from tokens.
+type claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles"`
+}
+
+// Verifier checks bearer tokens and returns their principals.
+type Verifier struct {
+	hmacSecret      []byte
+	keys            *keySet
+	refreshInterval time.Duration
+	parser          *jwt.Parser
+}
+
+// NewVerifier creates a verifier. The JWKS, if any, is loaded right away so
+// that a misconfiguration is reported at startup.
+func NewVerifier(ctx context.Context, config Config) (*Verifier, error) {
+	v := &Verifier{refreshInterval: config.JWKSRefreshInterval}
+	var methods []string
+	if config.HMACSecret != "" {
+		v.hmacSecret = []byte(config.HMACSecret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	switch {
+	case config.JWKSFile != "":
+		v.keys = newFileKeySet(config.JWKSFile)
+	case config.JWKSURL != "":
+		v.keys = newURLKeySet(config.JWKSURL, &http.Client{Timeout: 10 * time.Second})
+	}
+	if v.keys != nil {
+		if err := v.keys.refresh(ctx); err != nil {
+			return nil, err
+		}
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("no HMAC secret or JWKS to verify tokens with")
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(config.Leeway),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+	v.parser = jwt.NewParser(options...)
+	return v, nil
+}
+
+// Verify checks the signature and the claims of token.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Principal, error) {
+	var c claims
+	if _, err := v.parser.ParseWithClaims(token, &c, func(t *jwt.Token) (any, error) {
+		// The parser has already checked that the method is one of the configured ones.
+		if t.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+			return v.hmacSecret, nil
+		}
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.key(ctx, kid)
+	}); err != nil {
+		return nil, err
+	}
+	if c.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+	return &Principal{Subject: c.Subject, Roles: c.Roles}, nil
+}
+
+// Run reloads the JWKS every refresh interval until ctx is done. Without a
+// JWKS it only waits for ctx.
+func (v *Verifier) Run(ctx context.Context) {
+	if v.keys == nil {
+		<-ctx.Done()
+		return
+	}
+	ticker := time.NewTicker(v.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := v.keys.refresh(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("Failed to refresh JWKS, keeping the previous keys", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/demo/order/internal/auth/authtest"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func writeJWKS(t *testing.T, path string, issuers ...*authtest.Issuer) {
+	require.NoError(t, os.WriteFile(path, authtest.JWKS(issuers...), 0o600))
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	hmac := authtest.NewHMACIssuer(testSecret)
+	rsaIssuer := authtest.NewRSAIssuer("key-1")
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, rsaIssuer)
+	verifier, err := NewVerifier(context.Background(), Config{
+		HMACSecret: testSecret,
+		JWKSFile:   path,
+		Issuer:     "https://auth.example.com",
+		Leeway:     time.Second,
+	})
+	require.NoError(t, err)
+
+	claims := func(modify func(jwt.MapClaims)) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"sub":   "user-1",
+			"iss":   "https://auth.example.com",
+			"iat":   time.Now().Unix(),
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"roles": []string{"admin"},
+		}
+		if modify != nil {
+			modify(c)
+		}
+		return c
+	}
+
+	testCases := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{
+			name:  "HS256",
+			token: hmac.Sign(claims(nil)),
+		},
+		{
+			name:  "RS256",
+			token: rsaIssuer.Sign(claims(nil)),
+		},
+		{
+			name:    "expired",
+			token:   hmac.Sign(claims(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() })),
+			wantErr: "token is expired",
+		},
+		{
+			name:    "without expiry",
+			token:   hmac.Sign(claims(func(c jwt.MapClaims) { delete(c, "exp") })),
+			wantErr: "exp claim is required",
+		},
+		{
+			name:    "wrong issuer",
+			token:   hmac.Sign(claims(func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" })),
+			wantErr: "token has invalid issuer",
+		},
+		{
+			name:    "without subject",
+			token:   hmac.Sign(claims(func(c jwt.MapClaims) { delete(c, "sub") })),
+			wantErr: "token has no subject",
+		},
+		{
+			name:    "wrong secret",
+			token:   authtest.NewHMACIssuer("another secret of at least 32 bytes").Sign(claims(nil)),
+			wantErr: "signature is invalid",
+		},
+		{
+			name:    "unknown key",
+			token:   authtest.NewRSAIssuer("key-2").Sign(claims(nil)),
+			wantErr: `unknown key ID "key-2"`,
+		},
+		{
+			name:    "unsigned",
+			token:   "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0.eyJzdWIiOiJ1c2VyLTEifQ.",
+			wantErr: "signing method none is invalid",
+		},
+		{
+			name:    "malformed",
+			token:   "not-a-token",
+			wantErr: "token is malformed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			principal, err := verifier.Verify(context.Background(), tc.token)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &Principal{Subject: "user-1", Roles: []string{"admin"}}, principal)
+			assert.True(t, principal.HasRole("admin"))
+		})
+	}
+}
+
+func TestVerifier_OnlyConfiguredMethods(t *testing.T) {
+	verifier, err := NewVerifier(context.Background(), Config{HMACSecret: testSecret})
+	require.NoError(t, err)
+
+	_, err = verifier.Verify(context.Background(), authtest.NewRSAIssuer("key-1").Token("user-1"))
+	require.ErrorContains(t, err, "signing method RS256 is invalid")
+
+	_, err = NewVerifier(context.Background(), Config{})
+	require.EqualError(t, err, "no HMAC secret or JWKS to verify tokens with")
+}
+
+func TestVerifier_KeyRotation(t *testing.T) {
+	oldKey := authtest.NewRSAIssuer("old")
+	newKey := authtest.NewRSAIssuer("new")
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, oldKey)
+	verifier, err := NewVerifier(context.Background(), Config{JWKSFile: path, JWKSRefreshInterval: time.Hour})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = verifier.Verify(ctx, oldKey.Token("user-1"))
+	require.NoError(t, err)
+
+	writeJWKS(t, path, oldKey, newKey)
+	_, err = verifier.Verify(ctx, newKey.Token("user-1"))
+	require.ErrorContains(t, err, `unknown key ID "new"`, "Reloads should be rate limited")
+
+	verifier.keys.attempted = time.Time{}
+	_, err = verifier.Verify(ctx, newKey.Token("user-1"))
+	require.NoError(t, err, "An unknown key should trigger a reload")
+
+	writeJWKS(t, path, newKey)
+	require.NoError(t, verifier.keys.refresh(ctx))
+	_, err = verifier.Verify(ctx, oldKey.Token("user-1"))
+	require.ErrorContains(t, err, `unknown key ID "old"`, "Removed keys should be rejected")
+}
+
+func TestVerifier_JWKSURL(t *testing.T) {
+	issuer := authtest.NewRSAIssuer("key-1")
+	var requests atomic.Int32
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write(authtest.JWKS(issuer))
+	}))
+	defer server.Close()
+
+	verifier, err := NewVerifier(context.Background(), Config{JWKSURL: server.URL, JWKSRefreshInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		verifier.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return requests.Load() >= 3 }, time.Second, 5*time.Millisecond,
+		"The JWKS should be reloaded periodically")
+
+	failing.Store(true)
+	require.Eventually(t, func() bool { return requests.Load() >= 5 }, time.Second, 5*time.Millisecond)
+	_, err = verifier.Verify(context.Background(), issuer.Token("user-1"))
+	require.NoError(t, err, "The previous keys should be kept when a reload fails")
+
+	cancel()
+	<-done
+
+	_, err = NewVerifier(context.Background(), Config{JWKSURL: server.URL})
+	require.ErrorContains(t, err, "unexpected status 503 Service Unavailable")
+}
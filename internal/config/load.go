@@ -0,0 +1,228 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envPrefix prefixes the environment variables derived from yaml paths.
+const envPrefix = "ORDER_"
+
+const redacted = "REDACTED"
+
+// field is a setting of Config.
+type field struct {
+	// path is the yaml path, e.g. "server.addr". It is also the flag name.
+	path string
+	// env is the environment variable, ORDER_ and the upper-cased path with
+	// dots replaced by underscores unless set with the env tag.
+	env    string
+	secret bool
+	// index locates the field in Config for reflect.Value.FieldByIndex.
+	index []int
+}
+
+// fields lists the settings of Config.
+func fields() []field {
+	var result []field
+	var walk func(t reflect.Type, prefix string, index []int)
+	walk = func(t reflect.Type, prefix string, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			path := prefix + f.Tag.Get("yaml")
+			fieldIndex := append(append([]int(nil), index...), i)
+			if f.Type.Kind() == reflect.Struct {
+				walk(f.Type, path+".", fieldIndex)
+				continue
+			}
+
+			env := f.Tag.Get("env")
+			if env == "" {
+				env = envPrefix + strings.ToUpper(strings.ReplaceAll(path, ".", "_"))
+			}
+			result = append(result, field{
+				path:   path,
+				env:    env,
+				secret: f.Tag.Get("secret") == "true",
+				index:  fieldIndex,
+			})
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "", nil)
+	return result
+}
+
+// set parses value into the field of c.
+func (f field) set(c *Config, value string) error {
+	v := reflect.ValueOf(c).Elem().FieldByIndex(f.index)
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", value)
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(value)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Float64:
+		x, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		v.SetFloat(x)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		v.SetBool(b)
+	default:
+		panic(fmt.Sprintf("config: unsupported type %s of %s", v.Type(), f.path))
+	}
+	return nil
+}
+
+// flagValue collects the value of a flag, which is applied after the file and the environment.
+type flagValue struct {
+	value string
+}
+
+func (v *flagValue) String() string {
+	return v.value
+}
+
+func (v *flagValue) Set(value string) error {
+	v.value = value
+	return nil
+}
+
+// Load builds the configuration from, in increasing order of precedence:
+//
+//   - Default;
+//   - the YAML file given with the -config flag or the CONFIG_FILE environment variable;
+//   - non-empty environment variables, e.g. ORDER_SERVER_ADDR for server.addr;
+//   - flags named after the yaml paths, e.g. -server.addr.
+//
+// Load registers its flags on fs and parses args with it, so callers can add
+// their own flags first. The result is validated.
+func Load(fs *flag.FlagSet, args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	configFile := fs.String("config", "", "path to the YAML configuration file (env CONFIG_FILE)")
+	settings := fields()
+	flagValues := make([]flagValue, len(settings))
+	for i, f := range settings {
+		fs.Var(&flagValues[i], f.path, fmt.Sprintf("overrides %s (env %s)", f.path, f.env))
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	config := Default()
+
+	path := *configFile
+	if path == "" {
+		path, _ = lookupEnv("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := config.readFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	for _, f := range settings {
+		// Empty variables are ignored, like unset ones.
+		if value, ok := lookupEnv(f.env); ok && value != "" {
+			if err := f.set(&config, value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+			}
+		}
+	}
+	setFlags := make(map[string]bool)
+	fs.Visit(func(fl *flag.Flag) {
+		setFlags[fl.Name] = true
+	})
+	for i, f := range settings {
+		if setFlags[f.path] {
+			if err := f.set(&config, flagValues[i].value); err != nil {
+				errs = append(errs, fmt.Errorf("-%s: %w", f.path, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return &config, nil
+}
+
+// readFile applies the settings of a YAML file. Unknown keys are rejected to catch typos.
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Print writes the configuration as YAML with the secrets redacted.
+func (c *Config) Print(w io.Writer) error {
+	redactedConfig := *c
+	v := reflect.ValueOf(&redactedConfig).Elem()
+	for _, f := range fields() {
+		if f.secret {
+			field := v.FieldByIndex(f.index)
+			field.SetString(redact(field.String()))
+		}
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&redactedConfig); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// redact hides a secret. Only the password of a URL is hidden, the rest helps to spot mistakes.
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	u, err := url.Parse(secret)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return redacted
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redacted)
+	}
+	if query := u.Query(); query.Has("password") {
+		query.Set("password", redacted)
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
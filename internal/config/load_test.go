@@ -0,0 +1,307 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/demo/order/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+}
+
+func load(args []string, vars map[string]string) (*Config, error) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return Load(fs, args, env(vars))
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	config, err := load(nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, Default(), *config)
+	assert.Equal(t, ":8081", config.Server.Addr)
+	assert.Equal(t, "postgres", config.Store.Kind)
+	assert.True(t, config.Features.Webhooks)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, `
+server:
+  addr: ":9000"
+  shutdown_timeout: 10s
+store:
+  kind: memory
+  max_open_conns: 50
+webhooks:
+  concurrency: 4
+`)
+
+	testCases := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want func(*Config)
+	}{
+		{
+			name: "file overrides defaults",
+			args: []string{"-config", path},
+			want: func(c *Config) {
+				c.Server.Addr = ":9000"
+				c.Server.ShutdownTimeout = 10 * time.Second
+				c.Store.Kind = "memory"
+				c.Store.MaxOpenConns = 50
+				c.Webhooks.Concurrency = 4
+			},
+		},
+		{
+			name: "environment overrides file",
+			env: map[string]string{
+				"CONFIG_FILE":                path,
+				"ORDER_SERVER_ADDR":          ":9100",
+				"SHUTDOWN_TIMEOUT":           "5s",
+				"ORDER_FEATURES_WEBHOOKS":    "false",
+				"ORDER_WEBHOOKS_CONCURRENCY": "",
+				"ORDER_TRACING_SAMPLE_RATIO": "0.25",
+				"ORDER_LOG_FORMAT":           "text",
+			},
+			want: func(c *Config) {
+				c.Server.Addr = ":9100"
+				c.Server.ShutdownTimeout = 5 * time.Second
+				c.Store.Kind = "memory"
+				c.Store.MaxOpenConns = 50
+				c.Webhooks.Concurrency = 4
+				c.Features.Webhooks = false
+				c.Tracing.SampleRatio = 0.25
+				c.Log.Format = "text"
+			},
+		},
+		{
+			name: "flags override environment",
+			args: []string{"-config", path, "-server.addr", ":9200", "--store.max_open_conns=60"},
+			env: map[string]string{
+				"ORDER_SERVER_ADDR": ":9100",
+				"STORE":             "postgres",
+				"DATABASE_URL":      "postgres://localhost/orders",
+			},
+			want: func(c *Config) {
+				c.Server.Addr = ":9200"
+				c.Server.ShutdownTimeout = 10 * time.Second
+				c.Store.Kind = "postgres"
+				c.Store.DatabaseURL = "postgres://localhost/orders"
+				c.Store.MaxOpenConns = 60
+				c.Webhooks.Concurrency = 4
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := load(tc.args, tc.env)
+			require.NoError(t, err)
+
+			want := Default()
+			tc.want(&want)
+			assert.Equal(t, want, *config)
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		file    string
+		args    []string
+		env     map[string]string
+		wantErr []string
+	}{
+		{
+			name:    "unknown key in file",
+			file:    "server:\n  adr: \":9000\"\n",
+			wantErr: []string{"field adr not found"},
+		},
+		{
+			name:    "invalid value in environment",
+			env:     map[string]string{"ORDER_OUTBOX_LEASE": "soon", "ORDER_WEBHOOKS_MAX_ATTEMPTS": "many"},
+			wantErr: []string{`ORDER_OUTBOX_LEASE: invalid duration "soon"`, `ORDER_WEBHOOKS_MAX_ATTEMPTS: invalid integer "many"`},
+		},
+		{
+			name:    "invalid flag value",
+			args:    []string{"-features.webhooks=maybe"},
+			wantErr: []string{`-features.webhooks: invalid boolean "maybe"`},
+		},
+		{
+			name:    "invalid number",
+			args:    []string{"-tracing.sample_ratio=half"},
+			wantErr: []string{`-tracing.sample_ratio: invalid number "half"`},
+		},
+		{
+			name:    "unknown flag",
+			args:    []string{"-server.port=1"},
+			wantErr: []string{"flag provided but not defined: -server.port"},
+		},
+		{
+			name: "all invalid settings are reported",
+			args: []string{
+				"-store.kind=sqlite", "-outbox.batch_size=0", "-server.tls.cert_file=cert.pem",
+				"-tracing.exporter=jaeger", "-tracing.sample_ratio=2", "-log.format=xml", "-log.level=trace",
+			},
+			wantErr: []string{
+				`store.kind must be postgres or memory, got "sqlite"`,
+				"outbox.batch_size must be positive",
+				"server.tls.key_file must be set with server.tls.cert_file",
+				`tracing.exporter must be none, stdout or otlp, got "jaeger"`,
+				"tracing.sample_ratio must be between 0 and 1",
+				`log.format must be json or text, got "xml"`,
+				`log.level must be debug, info, warn or error, got "trace"`,
+			},
+		},
+		{
+			name: "deleted order retention",
+			args: []string{"-store.deleted_order_retention=0s", "-store.deleted_order_purge_interval=-1h"},
+			wantErr: []string{
+				"store.deleted_order_retention must be positive",
+				"store.deleted_order_purge_interval must be positive",
+			},
+		},
+		{
+			name: "outbox publisher and retention",
+			args: []string{"-outbox.publisher=kafka", "-outbox.published_retention=0s", "-outbox.purge_interval=0s"},
+			wantErr: []string{
+				`outbox.publisher must be log or none, got "kafka"`,
+				"outbox.published_retention must be positive",
+				"outbox.purge_interval must be positive",
+			},
+		},
+		{
+			name:    "auth without keys",
+			args:    []string{"-auth.enabled=true"},
+			wantErr: []string{"auth.hmac_secret, auth.jwks_file or auth.jwks_url must be set when auth is enabled"},
+		},
+		{
+			name:    "short HMAC secret",
+			args:    []string{"-auth.enabled=true", "-auth.hmac_secret=short"},
+			wantErr: []string{"auth.hmac_secret must be at least 32 bytes long"},
+		},
+		{
+			name: "auth with two JWKS",
+			env:  map[string]string{"ORDER_AUTH_ENABLED": "true", "ORDER_AUTH_JWKS_FILE": "jwks.json", "ORDER_AUTH_JWKS_URL": "file:///jwks.json"},
+			wantErr: []string{
+				"only one of auth.jwks_file and auth.jwks_url may be set",
+				`auth.jwks_url must be an http or https URL, got "file:///jwks.json"`,
+			},
+		},
+		{
+			name: "invalid rate limits",
+			args: []string{
+				"-rate_limit.backend=redis", "-rate_limit.default=10", "-rate_limit.per_rpc=CreateOrder=0/s",
+				"-rate_limit.global=PayOrder=10/s", "-rate_limit.per_ip=fast",
+			},
+			wantErr: []string{
+				`rate_limit.backend must be memory or postgres, got "redis"`,
+				`rate_limit.default: invalid limit "10", expected <count>/<period> such as 10/s`,
+				`rate_limit.per_rpc: CreateOrder: invalid limit "0/s", the count must be a positive integer`,
+				`rate_limit.per_ip: invalid limit "fast", expected <count>/<period> such as 10/s`,
+				"rate_limit.global: unknown OrderService method PayOrder",
+			},
+		},
+		{
+			name:    "shared rate limits without postgres",
+			env:     map[string]string{"STORE": "memory", "ORDER_RATE_LIMIT_BACKEND": "postgres"},
+			wantErr: []string{"rate_limit.backend postgres requires the postgres store"},
+		},
+		{
+			name:    "postgres without database URL",
+			env:     map[string]string{"STORE": "postgres"},
+			args:    []string{"-store.database_url="},
+			wantErr: []string{"store.database_url must be set"},
+		},
+		{
+			name:    "missing file",
+			args:    []string{"-config", "does-not-exist.yaml"},
+			wantErr: []string{"read config file"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := tc.args
+			if tc.file != "" {
+				args = append([]string{"-config", writeFile(t, tc.file)}, args...)
+			}
+
+			_, err := load(args, tc.env)
+			require.Error(t, err)
+			for _, want := range tc.wantErr {
+				assert.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func TestConfig_Print(t *testing.T) {
+	config := Default()
+	config.Store.DatabaseURL = "postgres://orders:s3cret@db:5432/orders?sslmode=disable"
+
+	var out bytes.Buffer
+	require.NoError(t, config.Print(&out))
+
+	assert.NotContains(t, out.String(), "s3cret")
+	assert.Contains(t, out.String(), "database_url: postgres://orders:REDACTED@db:5432/orders?sslmode=disable")
+	assert.Contains(t, out.String(), "shutdown_timeout: 30s")
+	assert.Equal(t, "postgres://orders:s3cret@db:5432/orders?sslmode=disable", config.Store.DatabaseURL,
+		"Printing should not change the config")
+
+	// The printed configuration can be loaded back.
+	loaded, err := load([]string{"-config", writeFile(t, out.String())}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, config.Server, loaded.Server)
+	assert.Equal(t, config.Webhooks, loaded.Webhooks)
+
+	config.Auth.HMACSecret = "0123456789abcdef0123456789abcdef"
+	out.Reset()
+	require.NoError(t, config.Print(&out))
+	assert.Contains(t, out.String(), "hmac_secret: REDACTED")
+}
+
+func TestConfig_RateLimitConfig(t *testing.T) {
+	config := Default()
+	config.RateLimit.Global = "CreateOrder=100/m"
+
+	assert.Equal(t, ratelimit.Config{
+		Default: ratelimit.Limit{Count: 50, Period: time.Second},
+		PerRPC:  map[string]ratelimit.Limit{"CreateOrder": {Count: 10, Period: time.Second}},
+		Global:  map[string]ratelimit.Limit{"CreateOrder": {Count: 100, Period: time.Minute}},
+		PerIP:   ratelimit.Limit{Count: 100, Period: time.Second},
+	}, config.RateLimitConfig())
+
+	config.RateLimit.Default = ""
+	assert.Zero(t, config.RateLimitConfig().Default, "An empty default should not limit")
+}
+
+func TestRedact(t *testing.T) {
+	assert.Equal(t, "", redact(""))
+	assert.Equal(t, "REDACTED", redact("plain-secret"))
+	assert.Equal(t, "REDACTED", redact("host=db user=orders password=s3cret"))
+	assert.Equal(t, "postgres://db/orders", redact("postgres://db/orders"))
+	assert.Equal(t, "postgres://orders@db/orders", redact("postgres://orders@db/orders"))
+	assert.Equal(t, "postgres://db/orders?password=REDACTED&user=orders", redact("postgres://db/orders?user=orders&password=s3cret"))
+}
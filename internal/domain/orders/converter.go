@@ -1,6 +1,7 @@
 package orders
 
 import (
+	"strings"
 	"time"
 
 	orderv1 "github.com/demo/contracts/gen/go/order/v1"
@@ -8,12 +9,47 @@ import (
 )
 
 func entityToProto(e *entity.Order) *orderv1.Order {
-	return &orderv1.Order{
-		Id:        e.ID,
-		UserId:    e.UserID,
-		Item:      e.Item,
-		Amount:    e.Amount,
-		Status:    string(e.Status),
-		CreatedAt: e.CreatedAt.Format(time.RFC3339),
+	o := &orderv1.Order{
+		Id:            e.ID,
+		UserId:        e.UserID,
+		Item:          itemSummary(e.Items),
+		Amount:        e.Amount.Float64(),
+		Status:        string(e.Status),
+		CreatedAt:     e.CreatedAt.Format(time.RFC3339),
+		CancelReason:  string(e.CancelReason),
+		CancelComment: e.CancelComment,
+		CancelledBy:   e.CancelledBy,
+
+		AmountMinorUnits: e.Amount.MinorUnits,
+		Currency:         e.Amount.Currency,
+		Items:            make([]*orderv1.OrderItem, len(e.Items)),
+	}
+	for i, item := range e.Items {
+		o.Items[i] = &orderv1.OrderItem{
+			Sku:                 item.SKU,
+			Name:                item.Name,
+			Quantity:            item.Quantity,
+			UnitPrice:           item.UnitPrice.Float64(),
+			UnitPriceMinorUnits: item.UnitPrice.MinorUnits,
+		}
+	}
+	if e.CancelledAt != nil {
+		o.CancelledAt = e.CancelledAt.Format(time.RFC3339)
+	}
+	if e.DeletedAt != nil {
+		o.DeletedAt = e.DeletedAt.Format(time.RFC3339)
+	}
+	return o
+}
+
+// itemSummary fills the legacy single item field with the names of all items.
+func itemSummary(items []entity.OrderItem) string {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+		if names[i] == "" {
+			names[i] = item.SKU
+		}
 	}
+	return strings.Join(names, ", ")
 }
@@ -0,0 +1,33 @@
+package orders
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type deletedOrderPurger interface {
+	PurgeDeletedOrders(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
+
+// PurgeDeletedOrders removes the orders deleted more than retention ago every interval until ctx is done.
+func PurgeDeletedOrders(ctx context.Context, store deletedOrderPurger, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := store.PurgeDeletedOrders(ctx, time.Now().UTC().Add(-retention))
+			if err != nil {
+				slog.Error("Failed to purge deleted orders", "error", err)
+				continue
+			}
+			if purged > 0 {
+				slog.Info("Purged deleted orders", "purged", purged)
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/auth"
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/logging"
+	"github.com/demo/order/internal/store"
+	"github.com/demo/order/internal/tracing"
+	"github.com/demo/order/internal/validation"
+)
+
+type cancelOrderHandler struct {
+	store store.OrderStore
+}
+
+func newCancelOrderHandler(store store.OrderStore) *cancelOrderHandler {
+	return &cancelOrderHandler{store: store}
+}
+
+func (h *cancelOrderHandler) Handle(
+	ctx context.Context,
+	req *connect.Request[orderv1.CancelOrderRequest],
+) (*connect.Response[orderv1.CancelOrderResponse], error) {
+	cancelledBy := cancelledBy(ctx, req.Msg)
+	if err := tracing.Run(ctx, "CancelOrder.validate", func() error { return h.validate(req.Msg, cancelledBy) }); err != nil {
+		return nil, err
+	}
+	policy, err := policyFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := h.store.Get(ctx, req.Msg.Id)
+	if err != nil {
+		if errors.Is(err, store.ErrOrderNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+		logging.FromContext(ctx).Error("Failed to get order", "order_id", req.Msg.Id, "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	if err := policy.checkOrder(order); err != nil {
+		return nil, err
+	}
+
+	// Cancelling an already cancelled order is a no-op: the original cancellation is kept.
+	if order.Status == entity.OrderStatusCancelled {
+		return h.response(order), nil
+	}
+
+	if !canTransitionOrderStatus(order.Status, entity.OrderStatusCancelled) {
+		return nil, connect.NewError(
+			connect.CodeFailedPrecondition,
+			fmt.Errorf("order in status %s cannot be cancelled", order.Status),
+		)
+	}
+
+	cancelledAt := time.Now().UTC()
+	cancelled, err := h.store.Cancel(ctx, order.ID, order.Status, entity.OrderCancellation{
+		CancelReason:  entity.CancelReason(req.Msg.Reason),
+		CancelComment: req.Msg.Comment,
+		CancelledBy:   cancelledBy,
+		CancelledAt:   &cancelledAt,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrOrderNotFound):
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		case errors.Is(err, store.ErrOrderStatusConflict):
+			return h.handleConflict(ctx, order.ID, err)
+		}
+		logging.FromContext(ctx).Error("Failed to cancel order", "order_id", order.ID, "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return h.response(cancelled), nil
+}
+
+// handleConflict resolves a concurrent status change: if another request has
+// already cancelled the order, the call is still reported as successful.
+func (h *cancelOrderHandler) handleConflict(
+	ctx context.Context,
+	id string,
+	conflictErr error,
+) (*connect.Response[orderv1.CancelOrderResponse], error) {
+	order, err := h.store.Get(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get order", "order_id", id, "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if order.Status != entity.OrderStatusCancelled {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, conflictErr)
+	}
+	return h.response(order), nil
+}
+
+func (h *cancelOrderHandler) response(order *entity.Order) *connect.Response[orderv1.CancelOrderResponse] {
+	return connect.NewResponse(&orderv1.CancelOrderResponse{
+		Order: entityToProto(order),
+	})
+}
+
+// cancelledBy returns who cancels the order: the authenticated caller, so that it
+// cannot be forged, or the cancelled_by of the request when authentication is disabled.
+func cancelledBy(ctx context.Context, req *orderv1.CancelOrderRequest) string {
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		return principal.Subject
+	}
+	return req.CancelledBy
+}
+
+func (h *cancelOrderHandler) validate(req *orderv1.CancelOrderRequest, cancelledBy string) error {
+	var v validation.Violations
+	v.Check(cancelledBy != "", "cancelled_by", "must be set")
+	if entity.CancelReason(req.Reason) == entity.CancelReasonOther {
+		v.Check(req.Comment != "", "comment", "must be set for reason OTHER")
+	}
+	return v.Err()
+}
@@ -0,0 +1,410 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/auth"
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+	"github.com/demo/order/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelOrderHandler(t *testing.T) {
+	// testData holds all data needed for each test case
+	type testData struct {
+		ctx       context.Context
+		t         *testing.T
+		handler   *cancelOrderHandler
+		mockStore *store.MockOrderStore
+		request   *connect.Request[orderv1.CancelOrderRequest]
+		response  *connect.Response[orderv1.CancelOrderResponse]
+		err       error
+
+		// Track Cancel calls for verification
+		cancelCalls []entity.OrderCancellation
+	}
+
+	// testCase defines GWT structure for each test scenario
+	type testCase struct {
+		name  string
+		given func(*testData)
+		when  func(*testData)
+		then  func(*testData)
+	}
+
+	// setupTestData creates isolated test data for each test case
+	setupTestData := func(t *testing.T) *testData {
+		td := &testData{
+			ctx:       auth.WithDisabled(context.Background()),
+			t:         t,
+			mockStore: &store.MockOrderStore{},
+			request: connect.NewRequest(&orderv1.CancelOrderRequest{
+				Id:          "order-123",
+				Reason:      string(entity.CancelReasonCreatedByMistake),
+				Comment:     "Customer clicked twice",
+				CancelledBy: "support-agent-1",
+			}),
+		}
+
+		// Default mock behavior: the order exists in status NEW
+		td.mockStore.GetFunc = func(_ context.Context, id string) (*entity.Order, error) {
+			return &entity.Order{
+				ID:        id,
+				UserID:    "user-123",
+				Items:     []entity.OrderItem{{Name: "Test Item", Quantity: 1}},
+				Amount:    entity.NewMoney(10000, entity.DefaultCurrency),
+				Status:    entity.OrderStatusNew,
+				CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			}, nil
+		}
+		td.mockStore.CancelFunc = func(
+			_ context.Context,
+			id string,
+			_ entity.OrderStatus,
+			cancellation entity.OrderCancellation,
+		) (*entity.Order, error) {
+			td.cancelCalls = append(td.cancelCalls, cancellation)
+			return &entity.Order{
+				ID:                id,
+				UserID:            "user-123",
+				Items:             []entity.OrderItem{{Name: "Test Item", Quantity: 1}},
+				Amount:            entity.NewMoney(10000, entity.DefaultCurrency),
+				Status:            entity.OrderStatusCancelled,
+				CreatedAt:         time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+				OrderCancellation: cancellation,
+			}, nil
+		}
+
+		td.handler = newCancelOrderHandler(td.mockStore)
+
+		return td
+	}
+
+	testCases := []testCase{
+		// Success scenario: NEW order is cancelled
+		{
+			name:  "Should cancel NEW order and record the cancellation",
+			given: func(_ *testData) {},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.NotNil(td.t, td.response)
+				require.NotNil(td.t, td.response.Msg.Order)
+
+				order := td.response.Msg.Order
+				assert.Equal(td.t, "CANCELLED", order.Status)
+				assert.Equal(td.t, "CREATED_BY_MISTAKE", order.CancelReason)
+				assert.Equal(td.t, "Customer clicked twice", order.CancelComment)
+				assert.Equal(td.t, "support-agent-1", order.CancelledBy)
+				assert.NotEmpty(td.t, order.CancelledAt)
+
+				require.Len(td.t, td.cancelCalls, 1, "Store.Cancel should be called once")
+				require.NotNil(td.t, td.cancelCalls[0].CancelledAt)
+			},
+		},
+
+		// Success scenario: IN_PROGRESS order is cancelled
+		{
+			name: "Should cancel IN_PROGRESS order",
+			given: func(td *testData) {
+				td.mockStore.GetFunc = func(_ context.Context, id string) (*entity.Order, error) {
+					return &entity.Order{ID: id, Status: entity.OrderStatusInProgress}, nil
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.Equal(td.t, "CANCELLED", td.response.Msg.Order.Status)
+				assert.Len(td.t, td.cancelCalls, 1)
+			},
+		},
+
+		// Idempotency: order is already cancelled
+		{
+			name: "Should return existing cancellation when order is already cancelled",
+			given: func(td *testData) {
+				cancelledAt := time.Date(2024, 1, 16, 8, 0, 0, 0, time.UTC)
+				td.mockStore.GetFunc = func(_ context.Context, id string) (*entity.Order, error) {
+					return &entity.Order{
+						ID:     id,
+						Status: entity.OrderStatusCancelled,
+						OrderCancellation: entity.OrderCancellation{
+							CancelReason: entity.CancelReasonDuplicate,
+							CancelledBy:  "support-agent-0",
+							CancelledAt:  &cancelledAt,
+						},
+					}, nil
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.NotNil(td.t, td.response)
+				assert.Equal(td.t, "CANCELLED", td.response.Msg.Order.Status)
+				assert.Equal(td.t, "DUPLICATE", td.response.Msg.Order.CancelReason)
+				assert.Equal(td.t, "support-agent-0", td.response.Msg.Order.CancelledBy)
+				assert.Equal(td.t, "2024-01-16T08:00:00Z", td.response.Msg.Order.CancelledAt)
+				assert.Empty(td.t, td.cancelCalls, "Store.Cancel should not be called for cancelled order")
+			},
+		},
+
+		// Idempotency: concurrent cancellation won the race
+		{
+			name: "Should succeed when order was cancelled concurrently",
+			given: func(td *testData) {
+				getCalls := 0
+				td.mockStore.GetFunc = func(_ context.Context, id string) (*entity.Order, error) {
+					getCalls++
+					if getCalls == 1 {
+						return &entity.Order{ID: id, Status: entity.OrderStatusNew}, nil
+					}
+					return &entity.Order{ID: id, Status: entity.OrderStatusCancelled}, nil
+				}
+				td.mockStore.CancelFunc = func(
+					_ context.Context,
+					_ string,
+					_ entity.OrderStatus,
+					_ entity.OrderCancellation,
+				) (*entity.Order, error) {
+					return nil, store.ErrOrderStatusConflict
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.Equal(td.t, "CANCELLED", td.response.Msg.Order.Status)
+			},
+		},
+
+		// FailedPrecondition: concurrent change to a non-cancellable status
+		{
+			name: "Should return FailedPrecondition when order was finished concurrently",
+			given: func(td *testData) {
+				getCalls := 0
+				td.mockStore.GetFunc = func(_ context.Context, id string) (*entity.Order, error) {
+					getCalls++
+					if getCalls == 1 {
+						return &entity.Order{ID: id, Status: entity.OrderStatusInProgress}, nil
+					}
+					return &entity.Order{ID: id, Status: entity.OrderStatusFinished}, nil
+				}
+				td.mockStore.CancelFunc = func(
+					_ context.Context,
+					_ string,
+					_ entity.OrderStatus,
+					_ entity.OrderCancellation,
+				) (*entity.Order, error) {
+					return nil, store.ErrOrderStatusConflict
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeFailedPrecondition, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+			},
+		},
+
+		// FailedPrecondition: FINISHED order cannot be cancelled
+		{
+			name: "Should return FailedPrecondition when order is FINISHED",
+			given: func(td *testData) {
+				td.mockStore.GetFunc = func(_ context.Context, id string) (*entity.Order, error) {
+					return &entity.Order{ID: id, Status: entity.OrderStatusFinished}, nil
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeFailedPrecondition, connect.CodeOf(td.err))
+				assert.Contains(td.t, td.err.Error(), "order in status FINISHED cannot be cancelled")
+				assert.Nil(td.t, td.response)
+				assert.Empty(td.t, td.cancelCalls)
+			},
+		},
+
+		// Validation error: empty cancelled_by without authentication
+		{
+			name: "Should return InvalidArgument when cancelled_by is empty without authentication",
+			given: func(td *testData) {
+				td.request.Msg.CancelledBy = ""
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Empty(td.t, td.cancelCalls)
+			},
+		},
+
+		// Validation error: OTHER without comment
+		{
+			name: "Should return InvalidArgument when reason is OTHER and comment is empty",
+			given: func(td *testData) {
+				td.request.Msg.Reason = string(entity.CancelReasonOther)
+				td.request.Msg.Comment = ""
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Empty(td.t, td.cancelCalls)
+			},
+		},
+
+		// NotFound: store.Get returns ErrOrderNotFound
+		{
+			name: "Should return NotFound when order does not exist",
+			given: func(td *testData) {
+				td.mockStore.GetFunc = func(_ context.Context, _ string) (*entity.Order, error) {
+					return nil, store.ErrOrderNotFound
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeNotFound, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+			},
+		},
+
+		// Internal: store.Cancel returns other error
+		{
+			name: "Should return Internal error when store.Cancel fails",
+			given: func(td *testData) {
+				td.mockStore.CancelFunc = func(
+					_ context.Context,
+					_ string,
+					_ entity.OrderStatus,
+					_ entity.OrderCancellation,
+				) (*entity.Order, error) {
+					return nil, errors.New("database connection failed")
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInternal, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+			},
+		},
+		{
+			name: "Should return NotFound and not cancel when order belongs to another user",
+			given: func(td *testData) {
+				td.ctx = asUser("user-789")
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeNotFound, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+				assert.Empty(td.t, td.cancelCalls, "Store.Cancel should not be called")
+			},
+		},
+		{
+			name: "Should cancel own order",
+			given: func(td *testData) {
+				td.ctx = asUser("user-123")
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.Len(td.t, td.cancelCalls, 1)
+			},
+		},
+		{
+			name: "Should record the authenticated caller instead of cancelled_by",
+			given: func(td *testData) {
+				td.ctx = asUser("agent-1", auth.RoleSupport)
+				td.request.Msg.CancelledBy = "someone-else"
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.Len(td.t, td.cancelCalls, 1)
+				assert.Equal(td.t, "agent-1", td.cancelCalls[0].CancelledBy)
+				assert.Equal(td.t, "agent-1", td.response.Msg.Order.CancelledBy)
+			},
+		},
+		{
+			name: "Should not require cancelled_by from an authenticated caller",
+			given: func(td *testData) {
+				td.ctx = asUser("user-123")
+				td.request.Msg.CancelledBy = ""
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.Len(td.t, td.cancelCalls, 1)
+				assert.Equal(td.t, "user-123", td.cancelCalls[0].CancelledBy)
+			},
+		},
+		{
+			name: "Should report every field violation",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CancelOrderRequest{
+					Id:      "order-1",
+					Reason:  string(entity.CancelReasonOther),
+					Comment: "",
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Equal(td.t, map[string]string{
+					"cancelled_by": "must be set",
+					"comment":      "must be set for reason OTHER",
+				}, validation.FieldViolations(td.err))
+				assert.Empty(td.t, td.cancelCalls, "Store.Cancel should not be called")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			td := setupTestData(t)
+			td.t = t
+			tc.given(td)
+			tc.when(td)
+			tc.then(td)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"connectrpc.com/connect"
 	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/logging"
 	"github.com/demo/order/internal/store"
 )
 
@@ -21,7 +22,11 @@ func (h *checkOrderOwnerHandler) Handle(
 	ctx context.Context,
 	req *connect.Request[orderv1.CheckOrderOwnerRequest],
 ) (*connect.Response[orderv1.CheckOrderOwnerResponse], error) {
-	if err := h.validate(req.Msg); err != nil {
+	policy, err := policyFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.checkUser(req.Msg.UserId); err != nil {
 		return nil, err
 	}
 
@@ -30,22 +35,17 @@ func (h *checkOrderOwnerHandler) Handle(
 		if errors.Is(err, store.ErrOrderNotFound) {
 			return nil, connect.NewError(connect.CodeNotFound, err)
 		}
+		logging.FromContext(ctx).Error("Failed to get order", "order_id", req.Msg.OrderId, "error", err)
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
+	if err := policy.checkOrder(order); err != nil {
+		return nil, err
+	}
+
 	if order.UserID != req.Msg.UserId {
 		return nil, connect.NewError(connect.CodePermissionDenied, errors.New("order does not belong to user"))
 	}
 
 	return connect.NewResponse(&orderv1.CheckOrderOwnerResponse{}), nil
 }
-
-func (h *checkOrderOwnerHandler) validate(req *orderv1.CheckOrderOwnerRequest) error {
-	if req.OrderId == "" {
-		return connect.NewError(connect.CodeInvalidArgument, nil)
-	}
-	if req.UserId == "" {
-		return connect.NewError(connect.CodeInvalidArgument, nil)
-	}
-	return nil
-}
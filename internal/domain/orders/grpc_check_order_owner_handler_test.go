@@ -8,6 +8,7 @@ import (
 
 	"connectrpc.com/connect"
 	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/auth"
 	"github.com/demo/order/internal/entity"
 	"github.com/demo/order/internal/store"
 	"github.com/stretchr/testify/assert"
@@ -43,8 +44,8 @@ func TestCheckOrderOwnerHandler(t *testing.T) {
 			return &entity.Order{
 				ID:        id,
 				UserID:    "user-123",
-				Item:      "Test Item",
-				Amount:    100.00,
+				Items:     []entity.OrderItem{{Name: "Test Item", Quantity: 1}},
+				Amount:    entity.NewMoney(10000, entity.DefaultCurrency),
 				Status:    entity.OrderStatusNew,
 				CreatedAt: time.Now(),
 			}, nil
@@ -53,7 +54,7 @@ func TestCheckOrderOwnerHandler(t *testing.T) {
 		handler := newCheckOrderOwnerHandler(mockStore)
 
 		return &testData{
-			ctx:       context.Background(),
+			ctx:       auth.WithDisabled(context.Background()),
 			t:         t,
 			handler:   handler,
 			mockStore: mockStore,
@@ -69,8 +70,8 @@ func TestCheckOrderOwnerHandler(t *testing.T) {
 					return &entity.Order{
 						ID:        id,
 						UserID:    "user-123",
-						Item:      "Test Item",
-						Amount:    100.00,
+						Items:     []entity.OrderItem{{Name: "Test Item", Quantity: 1}},
+						Amount:    entity.NewMoney(10000, entity.DefaultCurrency),
 						Status:    entity.OrderStatusNew,
 						CreatedAt: time.Now(),
 					}, nil
@@ -91,12 +92,15 @@ func TestCheckOrderOwnerHandler(t *testing.T) {
 			},
 		},
 
-		// Validation error: empty order_id
+		// NotFound: store returns ErrOrderNotFound
 		{
-			name: "Should return InvalidArgument when order_id is empty",
+			name: "Should return NotFound when order does not exist",
 			given: func(td *testData) {
+				td.mockStore.GetFunc = func(_ context.Context, _ string) (*entity.Order, error) {
+					return nil, store.ErrOrderNotFound
+				}
 				td.request = connect.NewRequest(&orderv1.CheckOrderOwnerRequest{
-					OrderId: "",
+					OrderId: "non-existent-order",
 					UserId:  "user-123",
 				})
 			},
@@ -105,18 +109,28 @@ func TestCheckOrderOwnerHandler(t *testing.T) {
 			},
 			then: func(td *testData) {
 				require.Error(td.t, td.err)
-				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Equal(td.t, connect.CodeNotFound, connect.CodeOf(td.err))
 				assert.Nil(td.t, td.response)
 			},
 		},
 
-		// Validation error: empty user_id
+		// PermissionDenied: user_id doesn't match order's user_id
 		{
-			name: "Should return InvalidArgument when user_id is empty",
+			name: "Should return PermissionDenied when user is not the owner",
 			given: func(td *testData) {
+				td.mockStore.GetFunc = func(_ context.Context, id string) (*entity.Order, error) {
+					return &entity.Order{
+						ID:        id,
+						UserID:    "owner-user-789",
+						Items:     []entity.OrderItem{{Name: "Test Item", Quantity: 1}},
+						Amount:    entity.NewMoney(10000, entity.DefaultCurrency),
+						Status:    entity.OrderStatusNew,
+						CreatedAt: time.Now(),
+					}, nil
+				}
 				td.request = connect.NewRequest(&orderv1.CheckOrderOwnerRequest{
 					OrderId: "order-456",
-					UserId:  "",
+					UserId:  "different-user-123",
 				})
 			},
 			when: func(td *testData) {
@@ -124,20 +138,21 @@ func TestCheckOrderOwnerHandler(t *testing.T) {
 			},
 			then: func(td *testData) {
 				require.Error(td.t, td.err)
-				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Equal(td.t, connect.CodePermissionDenied, connect.CodeOf(td.err))
+				assert.Contains(td.t, td.err.Error(), "order does not belong to user")
 				assert.Nil(td.t, td.response)
 			},
 		},
 
-		// NotFound: store returns ErrOrderNotFound
+		// Internal: store.Get() returns other error
 		{
-			name: "Should return NotFound when order does not exist",
+			name: "Should return Internal error when store returns unexpected error",
 			given: func(td *testData) {
 				td.mockStore.GetFunc = func(_ context.Context, _ string) (*entity.Order, error) {
-					return nil, store.ErrOrderNotFound
+					return nil, errors.New("database connection failed")
 				}
 				td.request = connect.NewRequest(&orderv1.CheckOrderOwnerRequest{
-					OrderId: "non-existent-order",
+					OrderId: "order-456",
 					UserId:  "user-123",
 				})
 			},
@@ -146,28 +161,17 @@ func TestCheckOrderOwnerHandler(t *testing.T) {
 			},
 			then: func(td *testData) {
 				require.Error(td.t, td.err)
-				assert.Equal(td.t, connect.CodeNotFound, connect.CodeOf(td.err))
+				assert.Equal(td.t, connect.CodeInternal, connect.CodeOf(td.err))
 				assert.Nil(td.t, td.response)
 			},
 		},
-
-		// PermissionDenied: user_id doesn't match order's user_id
 		{
-			name: "Should return PermissionDenied when user is not the owner",
+			name: "Should return PermissionDenied when checking another user",
 			given: func(td *testData) {
-				td.mockStore.GetFunc = func(_ context.Context, id string) (*entity.Order, error) {
-					return &entity.Order{
-						ID:        id,
-						UserID:    "owner-user-789",
-						Item:      "Test Item",
-						Amount:    100.00,
-						Status:    entity.OrderStatusNew,
-						CreatedAt: time.Now(),
-					}, nil
-				}
+				td.ctx = asUser("user-789")
 				td.request = connect.NewRequest(&orderv1.CheckOrderOwnerRequest{
 					OrderId: "order-456",
-					UserId:  "different-user-123",
+					UserId:  "user-123",
 				})
 			},
 			when: func(td *testData) {
@@ -176,21 +180,16 @@ func TestCheckOrderOwnerHandler(t *testing.T) {
 			then: func(td *testData) {
 				require.Error(td.t, td.err)
 				assert.Equal(td.t, connect.CodePermissionDenied, connect.CodeOf(td.err))
-				assert.Contains(td.t, td.err.Error(), "order does not belong to user")
 				assert.Nil(td.t, td.response)
 			},
 		},
-
-		// Internal: store.Get() returns other error
 		{
-			name: "Should return Internal error when store returns unexpected error",
+			name: "Should return NotFound when caller checks own ownership of another user's order",
 			given: func(td *testData) {
-				td.mockStore.GetFunc = func(_ context.Context, _ string) (*entity.Order, error) {
-					return nil, errors.New("database connection failed")
-				}
+				td.ctx = asUser("user-789")
 				td.request = connect.NewRequest(&orderv1.CheckOrderOwnerRequest{
 					OrderId: "order-456",
-					UserId:  "user-123",
+					UserId:  "user-789",
 				})
 			},
 			when: func(td *testData) {
@@ -198,7 +197,7 @@ func TestCheckOrderOwnerHandler(t *testing.T) {
 			},
 			then: func(td *testData) {
 				require.Error(td.t, td.err)
-				assert.Equal(td.t, connect.CodeInternal, connect.CodeOf(td.err))
+				assert.Equal(td.t, connect.CodeNotFound, connect.CodeOf(td.err))
 				assert.Nil(td.t, td.response)
 			},
 		},
@@ -2,12 +2,17 @@ package orders
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"connectrpc.com/connect"
 	orderv1 "github.com/demo/contracts/gen/go/order/v1"
 	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/logging"
 	"github.com/demo/order/internal/store"
+	"github.com/demo/order/internal/tracing"
+	"github.com/demo/order/internal/validation"
 	"github.com/google/uuid"
 )
 
@@ -23,37 +28,202 @@ func (h *createOrderHandler) Handle(
 	ctx context.Context,
 	req *connect.Request[orderv1.CreateOrderRequest],
 ) (*connect.Response[orderv1.CreateOrderResponse], error) {
-	if err := h.validate(req.Msg); err != nil {
+	headerKey := req.Header().Get(IdempotencyKeyHeader)
+	if err := tracing.Run(ctx, "CreateOrder.validate", func() error { return h.validate(req.Msg, headerKey) }); err != nil {
 		return nil, err
 	}
+	policy, err := policyFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.checkUser(req.Msg.UserId); err != nil {
+		return nil, err
+	}
+
+	key := idempotencyKey(req)
+
+	// Already checked by validate.
+	items, amount := orderItems(req.Msg, &validation.Violations{})
 
 	order := &entity.Order{
 		ID:        uuid.New().String(),
 		UserID:    req.Msg.UserId,
-		Item:      req.Msg.Item,
-		Amount:    req.Msg.Amount,
+		Items:     items,
+		Amount:    amount,
 		Status:    entity.OrderStatusNew,
 		CreatedAt: time.Now().UTC(),
 	}
 
-	if err := h.store.Create(ctx, order); err != nil {
+	if key == "" {
+		if err := h.store.Create(ctx, order); err != nil {
+			logging.FromContext(ctx).Error("Failed to create order", "order_id", order.ID, "error", err)
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		return h.response(order), nil
+	}
+
+	fingerprint, err := requestFingerprint(req.Msg)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to fingerprint request", "error", err)
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
+	if resp, err := h.replay(ctx, order.UserID, key, fingerprint); resp != nil || err != nil {
+		return resp, err
+	}
+
+	err = h.store.CreateWithIdempotencyKey(ctx, order, store.IdempotencyRecord{
+		UserID:      order.UserID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		OrderID:     order.ID,
+		CreatedAt:   order.CreatedAt,
+		ExpiresAt:   order.CreatedAt.Add(idempotencyKeyTTL),
+	})
+	if errors.Is(err, store.ErrIdempotencyKeyExists) {
+		// A concurrent request with the same key won the race.
+		if resp, err := h.replay(ctx, order.UserID, key, fingerprint); resp != nil || err != nil {
+			return resp, err
+		}
+		return nil, connect.NewError(connect.CodeAborted, err)
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to create order", "order_id", order.ID, "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return h.response(order), nil
+}
+
+// replay returns the order created by an earlier request of the user with the same idempotency key.
+// It returns a nil response and a nil error if the key has not been used yet.
+func (h *createOrderHandler) replay(
+	ctx context.Context,
+	userID, key, fingerprint string,
+) (*connect.Response[orderv1.CreateOrderResponse], error) {
+	record, err := h.store.GetIdempotencyRecord(ctx, userID, key, time.Now().UTC())
+	if errors.Is(err, store.ErrIdempotencyKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get idempotency record", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	if record.Fingerprint != fingerprint {
+		return nil, connect.NewError(
+			connect.CodeAlreadyExists,
+			errors.New("idempotency key has already been used with a different request"),
+		)
+	}
+
+	// The order may have been deleted since, the record goes away once it is purged.
+	order, err := h.store.GetIncludingDeleted(ctx, record.OrderID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get order", "order_id", record.OrderID, "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	resp := h.response(order)
+	resp.Header().Set(IdempotentReplayedHeader, "true")
+	return resp, nil
+}
+
+func (h *createOrderHandler) response(order *entity.Order) *connect.Response[orderv1.CreateOrderResponse] {
 	return connect.NewResponse(&orderv1.CreateOrderResponse{
 		Order: entityToProto(order),
-	}), nil
+	})
+}
+
+// validate checks the request, whose idempotency key may also be sent in the headerKey header.
+func (h *createOrderHandler) validate(req *orderv1.CreateOrderRequest, headerKey string) error {
+	var v validation.Violations
+	checkIdempotencyKey(&v, req, headerKey)
+	if v.Check(entity.IsKnownCurrency(orderCurrency(req)), "currency", fmt.Sprintf("unknown currency %q", req.Currency)) {
+		// The amounts cannot be checked without a known currency.
+		orderItems(req, &v)
+	}
+	return v.Err()
+}
+
+// orderCurrency returns the requested currency, defaulting to EUR for clients that do not send one.
+func orderCurrency(req *orderv1.CreateOrderRequest) string {
+	if req.Currency == "" {
+		return entity.DefaultCurrency
+	}
+	return req.Currency
 }
 
-func (h *createOrderHandler) validate(req *orderv1.CreateOrderRequest) error {
-	if req.UserId == "" {
-		return connect.NewError(connect.CodeInvalidArgument, nil)
+// orderItems returns the items of the order and their total, and records the
+// violations of the request in v. A request without items is a single item of
+// quantity one priced at the amount.
+//
+// Amounts are stored exactly, so values finer than the currency's minor unit
+// or too large for the amount column are rejected rather than rounded.
+func orderItems(req *orderv1.CreateOrderRequest, v *validation.Violations) ([]entity.OrderItem, entity.Money) {
+	currency := orderCurrency(req)
+
+	if len(req.Items) == 0 {
+		v.Check(req.Item != "", "item", "must be set when there are no items")
+		if !v.Check(req.Amount > 0, "amount", "must be positive") {
+			return nil, entity.Money{}
+		}
+		amount, err := entity.MoneyFromFloat(req.Amount, currency)
+		if err != nil {
+			v.Add("amount", moneyViolation(err, currency))
+			return nil, entity.Money{}
+		}
+		return []entity.OrderItem{{Name: req.Item, Quantity: 1, UnitPrice: amount}}, amount
+	}
+
+	v.Check(req.Item == "", "item", "must not be set with items")
+
+	violations := v.Len()
+	items := make([]entity.OrderItem, len(req.Items))
+	for i, item := range req.Items {
+		// The SKU, quantity and sign of the unit price are checked by the contract rules.
+		unitPrice, err := entity.MoneyFromFloat(item.UnitPrice, currency)
+		if err != nil {
+			v.Add(fmt.Sprintf("items[%d].unit_price", i), moneyViolation(err, currency))
+		}
+		items[i] = entity.OrderItem{
+			SKU:       item.Sku,
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			UnitPrice: unitPrice,
+		}
 	}
-	if req.Item == "" {
-		return connect.NewError(connect.CodeInvalidArgument, nil)
+	if v.Len() > violations {
+		return nil, entity.Money{}
 	}
-	if req.Amount <= 0 {
-		return connect.NewError(connect.CodeInvalidArgument, nil)
+
+	total, err := entity.ItemsTotal(items, currency)
+	if err != nil {
+		v.Add("items", fmt.Sprintf("total must not exceed %s", entity.MaxMoney(currency)))
+		return nil, entity.Money{}
+	}
+
+	// The total is computed here; a client-supplied amount is only checked against it.
+	if req.Amount != 0 {
+		amount, err := entity.MoneyFromFloat(req.Amount, currency)
+		if err != nil {
+			v.Add("amount", moneyViolation(err, currency))
+		} else {
+			v.Check(amount == total, "amount", fmt.Sprintf("%s does not match the items total %s", amount, total))
+		}
+	}
+	return items, total
+}
+
+// moneyViolation describes why entity.MoneyFromFloat rejected an amount in currency.
+func moneyViolation(err error, currency string) string {
+	switch {
+	case errors.Is(err, entity.ErrMoneyScale):
+		scale, _ := entity.CurrencyMinorUnits(currency)
+		return fmt.Sprintf("must have at most %d decimal places in %s", scale, currency)
+	case errors.Is(err, entity.ErrMoneyOutOfRange):
+		return fmt.Sprintf("must not exceed %s", entity.MaxMoney(currency))
+	default:
+		return "must be a finite number"
 	}
-	return nil
 }
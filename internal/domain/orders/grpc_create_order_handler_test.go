@@ -3,12 +3,16 @@ package orders
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"connectrpc.com/connect"
 	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/auth"
 	"github.com/demo/order/internal/entity"
 	"github.com/demo/order/internal/store"
+	"github.com/demo/order/internal/validation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -24,7 +28,8 @@ func TestCreateOrderHandler(t *testing.T) {
 		response  *connect.Response[orderv1.CreateOrderResponse]
 		err       error
 		// Track Create calls for verification
-		createCalls []*entity.Order
+		createCalls           []*entity.Order
+		idempotentCreateCalls []store.IdempotencyRecord
 	}
 
 	// Define testCase struct locally - GWT pattern is MANDATORY
@@ -38,7 +43,7 @@ func TestCreateOrderHandler(t *testing.T) {
 	// Setup function creates isolated test data for each test case
 	setupTestData := func(t *testing.T) *testData {
 		td := &testData{
-			ctx:         context.Background(),
+			ctx:         auth.WithDisabled(context.Background()),
 			t:           t,
 			createCalls: make([]*entity.Order, 0),
 		}
@@ -50,6 +55,10 @@ func TestCreateOrderHandler(t *testing.T) {
 			td.createCalls = append(td.createCalls, order)
 			return nil
 		}
+		td.mockStore.CreateWithIdempotencyKeyFunc = func(_ context.Context, _ *entity.Order, record store.IdempotencyRecord) error {
+			td.idempotentCreateCalls = append(td.idempotentCreateCalls, record)
+			return nil
+		}
 
 		td.handler = newCreateOrderHandler(td.mockStore)
 
@@ -80,6 +89,8 @@ func TestCreateOrderHandler(t *testing.T) {
 				assert.Equal(td.t, "user-123", td.response.Msg.Order.UserId)
 				assert.Equal(td.t, "Test Item", td.response.Msg.Order.Item)
 				assert.Equal(td.t, 100.50, td.response.Msg.Order.Amount)
+				assert.Equal(td.t, int64(10050), td.response.Msg.Order.AmountMinorUnits)
+				assert.Equal(td.t, "EUR", td.response.Msg.Order.Currency)
 				assert.Equal(td.t, string(entity.OrderStatusNew), td.response.Msg.Order.Status)
 				assert.NotEmpty(td.t, td.response.Msg.Order.CreatedAt)
 
@@ -90,19 +101,23 @@ func TestCreateOrderHandler(t *testing.T) {
 				savedOrder := td.createCalls[0]
 				assert.NotEmpty(td.t, savedOrder.ID)
 				assert.Equal(td.t, "user-123", savedOrder.UserID)
-				assert.Equal(td.t, "Test Item", savedOrder.Item)
-				assert.Equal(td.t, 100.50, savedOrder.Amount)
+				assert.Equal(td.t, []entity.OrderItem{{
+					Name:      "Test Item",
+					Quantity:  1,
+					UnitPrice: entity.NewMoney(10050, entity.DefaultCurrency),
+				}}, savedOrder.Items)
+				assert.Equal(td.t, entity.NewMoney(10050, entity.DefaultCurrency), savedOrder.Amount)
 				assert.Equal(td.t, entity.OrderStatusNew, savedOrder.Status)
 			},
 		},
 
-		// Validation error - empty user_id
+		// Validation error - empty item
 		{
-			name: "Should return InvalidArgument when user_id is empty",
+			name: "Should return InvalidArgument when item is empty",
 			given: func(td *testData) {
 				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
-					UserId: "",
-					Item:   "Test Item",
+					UserId: "user-123",
+					Item:   "",
 					Amount: 100.50,
 				})
 			},
@@ -117,14 +132,14 @@ func TestCreateOrderHandler(t *testing.T) {
 			},
 		},
 
-		// Validation error - empty item
+		// Validation error - zero amount
 		{
-			name: "Should return InvalidArgument when item is empty",
+			name: "Should return InvalidArgument when amount is zero",
 			given: func(td *testData) {
 				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
 					UserId: "user-123",
-					Item:   "",
-					Amount: 100.50,
+					Item:   "Test Item",
+					Amount: 0,
 				})
 			},
 			when: func(td *testData) {
@@ -138,14 +153,14 @@ func TestCreateOrderHandler(t *testing.T) {
 			},
 		},
 
-		// Validation error - zero amount
+		// Validation error - negative amount
 		{
-			name: "Should return InvalidArgument when amount is zero",
+			name: "Should return InvalidArgument when amount is negative",
 			given: func(td *testData) {
 				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
 					UserId: "user-123",
 					Item:   "Test Item",
-					Amount: 0,
+					Amount: -10.00,
 				})
 			},
 			when: func(td *testData) {
@@ -159,14 +174,232 @@ func TestCreateOrderHandler(t *testing.T) {
 			},
 		},
 
-		// Validation error - negative amount
+		// Currency - order is created in the requested currency
 		{
-			name: "Should return InvalidArgument when amount is negative",
+			name: "Should create order in requested currency",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId:   "user-123",
+					Item:     "Test Item",
+					Amount:   1500,
+					Currency: "JPY",
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.Len(td.t, td.createCalls, 1)
+				assert.Equal(td.t, entity.NewMoney(1500, "JPY"), td.createCalls[0].Amount)
+				assert.Equal(td.t, "JPY", td.response.Msg.Order.Currency)
+				assert.Equal(td.t, int64(1500), td.response.Msg.Order.AmountMinorUnits)
+			},
+		},
+
+		// Validation error - unknown currency
+		{
+			name: "Should return InvalidArgument when currency is unknown",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId:   "user-123",
+					Item:     "Test Item",
+					Amount:   100.50,
+					Currency: "XXX",
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Len(td.t, td.createCalls, 0, "Store.Create should not be called on validation error")
+			},
+		},
+
+		// Validation error - amount finer than the currency's minor unit
+		{
+			name: "Should return InvalidArgument when amount has decimals the currency does not allow",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId:   "user-123",
+					Item:     "Test Item",
+					Amount:   10.5,
+					Currency: "JPY",
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Len(td.t, td.createCalls, 0, "Store.Create should not be called on validation error")
+			},
+		},
+
+		// Validation error - more than two decimal places
+		{
+			name: "Should return InvalidArgument when amount has sub-cent precision",
 			given: func(td *testData) {
 				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
 					UserId: "user-123",
 					Item:   "Test Item",
-					Amount: -10.00,
+					Amount: 0.001,
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Equal(td.t, map[string]string{"amount": "must have at most 2 decimal places in EUR"},
+					validation.FieldViolations(td.err))
+				assert.Len(td.t, td.createCalls, 0, "Store.Create should not be called on validation error")
+			},
+		},
+
+		// Validation error - amount does not fit the amount column
+		{
+			name: "Should return InvalidArgument when amount is too large",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId: "user-123",
+					Item:   "Test Item",
+					Amount: 1e12,
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Equal(td.t, map[string]string{"amount": "must not exceed 99999999.99 EUR"},
+					validation.FieldViolations(td.err))
+				assert.Len(td.t, td.createCalls, 0, "Store.Create should not be called on validation error")
+			},
+		},
+
+		// Line items - total is computed on the server
+		{
+			name: "Should create order with items and computed total",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId: "user-123",
+					Items: []*orderv1.OrderItem{
+						{Sku: "SKU-1", Name: "Widget", Quantity: 3, UnitPrice: 0.1},
+						{Sku: "SKU-2", Name: "Gadget", Quantity: 1, UnitPrice: 19.99},
+					},
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.Len(td.t, td.createCalls, 1)
+				saved := td.createCalls[0]
+				assert.Equal(td.t, entity.NewMoney(2029, entity.DefaultCurrency), saved.Amount)
+				assert.Equal(td.t, []entity.OrderItem{
+					{SKU: "SKU-1", Name: "Widget", Quantity: 3, UnitPrice: entity.NewMoney(10, entity.DefaultCurrency)},
+					{SKU: "SKU-2", Name: "Gadget", Quantity: 1, UnitPrice: entity.NewMoney(1999, entity.DefaultCurrency)},
+				}, saved.Items)
+
+				order := td.response.Msg.Order
+				assert.Equal(td.t, 20.29, order.Amount)
+				assert.Equal(td.t, "Widget, Gadget", order.Item)
+				require.Len(td.t, order.Items, 2)
+				assert.Equal(td.t, "SKU-1", order.Items[0].Sku)
+				assert.Equal(td.t, int64(3), order.Items[0].Quantity)
+				assert.Equal(td.t, int64(10), order.Items[0].UnitPriceMinorUnits)
+			},
+		},
+
+		// Line items - matching client total is accepted
+		{
+			name: "Should accept client amount that matches the items total",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId: "user-123",
+					Amount: 0.3,
+					Items: []*orderv1.OrderItem{
+						{Sku: "SKU-1", Quantity: 3, UnitPrice: 0.1},
+					},
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.Len(td.t, td.createCalls, 1)
+				assert.Equal(td.t, entity.NewMoney(30, entity.DefaultCurrency), td.createCalls[0].Amount)
+			},
+		},
+
+		// Line items - validation errors
+		{
+			name: "Should return InvalidArgument when client amount does not match the items total",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId: "user-123",
+					Amount: 10,
+					Items:  []*orderv1.OrderItem{{Sku: "SKU-1", Quantity: 3, UnitPrice: 3.33}},
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Len(td.t, td.createCalls, 0, "Store.Create should not be called on validation error")
+			},
+		},
+		{
+			name: "Should return InvalidArgument when both item and items are set",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId: "user-123",
+					Item:   "Test Item",
+					Items:  []*orderv1.OrderItem{{Sku: "SKU-1", Quantity: 1, UnitPrice: 1}},
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Len(td.t, td.createCalls, 0, "Store.Create should not be called on validation error")
+			},
+		},
+		{
+			name: "Should return InvalidArgument when item unit price has sub-cent precision",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId: "user-123",
+					Items:  []*orderv1.OrderItem{{Sku: "SKU-1", Quantity: 1, UnitPrice: 0.001}},
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Len(td.t, td.createCalls, 0, "Store.Create should not be called on validation error")
+			},
+		},
+		{
+			name: "Should return InvalidArgument when items total is too large",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId: "user-123",
+					Items:  []*orderv1.OrderItem{{Sku: "SKU-1", Quantity: 10000, UnitPrice: 99999999}},
 				})
 			},
 			when: func(td *testData) {
@@ -174,8 +407,9 @@ func TestCreateOrderHandler(t *testing.T) {
 			},
 			then: func(td *testData) {
 				require.Error(td.t, td.err)
-				assert.Nil(td.t, td.response)
 				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Equal(td.t, map[string]string{"items": "total must not exceed 99999999.99 EUR"},
+					validation.FieldViolations(td.err))
 				assert.Len(td.t, td.createCalls, 0, "Store.Create should not be called on validation error")
 			},
 		},
@@ -204,6 +438,272 @@ func TestCreateOrderHandler(t *testing.T) {
 				assert.Len(td.t, td.createCalls, 1, "Store.Create should be called once before failing")
 			},
 		},
+
+		// Idempotency - first request with a key stores the record
+		{
+			name: "Should store idempotency record when key header is set",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId: "user-123",
+					Item:   "Test Item",
+					Amount: 100.50,
+				})
+				td.request.Header().Set(IdempotencyKeyHeader, "key-1")
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.NotNil(td.t, td.response)
+				assert.Empty(td.t, td.response.Header().Get(IdempotentReplayedHeader))
+
+				assert.Len(td.t, td.createCalls, 0, "Store.Create should not be called for keyed requests")
+				require.Len(td.t, td.idempotentCreateCalls, 1)
+				record := td.idempotentCreateCalls[0]
+				assert.Equal(td.t, "user-123", record.UserID)
+				assert.Equal(td.t, "key-1", record.Key)
+				assert.NotEmpty(td.t, record.Fingerprint)
+				assert.Equal(td.t, td.response.Msg.Order.Id, record.OrderID)
+				assert.Equal(td.t, idempotencyKeyTTL, record.ExpiresAt.Sub(record.CreatedAt))
+			},
+		},
+
+		// Idempotency - key in request field is used when header is absent
+		{
+			name: "Should use idempotency key from request field",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId:         "user-123",
+					Item:           "Test Item",
+					Amount:         100.50,
+					IdempotencyKey: "key-1",
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.Len(td.t, td.idempotentCreateCalls, 1)
+				assert.Equal(td.t, "key-1", td.idempotentCreateCalls[0].Key)
+			},
+		},
+
+		// Idempotency - retry replays the original order
+		{
+			name: "Should replay original order when key was used with the same request",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId: "user-123",
+					Item:   "Test Item",
+					Amount: 100.50,
+				})
+				td.request.Header().Set(IdempotencyKeyHeader, "key-1")
+
+				fingerprint, err := requestFingerprint(td.request.Msg)
+				require.NoError(td.t, err)
+				td.mockStore.GetIdempotencyRecordFunc = func(_ context.Context, userID, key string, _ time.Time) (*store.IdempotencyRecord, error) {
+					assert.Equal(td.t, "user-123", userID)
+					assert.Equal(td.t, "key-1", key)
+					return &store.IdempotencyRecord{Key: key, Fingerprint: fingerprint, OrderID: "order-1"}, nil
+				}
+				td.mockStore.GetIncludingDeletedFunc = func(_ context.Context, id string) (*entity.Order, error) {
+					return &entity.Order{
+						ID:        id,
+						UserID:    "user-123",
+						Items:     []entity.OrderItem{{Name: "Test Item", Quantity: 1}},
+						Amount:    entity.NewMoney(10050, entity.DefaultCurrency),
+						Status:    entity.OrderStatusNew,
+						CreatedAt: time.Now(),
+					}, nil
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.NotNil(td.t, td.response)
+				assert.Equal(td.t, "order-1", td.response.Msg.Order.Id)
+				assert.Equal(td.t, "true", td.response.Header().Get(IdempotentReplayedHeader))
+				assert.Len(td.t, td.createCalls, 0)
+				assert.Len(td.t, td.idempotentCreateCalls, 0)
+			},
+		},
+
+		// Idempotency - key reused with a different payload
+		{
+			name: "Should return AlreadyExists when key was used with a different request",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId: "user-123",
+					Item:   "Test Item",
+					Amount: 100.50,
+				})
+				td.request.Header().Set(IdempotencyKeyHeader, "key-1")
+				td.mockStore.GetIdempotencyRecordFunc = func(_ context.Context, userID, key string, _ time.Time) (*store.IdempotencyRecord, error) {
+					return &store.IdempotencyRecord{Key: key, Fingerprint: "other", OrderID: "order-1"}, nil
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Nil(td.t, td.response)
+				assert.Equal(td.t, connect.CodeAlreadyExists, connect.CodeOf(td.err))
+				assert.Len(td.t, td.idempotentCreateCalls, 0)
+			},
+		},
+
+		// Idempotency - concurrent request with the same key wins the race
+		{
+			name: "Should replay order stored by a concurrent request with the same key",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId: "user-123",
+					Item:   "Test Item",
+					Amount: 100.50,
+				})
+				td.request.Header().Set(IdempotencyKeyHeader, "key-1")
+
+				fingerprint, err := requestFingerprint(td.request.Msg)
+				require.NoError(td.t, err)
+				stored := false
+				td.mockStore.GetIdempotencyRecordFunc = func(_ context.Context, userID, key string, _ time.Time) (*store.IdempotencyRecord, error) {
+					if !stored {
+						return nil, store.ErrIdempotencyKeyNotFound
+					}
+					return &store.IdempotencyRecord{Key: key, Fingerprint: fingerprint, OrderID: "order-1"}, nil
+				}
+				td.mockStore.CreateWithIdempotencyKeyFunc = func(_ context.Context, _ *entity.Order, record store.IdempotencyRecord) error {
+					td.idempotentCreateCalls = append(td.idempotentCreateCalls, record)
+					stored = true
+					return store.ErrIdempotencyKeyExists
+				}
+				td.mockStore.GetIncludingDeletedFunc = func(_ context.Context, id string) (*entity.Order, error) {
+					return &entity.Order{ID: id, UserID: "user-123", Status: entity.OrderStatusNew}, nil
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.Equal(td.t, "order-1", td.response.Msg.Order.Id)
+				assert.Equal(td.t, "true", td.response.Header().Get(IdempotentReplayedHeader))
+			},
+		},
+
+		// Idempotency - header and field disagree
+		{
+			name: "Should return InvalidArgument when header and field keys differ",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId:         "user-123",
+					Item:           "Test Item",
+					Amount:         100.50,
+					IdempotencyKey: "key-1",
+				})
+				td.request.Header().Set(IdempotencyKeyHeader, "key-2")
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Len(td.t, td.idempotentCreateCalls, 0)
+			},
+		},
+
+		// Idempotency - key too long
+		{
+			name: "Should return InvalidArgument when idempotency key is too long",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId: "user-123",
+					Item:   "Test Item",
+					Amount: 100.50,
+				})
+				td.request.Header().Set(IdempotencyKeyHeader, strings.Repeat("k", maxIdempotencyKeyLength+1))
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Len(td.t, td.idempotentCreateCalls, 0)
+			},
+		},
+		{
+			name: "Should return PermissionDenied when creating an order for another user",
+			given: func(td *testData) {
+				td.ctx = asUser("user-789")
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId: "user-123",
+					Item:   "Test Item",
+					Amount: 100.50,
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodePermissionDenied, connect.CodeOf(td.err))
+				assert.Empty(td.t, td.createCalls, "Store.Create should not be called")
+			},
+		},
+		{
+			name: "Should report every field violation of the items",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId: "user-123",
+					Item:   "Test Item",
+					Items: []*orderv1.OrderItem{
+						{Sku: "SKU-1", Quantity: 1, UnitPrice: 0.001},
+						{Sku: "SKU-2", Quantity: 1, UnitPrice: 1e12},
+					},
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Equal(td.t, map[string]string{
+					"item":                "must not be set with items",
+					"items[0].unit_price": "must have at most 2 decimal places in EUR",
+					"items[1].unit_price": "must not exceed 99999999.99 EUR",
+				}, validation.FieldViolations(td.err))
+				assert.Empty(td.t, td.createCalls, "Store.Create should not be called")
+			},
+		},
+		{
+			name: "Should report idempotency key violations with the other field violations",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.CreateOrderRequest{
+					UserId:         "user-123",
+					Item:           "Test Item",
+					IdempotencyKey: "key-1",
+				})
+				td.request.Header().Set(IdempotencyKeyHeader, "key-2")
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Equal(td.t, map[string]string{
+					"amount":          "must be positive",
+					"idempotency_key": "differs from the Idempotency-Key header",
+				}, validation.FieldViolations(td.err))
+			},
+		},
 	}
 
 	for _, tc := range testCases {
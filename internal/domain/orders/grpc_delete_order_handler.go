@@ -0,0 +1,57 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/logging"
+	"github.com/demo/order/internal/store"
+)
+
+type deleteOrderHandler struct {
+	store store.OrderStore
+}
+
+func newDeleteOrderHandler(store store.OrderStore) *deleteOrderHandler {
+	return &deleteOrderHandler{store: store}
+}
+
+func (h *deleteOrderHandler) Handle(
+	ctx context.Context,
+	req *connect.Request[orderv1.DeleteOrderRequest],
+) (*connect.Response[orderv1.DeleteOrderResponse], error) {
+	policy, err := policyFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := h.store.Get(ctx, req.Msg.Id)
+	if err != nil {
+		if errors.Is(err, store.ErrOrderNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+		logging.FromContext(ctx).Error("Failed to get order", "order_id", req.Msg.Id, "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	if err := policy.checkOrder(order); err != nil {
+		return nil, err
+	}
+
+	// The order is kept until the purge job removes it, so that admins can restore it.
+	deleted, err := h.store.Delete(ctx, order.ID, time.Now().UTC())
+	if err != nil {
+		if errors.Is(err, store.ErrOrderNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+		logging.FromContext(ctx).Error("Failed to delete order", "order_id", order.ID, "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&orderv1.DeleteOrderResponse{
+		Order: entityToProto(deleted),
+	}), nil
+}
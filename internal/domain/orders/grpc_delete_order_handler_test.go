@@ -0,0 +1,181 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/auth"
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteOrderHandler(t *testing.T) {
+	// testData holds all data needed for each test case
+	type testData struct {
+		ctx       context.Context
+		t         *testing.T
+		handler   *deleteOrderHandler
+		mockStore *store.MockOrderStore
+		request   *connect.Request[orderv1.DeleteOrderRequest]
+		response  *connect.Response[orderv1.DeleteOrderResponse]
+		err       error
+
+		// Track Delete calls for verification
+		deleteCalls []string
+	}
+
+	// testCase defines GWT structure for each test scenario
+	type testCase struct {
+		name  string
+		given func(*testData)
+		when  func(*testData)
+		then  func(*testData)
+	}
+
+	// setupTestData creates isolated test data for each test case
+	setupTestData := func(t *testing.T) *testData {
+		td := &testData{
+			ctx:       auth.WithDisabled(context.Background()),
+			t:         t,
+			mockStore: &store.MockOrderStore{},
+			request:   connect.NewRequest(&orderv1.DeleteOrderRequest{Id: "order-123"}),
+		}
+
+		// Default mock behavior: the order exists and is not deleted
+		td.mockStore.GetFunc = func(_ context.Context, id string) (*entity.Order, error) {
+			return &entity.Order{
+				ID:        id,
+				UserID:    "user-123",
+				Items:     []entity.OrderItem{{Name: "Test Item", Quantity: 1}},
+				Amount:    entity.NewMoney(10000, entity.DefaultCurrency),
+				Status:    entity.OrderStatusNew,
+				CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			}, nil
+		}
+		td.mockStore.DeleteFunc = func(_ context.Context, id string, deletedAt time.Time) (*entity.Order, error) {
+			td.deleteCalls = append(td.deleteCalls, id)
+			return &entity.Order{
+				ID:        id,
+				UserID:    "user-123",
+				Items:     []entity.OrderItem{{Name: "Test Item", Quantity: 1}},
+				Amount:    entity.NewMoney(10000, entity.DefaultCurrency),
+				Status:    entity.OrderStatusNew,
+				CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+				DeletedAt: &deletedAt,
+			}, nil
+		}
+
+		td.handler = newDeleteOrderHandler(td.mockStore)
+
+		return td
+	}
+
+	testCases := []testCase{
+		{
+			name: "Should delete order successfully",
+			given: func(td *testData) {
+				// Default mocks
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.NotNil(td.t, td.response)
+				assert.Equal(td.t, []string{"order-123"}, td.deleteCalls)
+				assert.Equal(td.t, "order-123", td.response.Msg.Order.Id)
+				assert.NotEmpty(td.t, td.response.Msg.Order.DeletedAt)
+			},
+		},
+		{
+			name: "Should return NotFound when order does not exist or is already deleted",
+			given: func(td *testData) {
+				td.mockStore.GetFunc = func(_ context.Context, _ string) (*entity.Order, error) {
+					return nil, store.ErrOrderNotFound
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeNotFound, connect.CodeOf(td.err))
+				assert.Empty(td.t, td.deleteCalls, "Store.Delete should not be called")
+			},
+		},
+		{
+			name: "Should return NotFound when order is deleted concurrently",
+			given: func(td *testData) {
+				td.mockStore.DeleteFunc = func(_ context.Context, _ string, _ time.Time) (*entity.Order, error) {
+					return nil, store.ErrOrderNotFound
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeNotFound, connect.CodeOf(td.err))
+			},
+		},
+		{
+			name: "Should return Internal error when store fails",
+			given: func(td *testData) {
+				td.mockStore.DeleteFunc = func(_ context.Context, _ string, _ time.Time) (*entity.Order, error) {
+					return nil, errors.New("database connection lost")
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInternal, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+			},
+		},
+		{
+			name: "Should delete own order",
+			given: func(td *testData) {
+				td.ctx = asUser("user-123")
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.Equal(td.t, []string{"order-123"}, td.deleteCalls)
+			},
+		},
+		{
+			name: "Should return NotFound when order belongs to another user",
+			given: func(td *testData) {
+				td.ctx = asUser("user-789")
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeNotFound, connect.CodeOf(td.err))
+				assert.Empty(td.t, td.deleteCalls, "Store.Delete should not be called")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			td := setupTestData(t)
+			td.t = t
+			tc.given(td)
+			tc.when(td)
+			tc.then(td)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"connectrpc.com/connect"
 	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/logging"
 	"github.com/demo/order/internal/store"
 )
 
@@ -21,26 +22,33 @@ func (h *getOrderHandler) Handle(
 	ctx context.Context,
 	req *connect.Request[orderv1.GetOrderRequest],
 ) (*connect.Response[orderv1.GetOrderResponse], error) {
-	if err := h.validate(req.Msg); err != nil {
+	policy, err := policyFromContext(ctx)
+	if err != nil {
 		return nil, err
 	}
 
-	order, err := h.store.Get(ctx, req.Msg.Id)
+	get := h.store.Get
+	if req.Msg.IncludeDeleted {
+		if err := policy.checkDeleted(); err != nil {
+			return nil, err
+		}
+		get = h.store.GetIncludingDeleted
+	}
+
+	order, err := get(ctx, req.Msg.Id)
 	if err != nil {
 		if errors.Is(err, store.ErrOrderNotFound) {
 			return nil, connect.NewError(connect.CodeNotFound, err)
 		}
+		logging.FromContext(ctx).Error("Failed to get order", "order_id", req.Msg.Id, "error", err)
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
+	if err := policy.checkOrder(order); err != nil {
+		return nil, err
+	}
+
 	return connect.NewResponse(&orderv1.GetOrderResponse{
 		Order: entityToProto(order),
 	}), nil
 }
-
-func (h *getOrderHandler) validate(req *orderv1.GetOrderRequest) error {
-	if req.Id == "" {
-		return connect.NewError(connect.CodeInvalidArgument, nil)
-	}
-	return nil
-}
@@ -8,6 +8,7 @@ import (
 
 	"connectrpc.com/connect"
 	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/auth"
 	"github.com/demo/order/internal/entity"
 	"github.com/demo/order/internal/store"
 	"github.com/stretchr/testify/assert"
@@ -41,7 +42,7 @@ func TestGetOrderHandler(t *testing.T) {
 		handler := newGetOrderHandler(mockStore)
 
 		return &testData{
-			ctx:       context.Background(),
+			ctx:       auth.WithDisabled(context.Background()),
 			t:         t,
 			handler:   handler,
 			mockStore: mockStore,
@@ -55,8 +56,8 @@ func TestGetOrderHandler(t *testing.T) {
 				expectedOrder := &entity.Order{
 					ID:        "order-123",
 					UserID:    "user-456",
-					Item:      "Test Item",
-					Amount:    99.99,
+					Items:     []entity.OrderItem{{Name: "Test Item", Quantity: 1}},
+					Amount:    entity.NewMoney(9999, entity.DefaultCurrency),
 					Status:    entity.OrderStatusNew,
 					CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
 				}
@@ -88,10 +89,13 @@ func TestGetOrderHandler(t *testing.T) {
 			},
 		},
 		{
-			name: "Should return InvalidArgument when id is empty",
+			name: "Should return NotFound when order does not exist",
 			given: func(td *testData) {
+				td.mockStore.GetFunc = func(ctx context.Context, id string) (*entity.Order, error) {
+					return nil, store.ErrOrderNotFound
+				}
 				td.request = connect.NewRequest(&orderv1.GetOrderRequest{
-					Id: "",
+					Id: "non-existent-order",
 				})
 			},
 			when: func(td *testData) {
@@ -99,18 +103,18 @@ func TestGetOrderHandler(t *testing.T) {
 			},
 			then: func(td *testData) {
 				require.Error(td.t, td.err)
-				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Equal(td.t, connect.CodeNotFound, connect.CodeOf(td.err))
 				assert.Nil(td.t, td.response)
 			},
 		},
 		{
-			name: "Should return NotFound when order does not exist",
+			name: "Should return Internal error when store returns unexpected error",
 			given: func(td *testData) {
 				td.mockStore.GetFunc = func(ctx context.Context, id string) (*entity.Order, error) {
-					return nil, store.ErrOrderNotFound
+					return nil, errors.New("database connection failed")
 				}
 				td.request = connect.NewRequest(&orderv1.GetOrderRequest{
-					Id: "non-existent-order",
+					Id: "order-123",
 				})
 			},
 			when: func(td *testData) {
@@ -118,15 +122,16 @@ func TestGetOrderHandler(t *testing.T) {
 			},
 			then: func(td *testData) {
 				require.Error(td.t, td.err)
-				assert.Equal(td.t, connect.CodeNotFound, connect.CodeOf(td.err))
+				assert.Equal(td.t, connect.CodeInternal, connect.CodeOf(td.err))
 				assert.Nil(td.t, td.response)
 			},
 		},
 		{
-			name: "Should return Internal error when store returns unexpected error",
+			name: "Should return NotFound when order belongs to another user",
 			given: func(td *testData) {
+				td.ctx = asUser("user-789")
 				td.mockStore.GetFunc = func(ctx context.Context, id string) (*entity.Order, error) {
-					return nil, errors.New("database connection failed")
+					return &entity.Order{ID: id, UserID: "user-456", Status: entity.OrderStatusNew}, nil
 				}
 				td.request = connect.NewRequest(&orderv1.GetOrderRequest{
 					Id: "order-123",
@@ -137,7 +142,65 @@ func TestGetOrderHandler(t *testing.T) {
 			},
 			then: func(td *testData) {
 				require.Error(td.t, td.err)
-				assert.Equal(td.t, connect.CodeInternal, connect.CodeOf(td.err))
+				assert.Equal(td.t, connect.CodeNotFound, connect.CodeOf(td.err), "Other users should not learn that the order exists")
+				assert.Nil(td.t, td.response)
+			},
+		},
+		{
+			name: "Should return order of another user to support",
+			given: func(td *testData) {
+				td.ctx = asUser("agent-1", auth.RoleSupport)
+				td.mockStore.GetFunc = func(ctx context.Context, id string) (*entity.Order, error) {
+					return &entity.Order{ID: id, UserID: "user-456", Status: entity.OrderStatusNew}, nil
+				}
+				td.request = connect.NewRequest(&orderv1.GetOrderRequest{
+					Id: "order-123",
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.Equal(td.t, "user-456", td.response.Msg.Order.UserId)
+			},
+		},
+		{
+			name: "Should return deleted order to admin when include_deleted is set",
+			given: func(td *testData) {
+				td.ctx = asUser("admin-1", auth.RoleAdmin)
+				deletedAt := time.Date(2024, 1, 17, 9, 0, 0, 0, time.UTC)
+				td.mockStore.GetIncludingDeletedFunc = func(ctx context.Context, id string) (*entity.Order, error) {
+					return &entity.Order{ID: id, UserID: "user-456", Status: entity.OrderStatusNew, DeletedAt: &deletedAt}, nil
+				}
+				td.request = connect.NewRequest(&orderv1.GetOrderRequest{
+					Id:             "order-123",
+					IncludeDeleted: true,
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.Equal(td.t, "2024-01-17T09:00:00Z", td.response.Msg.Order.DeletedAt)
+			},
+		},
+		{
+			name: "Should return PermissionDenied when non-admin sets include_deleted",
+			given: func(td *testData) {
+				td.ctx = asUser("agent-1", auth.RoleSupport)
+				td.request = connect.NewRequest(&orderv1.GetOrderRequest{
+					Id:             "order-123",
+					IncludeDeleted: true,
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodePermissionDenied, connect.CodeOf(td.err))
 				assert.Nil(td.t, td.response)
 			},
 		},
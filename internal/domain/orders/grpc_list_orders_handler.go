@@ -2,10 +2,22 @@ package orders
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"connectrpc.com/connect"
 	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/logging"
 	"github.com/demo/order/internal/store"
+	"github.com/demo/order/internal/tracing"
+	"github.com/demo/order/internal/validation"
+)
+
+const (
+	defaultListOrdersPageSize = 50
+	maxListOrdersPageSize     = 500
 )
 
 type listOrdersHandler struct {
@@ -20,25 +32,136 @@ func (h *listOrdersHandler) Handle(
 	ctx context.Context,
 	req *connect.Request[orderv1.ListOrdersRequest],
 ) (*connect.Response[orderv1.ListOrdersResponse], error) {
-	if err := h.validate(req.Msg); err != nil {
+	if err := tracing.Run(ctx, "ListOrders.validate", func() error { return h.validate(req.Msg) }); err != nil {
+		return nil, err
+	}
+
+	query := h.query(req.Msg)
+	policy, err := policyFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := policy.userFilter(query.UserID)
+	if err != nil {
 		return nil, err
 	}
+	query.UserID = userID
+	if query.IncludeDeleted {
+		if err := policy.checkDeleted(); err != nil {
+			return nil, err
+		}
+	}
 
-	orders, err := h.store.List(ctx)
+	page, err := h.store.List(ctx, query)
 	if err != nil {
+		if errors.Is(err, store.ErrInvalidPageToken) {
+			return nil, validation.Error("page_token", err.Error())
+		}
+		logging.FromContext(ctx).Error("Failed to list orders", "error", err)
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
-	protoOrders := make([]*orderv1.Order, len(orders))
-	for i, o := range orders {
+	protoOrders := make([]*orderv1.Order, len(page.Orders))
+	for i, o := range page.Orders {
 		protoOrders[i] = entityToProto(o)
 	}
 
 	return connect.NewResponse(&orderv1.ListOrdersResponse{
-		Orders: protoOrders,
+		Orders:        protoOrders,
+		NextPageToken: page.NextPageToken,
 	}), nil
 }
 
-func (h *listOrdersHandler) validate(_ *orderv1.ListOrdersRequest) error {
-	return nil
+// query builds the store query from an already validated request.
+func (h *listOrdersHandler) query(req *orderv1.ListOrdersRequest) store.ListQuery {
+	pageSize := int(req.PageSize)
+	switch {
+	case pageSize == 0:
+		pageSize = defaultListOrdersPageSize
+	case pageSize > maxListOrdersPageSize:
+		pageSize = maxListOrdersPageSize
+	}
+
+	minAmount, _ := parseAmountFilter(req.MinAmount, req.Currency)
+	maxAmount, _ := parseAmountFilter(req.MaxAmount, req.Currency)
+	createdAfter, _ := parseTimeFilter(req.CreatedAfter)
+	createdBefore, _ := parseTimeFilter(req.CreatedBefore)
+
+	return store.ListQuery{
+		PageSize:       pageSize,
+		PageToken:      req.PageToken,
+		UserID:         req.UserId,
+		Status:         entity.OrderStatus(req.Status),
+		Currency:       req.Currency,
+		MinAmount:      minAmount,
+		MaxAmount:      maxAmount,
+		CreatedAfter:   createdAfter,
+		CreatedBefore:  createdBefore,
+		IncludeDeleted: req.IncludeDeleted,
+	}
+}
+
+func (h *listOrdersHandler) validate(req *orderv1.ListOrdersRequest) error {
+	var v validation.Violations
+	if !v.Check(req.Currency == "" || entity.IsKnownCurrency(req.Currency), "currency", fmt.Sprintf("unknown currency %q", req.Currency)) {
+		// The amount filters cannot be checked without a known currency.
+		return v.Err()
+	}
+	// Amounts in different currencies are not comparable.
+	if v.Check(req.Currency != "" || (req.MinAmount == nil && req.MaxAmount == nil), "currency", "must be set with min_amount or max_amount") {
+		minAmount, err := parseAmountFilter(req.MinAmount, req.Currency)
+		if err != nil {
+			v.Add("min_amount", err.Error())
+		}
+		maxAmount, err := parseAmountFilter(req.MaxAmount, req.Currency)
+		if err != nil {
+			v.Add("max_amount", err.Error())
+		}
+		if minAmount != nil && maxAmount != nil {
+			v.Check(minAmount.Cmp(*maxAmount) <= 0, "min_amount", "must not be greater than max_amount")
+		}
+	}
+
+	createdAfter, err := parseTimeFilter(req.CreatedAfter)
+	if err != nil {
+		v.Add("created_after", "must be an RFC 3339 timestamp")
+	}
+	createdBefore, err := parseTimeFilter(req.CreatedBefore)
+	if err != nil {
+		v.Add("created_before", "must be an RFC 3339 timestamp")
+	}
+	if createdAfter != nil && createdBefore != nil {
+		v.Check(createdAfter.Before(*createdBefore), "created_after", "must be before created_before")
+	}
+	return v.Err()
+}
+
+// parseAmountFilter converts an optional non-negative amount bound, returning nil if it is not set.
+// The bound may not be finer than the minor unit of the currency filter.
+func parseAmountFilter(value *float64, currency string) (*entity.Money, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if *value < 0 {
+		return nil, errors.New("must not be negative")
+	}
+	amount, err := entity.MoneyFromFloat(*value, currency)
+	if err != nil {
+		return nil, errors.New(moneyViolation(err, currency))
+	}
+	return &amount, nil
+}
+
+// parseTimeFilter parses an optional RFC 3339 timestamp, returning nil for an empty value.
+// The result is in UTC like created_at, which is stored without a time zone.
+func parseTimeFilter(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	t = t.UTC()
+	return &t, nil
 }
@@ -8,8 +8,10 @@ import (
 
 	"connectrpc.com/connect"
 	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/auth"
 	"github.com/demo/order/internal/entity"
 	"github.com/demo/order/internal/store"
+	"github.com/demo/order/internal/validation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -27,6 +29,7 @@ func TestListOrdersHandler(t *testing.T) {
 
 		// Helper fields for test setup
 		listCalled bool
+		listQuery  store.ListQuery
 	}
 
 	// Define testCase struct locally - GWT pattern is MANDATORY
@@ -39,22 +42,23 @@ func TestListOrdersHandler(t *testing.T) {
 
 	// Setup function creates isolated test data for each test case
 	setupTestData := func(t *testing.T) *testData {
-		mockStore := &store.MockOrderStore{}
+		td := &testData{
+			ctx:       auth.WithDisabled(context.Background()),
+			t:         t,
+			mockStore: &store.MockOrderStore{},
+			request:   connect.NewRequest(&orderv1.ListOrdersRequest{}),
+		}
 
-		// Setup default mock behavior (empty list)
-		mockStore.ListFunc = func(ctx context.Context) ([]*entity.Order, error) {
-			return []*entity.Order{}, nil
+		// Setup default mock behavior (empty list) that captures the query
+		td.mockStore.ListFunc = func(ctx context.Context, query store.ListQuery) (*store.ListPage, error) {
+			td.listCalled = true
+			td.listQuery = query
+			return &store.ListPage{Orders: []*entity.Order{}}, nil
 		}
 
-		handler := newListOrdersHandler(mockStore)
+		td.handler = newListOrdersHandler(td.mockStore)
 
-		return &testData{
-			ctx:       context.Background(),
-			t:         t,
-			handler:   handler,
-			mockStore: mockStore,
-			request:   connect.NewRequest(&orderv1.ListOrdersRequest{}),
-		}
+		return td
 	}
 
 	testCases := []testCase{
@@ -62,9 +66,9 @@ func TestListOrdersHandler(t *testing.T) {
 		{
 			name: "Should return empty list when no orders exist",
 			given: func(td *testData) {
-				td.mockStore.ListFunc = func(ctx context.Context) ([]*entity.Order, error) {
+				td.mockStore.ListFunc = func(ctx context.Context, query store.ListQuery) (*store.ListPage, error) {
 					td.listCalled = true
-					return []*entity.Order{}, nil
+					return &store.ListPage{Orders: []*entity.Order{}}, nil
 				}
 			},
 			when: func(td *testData) {
@@ -83,18 +87,18 @@ func TestListOrdersHandler(t *testing.T) {
 			name: "Should return single order when one order exists",
 			given: func(td *testData) {
 				createdAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
-				td.mockStore.ListFunc = func(ctx context.Context) ([]*entity.Order, error) {
+				td.mockStore.ListFunc = func(ctx context.Context, query store.ListQuery) (*store.ListPage, error) {
 					td.listCalled = true
-					return []*entity.Order{
+					return &store.ListPage{Orders: []*entity.Order{
 						{
 							ID:        "order-001",
 							UserID:    "user-123",
-							Item:      "Widget",
-							Amount:    99.99,
+							Items:     []entity.OrderItem{{Name: "Widget", Quantity: 1}},
+							Amount:    entity.NewMoney(9999, entity.DefaultCurrency),
 							Status:    entity.OrderStatusNew,
 							CreatedAt: createdAt,
 						},
-					}, nil
+					}}, nil
 				}
 			},
 			when: func(td *testData) {
@@ -123,34 +127,34 @@ func TestListOrdersHandler(t *testing.T) {
 				createdAt1 := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
 				createdAt2 := time.Date(2024, 1, 16, 14, 45, 0, 0, time.UTC)
 				createdAt3 := time.Date(2024, 1, 17, 9, 0, 0, 0, time.UTC)
-				td.mockStore.ListFunc = func(ctx context.Context) ([]*entity.Order, error) {
+				td.mockStore.ListFunc = func(ctx context.Context, query store.ListQuery) (*store.ListPage, error) {
 					td.listCalled = true
-					return []*entity.Order{
+					return &store.ListPage{Orders: []*entity.Order{
 						{
 							ID:        "order-003",
 							UserID:    "user-789",
-							Item:      "Gadget Pro",
-							Amount:    299.99,
+							Items:     []entity.OrderItem{{Name: "Gadget Pro", Quantity: 1}},
+							Amount:    entity.NewMoney(29999, entity.DefaultCurrency),
 							Status:    entity.OrderStatusFinished,
 							CreatedAt: createdAt3,
 						},
 						{
 							ID:        "order-002",
 							UserID:    "user-456",
-							Item:      "Super Gadget",
-							Amount:    199.99,
+							Items:     []entity.OrderItem{{Name: "Super Gadget", Quantity: 1}},
+							Amount:    entity.NewMoney(19999, entity.DefaultCurrency),
 							Status:    entity.OrderStatusInProgress,
 							CreatedAt: createdAt2,
 						},
 						{
 							ID:        "order-001",
 							UserID:    "user-123",
-							Item:      "Widget",
-							Amount:    99.99,
+							Items:     []entity.OrderItem{{Name: "Widget", Quantity: 1}},
+							Amount:    entity.NewMoney(9999, entity.DefaultCurrency),
 							Status:    entity.OrderStatusNew,
 							CreatedAt: createdAt1,
 						},
-					}, nil
+					}}, nil
 				}
 			},
 			when: func(td *testData) {
@@ -188,11 +192,235 @@ func TestListOrdersHandler(t *testing.T) {
 			},
 		},
 
+		// Pagination: default page size
+		{
+			name:  "Should use default page size when page_size is not set",
+			given: func(_ *testData) {},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.Equal(td.t, defaultListOrdersPageSize, td.listQuery.PageSize)
+				assert.Empty(td.t, td.listQuery.PageToken)
+			},
+		},
+
+		// Pagination: page size is capped
+		{
+			name: "Should cap page size at the maximum",
+			given: func(td *testData) {
+				td.request.Msg.PageSize = maxListOrdersPageSize + 1
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.Equal(td.t, maxListOrdersPageSize, td.listQuery.PageSize)
+			},
+		},
+
+		// Pagination: token round-trip
+		{
+			name: "Should pass page token to store and return next page token",
+			given: func(td *testData) {
+				td.request.Msg.PageSize = 2
+				td.request.Msg.PageToken = "token-page-2"
+				td.mockStore.ListFunc = func(ctx context.Context, query store.ListQuery) (*store.ListPage, error) {
+					td.listQuery = query
+					return &store.ListPage{
+						Orders: []*entity.Order{
+							{ID: "order-003", Status: entity.OrderStatusNew},
+							{ID: "order-002", Status: entity.OrderStatusNew},
+						},
+						NextPageToken: "token-page-3",
+					}, nil
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.Equal(td.t, 2, td.listQuery.PageSize)
+				assert.Equal(td.t, "token-page-2", td.listQuery.PageToken)
+				assert.Len(td.t, td.response.Msg.Orders, 2)
+				assert.Equal(td.t, "token-page-3", td.response.Msg.NextPageToken)
+			},
+		},
+
+		// Filters: all filters are passed to store
+		{
+			name: "Should pass filters to store",
+			given: func(td *testData) {
+				minAmount, maxAmount := 10.0, 100.0
+				td.request.Msg = &orderv1.ListOrdersRequest{
+					UserId:        "user-123",
+					Status:        "IN_PROGRESS",
+					Currency:      entity.DefaultCurrency,
+					MinAmount:     &minAmount,
+					MaxAmount:     &maxAmount,
+					CreatedAfter:  "2024-01-01T00:00:00Z",
+					CreatedBefore: "2024-02-01T03:00:00+03:00",
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				q := td.listQuery
+				assert.Equal(td.t, "user-123", q.UserID)
+				assert.Equal(td.t, entity.OrderStatusInProgress, q.Status)
+				require.NotNil(td.t, q.MinAmount)
+				require.NotNil(td.t, q.MaxAmount)
+				assert.Equal(td.t, entity.NewMoney(1000, entity.DefaultCurrency), *q.MinAmount)
+				assert.Equal(td.t, entity.NewMoney(10000, entity.DefaultCurrency), *q.MaxAmount)
+				require.NotNil(td.t, q.CreatedAfter)
+				require.NotNil(td.t, q.CreatedBefore)
+				assert.Equal(td.t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), *q.CreatedAfter)
+				assert.Equal(td.t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), *q.CreatedBefore)
+			},
+		},
+
+		// Filters: amount bounds use the precision of the currency filter
+		{
+			name: "Should pass currency filter and parse amount bounds in that currency",
+			given: func(td *testData) {
+				minAmount := 1.005
+				td.request.Msg = &orderv1.ListOrdersRequest{
+					Currency:  "KWD",
+					MinAmount: &minAmount,
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.Equal(td.t, "KWD", td.listQuery.Currency)
+				require.NotNil(td.t, td.listQuery.MinAmount)
+				assert.Equal(td.t, entity.NewMoney(1005, "KWD"), *td.listQuery.MinAmount)
+			},
+		},
+
+		// Validation errors
+		{
+			name: "Should return InvalidArgument when currency is unknown",
+			given: func(td *testData) {
+				td.request.Msg.Currency = "XXX"
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.False(td.t, td.listCalled, "Store.List should not be called on validation error")
+			},
+		},
+		{
+			name: "Should return InvalidArgument when min_amount is greater than max_amount",
+			given: func(td *testData) {
+				minAmount, maxAmount := 100.0, 10.0
+				td.request.Msg.Currency = entity.DefaultCurrency
+				td.request.Msg.MinAmount = &minAmount
+				td.request.Msg.MaxAmount = &maxAmount
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.False(td.t, td.listCalled, "Store.List should not be called on validation error")
+			},
+		},
+		{
+			name: "Should return InvalidArgument when amount filter has sub-cent precision",
+			given: func(td *testData) {
+				minAmount := 10.005
+				td.request.Msg.Currency = entity.DefaultCurrency
+				td.request.Msg.MinAmount = &minAmount
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.False(td.t, td.listCalled, "Store.List should not be called on validation error")
+			},
+		},
+		{
+			name: "Should return InvalidArgument when amount filter has no currency",
+			given: func(td *testData) {
+				maxAmount := 100.0
+				td.request.Msg.MaxAmount = &maxAmount
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Equal(td.t, map[string]string{"currency": "must be set with min_amount or max_amount"}, validation.FieldViolations(td.err))
+				assert.False(td.t, td.listCalled, "Store.List should not be called on validation error")
+			},
+		},
+		{
+			name: "Should return InvalidArgument when created_after is not RFC 3339",
+			given: func(td *testData) {
+				td.request.Msg.CreatedAfter = "yesterday"
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.False(td.t, td.listCalled, "Store.List should not be called on validation error")
+			},
+		},
+		{
+			name: "Should return InvalidArgument when created_after is not before created_before",
+			given: func(td *testData) {
+				td.request.Msg.CreatedAfter = "2024-02-01T00:00:00Z"
+				td.request.Msg.CreatedBefore = "2024-01-01T00:00:00Z"
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.False(td.t, td.listCalled, "Store.List should not be called on validation error")
+			},
+		},
+		{
+			name: "Should return InvalidArgument when store rejects page token",
+			given: func(td *testData) {
+				td.request.Msg.PageToken = "garbage"
+				td.mockStore.ListFunc = func(ctx context.Context, query store.ListQuery) (*store.ListPage, error) {
+					return nil, store.ErrInvalidPageToken
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Nil(td.t, td.response)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+			},
+		},
+
 		// Error scenario: store returns error
 		{
 			name: "Should return Internal error when store fails",
 			given: func(td *testData) {
-				td.mockStore.ListFunc = func(ctx context.Context) ([]*entity.Order, error) {
+				td.mockStore.ListFunc = func(ctx context.Context, query store.ListQuery) (*store.ListPage, error) {
 					td.listCalled = true
 					return nil, errors.New("database connection lost")
 				}
@@ -208,6 +436,101 @@ func TestListOrdersHandler(t *testing.T) {
 				assert.True(td.t, td.listCalled, "Store.List should be called")
 			},
 		},
+		{
+			name: "Should list own orders when user_id is empty",
+			given: func(td *testData) {
+				td.ctx = asUser("user-789")
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.Equal(td.t, "user-789", td.listQuery.UserID, "Users should only list their own orders")
+			},
+		},
+		{
+			name: "Should return PermissionDenied when listing orders of another user",
+			given: func(td *testData) {
+				td.ctx = asUser("user-789")
+				td.request = connect.NewRequest(&orderv1.ListOrdersRequest{UserId: "user-123"})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodePermissionDenied, connect.CodeOf(td.err))
+				assert.False(td.t, td.listCalled, "Store.List should not be called")
+			},
+		},
+		{
+			name: "Should list orders of every user for admins",
+			given: func(td *testData) {
+				td.ctx = asUser("admin-1", auth.RoleAdmin)
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.Empty(td.t, td.listQuery.UserID)
+			},
+		},
+		{
+			name: "Should report every field violation",
+			given: func(td *testData) {
+				minAmount, maxAmount := 20.0, 10.0
+				td.request = connect.NewRequest(&orderv1.ListOrdersRequest{
+					Currency:      entity.DefaultCurrency,
+					MinAmount:     &minAmount,
+					MaxAmount:     &maxAmount,
+					CreatedAfter:  "yesterday",
+					CreatedBefore: "2024-01-01T00:00:00Z",
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Equal(td.t, map[string]string{
+					"min_amount":    "must not be greater than max_amount",
+					"created_after": "must be an RFC 3339 timestamp",
+				}, validation.FieldViolations(td.err))
+				assert.False(td.t, td.listCalled, "Store.List should not be called")
+			},
+		},
+		{
+			name: "Should pass include_deleted to store for admins",
+			given: func(td *testData) {
+				td.ctx = asUser("admin-1", auth.RoleAdmin)
+				td.request = connect.NewRequest(&orderv1.ListOrdersRequest{IncludeDeleted: true})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.True(td.t, td.listQuery.IncludeDeleted)
+			},
+		},
+		{
+			name: "Should return PermissionDenied when non-admin sets include_deleted",
+			given: func(td *testData) {
+				td.ctx = asUser("user-789")
+				td.request = connect.NewRequest(&orderv1.ListOrdersRequest{IncludeDeleted: true})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodePermissionDenied, connect.CodeOf(td.err))
+				assert.False(td.t, td.listCalled, "Store.List should not be called")
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,48 @@
+package orders
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/logging"
+	"github.com/demo/order/internal/store"
+)
+
+type restoreOrderHandler struct {
+	store store.OrderStore
+}
+
+func newRestoreOrderHandler(store store.OrderStore) *restoreOrderHandler {
+	return &restoreOrderHandler{store: store}
+}
+
+func (h *restoreOrderHandler) Handle(
+	ctx context.Context,
+	req *connect.Request[orderv1.RestoreOrderRequest],
+) (*connect.Response[orderv1.RestoreOrderResponse], error) {
+	policy, err := policyFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.checkDeleted(); err != nil {
+		return nil, err
+	}
+
+	order, err := h.store.Restore(ctx, req.Msg.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrOrderNotFound):
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		case errors.Is(err, store.ErrOrderNotDeleted):
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+		}
+		logging.FromContext(ctx).Error("Failed to restore order", "order_id", req.Msg.Id, "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&orderv1.RestoreOrderResponse{
+		Order: entityToProto(order),
+	}), nil
+}
@@ -0,0 +1,170 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/auth"
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreOrderHandler(t *testing.T) {
+	// testData holds all data needed for each test case
+	type testData struct {
+		ctx       context.Context
+		t         *testing.T
+		handler   *restoreOrderHandler
+		mockStore *store.MockOrderStore
+		request   *connect.Request[orderv1.RestoreOrderRequest]
+		response  *connect.Response[orderv1.RestoreOrderResponse]
+		err       error
+
+		// Track Restore calls for verification
+		restoreCalls []string
+	}
+
+	// testCase defines GWT structure for each test scenario
+	type testCase struct {
+		name  string
+		given func(*testData)
+		when  func(*testData)
+		then  func(*testData)
+	}
+
+	// setupTestData creates isolated test data for each test case
+	setupTestData := func(t *testing.T) *testData {
+		td := &testData{
+			ctx:       auth.WithDisabled(context.Background()),
+			t:         t,
+			mockStore: &store.MockOrderStore{},
+			request:   connect.NewRequest(&orderv1.RestoreOrderRequest{Id: "order-123"}),
+		}
+
+		// Default mock behavior: the order is deleted and gets restored
+		td.mockStore.RestoreFunc = func(_ context.Context, id string) (*entity.Order, error) {
+			td.restoreCalls = append(td.restoreCalls, id)
+			return &entity.Order{
+				ID:        id,
+				UserID:    "user-123",
+				Items:     []entity.OrderItem{{Name: "Test Item", Quantity: 1}},
+				Amount:    entity.NewMoney(10000, entity.DefaultCurrency),
+				Status:    entity.OrderStatusNew,
+				CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			}, nil
+		}
+
+		td.handler = newRestoreOrderHandler(td.mockStore)
+
+		return td
+	}
+
+	testCases := []testCase{
+		{
+			name: "Should restore deleted order successfully",
+			given: func(td *testData) {
+				td.ctx = asUser("admin-1", auth.RoleAdmin)
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.NotNil(td.t, td.response)
+				assert.Equal(td.t, []string{"order-123"}, td.restoreCalls)
+				assert.Equal(td.t, "order-123", td.response.Msg.Order.Id)
+				assert.Empty(td.t, td.response.Msg.Order.DeletedAt)
+			},
+		},
+		{
+			name: "Should return NotFound when order does not exist",
+			given: func(td *testData) {
+				td.mockStore.RestoreFunc = func(_ context.Context, _ string) (*entity.Order, error) {
+					return nil, store.ErrOrderNotFound
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeNotFound, connect.CodeOf(td.err))
+			},
+		},
+		{
+			name: "Should return FailedPrecondition when order is not deleted",
+			given: func(td *testData) {
+				td.mockStore.RestoreFunc = func(_ context.Context, _ string) (*entity.Order, error) {
+					return nil, store.ErrOrderNotDeleted
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeFailedPrecondition, connect.CodeOf(td.err))
+			},
+		},
+		{
+			name: "Should return Internal error when store fails",
+			given: func(td *testData) {
+				td.mockStore.RestoreFunc = func(_ context.Context, _ string) (*entity.Order, error) {
+					return nil, errors.New("database connection lost")
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInternal, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+			},
+		},
+		{
+			name: "Should return PermissionDenied when caller is not an admin",
+			given: func(td *testData) {
+				td.ctx = asUser("user-123")
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodePermissionDenied, connect.CodeOf(td.err))
+				assert.Empty(td.t, td.restoreCalls, "Store.Restore should not be called")
+			},
+		},
+		{
+			name: "Should return PermissionDenied to support",
+			given: func(td *testData) {
+				td.ctx = asUser("agent-1", auth.RoleSupport)
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodePermissionDenied, connect.CodeOf(td.err))
+				assert.Empty(td.t, td.restoreCalls, "Store.Restore should not be called")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			td := setupTestData(t)
+			td.t = t
+			tc.given(td)
+			tc.when(td)
+			tc.then(td)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/logging"
+	"github.com/demo/order/internal/store"
+	"github.com/demo/order/internal/tracing"
+	"github.com/demo/order/internal/validation"
+)
+
+type updateOrderStatusHandler struct {
+	store store.OrderStore
+}
+
+func newUpdateOrderStatusHandler(store store.OrderStore) *updateOrderStatusHandler {
+	return &updateOrderStatusHandler{store: store}
+}
+
+func (h *updateOrderStatusHandler) Handle(
+	ctx context.Context,
+	req *connect.Request[orderv1.UpdateOrderStatusRequest],
+) (*connect.Response[orderv1.UpdateOrderStatusResponse], error) {
+	if err := tracing.Run(ctx, "UpdateOrderStatus.validate", func() error { return h.validate(req.Msg) }); err != nil {
+		return nil, err
+	}
+	policy, err := policyFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.checkStatusChange(); err != nil {
+		return nil, err
+	}
+
+	order, err := h.store.Get(ctx, req.Msg.Id)
+	if err != nil {
+		if errors.Is(err, store.ErrOrderNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+		logging.FromContext(ctx).Error("Failed to get order", "order_id", req.Msg.Id, "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	status := entity.OrderStatus(req.Msg.Status)
+	if !canTransitionOrderStatus(order.Status, status) {
+		return nil, connect.NewError(
+			connect.CodeFailedPrecondition,
+			fmt.Errorf("order status cannot change from %s to %s", order.Status, status),
+		)
+	}
+
+	updated, err := h.store.UpdateStatus(ctx, order.ID, order.Status, status)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrOrderNotFound):
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		case errors.Is(err, store.ErrOrderStatusConflict):
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+		}
+		logging.FromContext(ctx).Error("Failed to update order status", "order_id", order.ID, "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&orderv1.UpdateOrderStatusResponse{
+		Order: entityToProto(updated),
+	}), nil
+}
+
+func (h *updateOrderStatusHandler) validate(req *orderv1.UpdateOrderStatusRequest) error {
+	var v validation.Violations
+	v.Check(entity.OrderStatus(req.Status) != entity.OrderStatusCancelled, "status", "orders are cancelled via CancelOrder")
+	return v.Err()
+}
@@ -0,0 +1,337 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/auth"
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateOrderStatusHandler(t *testing.T) {
+	// testData holds all data needed for each test case
+	type testData struct {
+		ctx       context.Context
+		t         *testing.T
+		handler   *updateOrderStatusHandler
+		mockStore *store.MockOrderStore
+		request   *connect.Request[orderv1.UpdateOrderStatusRequest]
+		response  *connect.Response[orderv1.UpdateOrderStatusResponse]
+		err       error
+
+		// Track UpdateStatus calls for verification
+		updateCalls []entity.OrderStatus
+	}
+
+	// testCase defines GWT structure for each test scenario
+	type testCase struct {
+		name  string
+		given func(*testData)
+		when  func(*testData)
+		then  func(*testData)
+	}
+
+	// setupTestData creates isolated test data for each test case
+	setupTestData := func(t *testing.T) *testData {
+		td := &testData{
+			ctx:       auth.WithDisabled(context.Background()),
+			t:         t,
+			mockStore: &store.MockOrderStore{},
+		}
+
+		// Default mock behavior: the order exists in status NEW
+		td.mockStore.GetFunc = func(_ context.Context, id string) (*entity.Order, error) {
+			return &entity.Order{
+				ID:        id,
+				UserID:    "user-123",
+				Items:     []entity.OrderItem{{Name: "Test Item", Quantity: 1}},
+				Amount:    entity.NewMoney(10000, entity.DefaultCurrency),
+				Status:    entity.OrderStatusNew,
+				CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			}, nil
+		}
+		td.mockStore.UpdateStatusFunc = func(
+			_ context.Context,
+			id string,
+			from, to entity.OrderStatus,
+		) (*entity.Order, error) {
+			td.updateCalls = append(td.updateCalls, from, to)
+			return &entity.Order{
+				ID:        id,
+				UserID:    "user-123",
+				Items:     []entity.OrderItem{{Name: "Test Item", Quantity: 1}},
+				Amount:    entity.NewMoney(10000, entity.DefaultCurrency),
+				Status:    to,
+				CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			}, nil
+		}
+
+		td.handler = newUpdateOrderStatusHandler(td.mockStore)
+
+		return td
+	}
+
+	testCases := []testCase{
+		// Success scenario: NEW -> IN_PROGRESS
+		{
+			name: "Should move order from NEW to IN_PROGRESS",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+					Id:     "order-123",
+					Status: string(entity.OrderStatusInProgress),
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.NotNil(td.t, td.response)
+				require.NotNil(td.t, td.response.Msg.Order)
+
+				assert.Equal(td.t, "order-123", td.response.Msg.Order.Id)
+				assert.Equal(td.t, "IN_PROGRESS", td.response.Msg.Order.Status)
+				assert.Equal(td.t,
+					[]entity.OrderStatus{entity.OrderStatusNew, entity.OrderStatusInProgress},
+					td.updateCalls,
+					"Store.UpdateStatus should be called with current and target status",
+				)
+			},
+		},
+
+		// Success scenario: IN_PROGRESS -> FINISHED
+		{
+			name: "Should move order from IN_PROGRESS to FINISHED",
+			given: func(td *testData) {
+				td.mockStore.GetFunc = func(_ context.Context, id string) (*entity.Order, error) {
+					return &entity.Order{ID: id, Status: entity.OrderStatusInProgress}, nil
+				}
+				td.request = connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+					Id:     "order-123",
+					Status: string(entity.OrderStatusFinished),
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.NotNil(td.t, td.response)
+				assert.Equal(td.t, "FINISHED", td.response.Msg.Order.Status)
+				assert.Equal(td.t,
+					[]entity.OrderStatus{entity.OrderStatusInProgress, entity.OrderStatusFinished},
+					td.updateCalls,
+				)
+			},
+		},
+
+		// Validation error: cancellation must go through CancelOrder
+		{
+			name: "Should return InvalidArgument when status is CANCELLED",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+					Id:     "order-123",
+					Status: string(entity.OrderStatusCancelled),
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+				assert.Empty(td.t, td.updateCalls, "Store.UpdateStatus should not be called on validation error")
+			},
+		},
+
+		// FailedPrecondition: skipping IN_PROGRESS
+		{
+			name: "Should return FailedPrecondition when jumping from NEW to FINISHED",
+			given: func(td *testData) {
+				td.request = connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+					Id:     "order-123",
+					Status: string(entity.OrderStatusFinished),
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeFailedPrecondition, connect.CodeOf(td.err))
+				assert.Contains(td.t, td.err.Error(), "order status cannot change from NEW to FINISHED")
+				assert.Nil(td.t, td.response)
+				assert.Empty(td.t, td.updateCalls, "Store.UpdateStatus should not be called for illegal transition")
+			},
+		},
+
+		// FailedPrecondition: moving backwards
+		{
+			name: "Should return FailedPrecondition when moving FINISHED order back to NEW",
+			given: func(td *testData) {
+				td.mockStore.GetFunc = func(_ context.Context, id string) (*entity.Order, error) {
+					return &entity.Order{ID: id, Status: entity.OrderStatusFinished}, nil
+				}
+				td.request = connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+					Id:     "order-123",
+					Status: string(entity.OrderStatusNew),
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeFailedPrecondition, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+				assert.Empty(td.t, td.updateCalls)
+			},
+		},
+
+		// FailedPrecondition: concurrent status change
+		{
+			name: "Should return FailedPrecondition when status was changed concurrently",
+			given: func(td *testData) {
+				td.mockStore.UpdateStatusFunc = func(
+					_ context.Context,
+					_ string,
+					_, _ entity.OrderStatus,
+				) (*entity.Order, error) {
+					return nil, store.ErrOrderStatusConflict
+				}
+				td.request = connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+					Id:     "order-123",
+					Status: string(entity.OrderStatusInProgress),
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeFailedPrecondition, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+			},
+		},
+
+		// NotFound: store.Get returns ErrOrderNotFound
+		{
+			name: "Should return NotFound when order does not exist",
+			given: func(td *testData) {
+				td.mockStore.GetFunc = func(_ context.Context, _ string) (*entity.Order, error) {
+					return nil, store.ErrOrderNotFound
+				}
+				td.request = connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+					Id:     "non-existent-order",
+					Status: string(entity.OrderStatusInProgress),
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeNotFound, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+				assert.Empty(td.t, td.updateCalls)
+			},
+		},
+
+		// Internal: store.UpdateStatus returns other error
+		{
+			name: "Should return Internal error when store.UpdateStatus fails",
+			given: func(td *testData) {
+				td.mockStore.UpdateStatusFunc = func(
+					_ context.Context,
+					_ string,
+					_, _ entity.OrderStatus,
+				) (*entity.Order, error) {
+					return nil, errors.New("database connection failed")
+				}
+				td.request = connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+					Id:     "order-123",
+					Status: string(entity.OrderStatusInProgress),
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInternal, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+			},
+		},
+		{
+			name: "Should return PermissionDenied when users change the status of their own orders",
+			given: func(td *testData) {
+				td.ctx = asUser("user-123")
+				td.request = connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+					Id:     "order-123",
+					Status: string(entity.OrderStatusFinished),
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodePermissionDenied, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+				assert.Empty(td.t, td.updateCalls, "Store.UpdateStatus should not be called")
+			},
+		},
+		{
+			name: "Should update order for support",
+			given: func(td *testData) {
+				td.ctx = asUser("support-1", auth.RoleSupport)
+				td.request = connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+					Id:     "order-123",
+					Status: string(entity.OrderStatusInProgress),
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.Equal(td.t, []entity.OrderStatus{entity.OrderStatusNew, entity.OrderStatusInProgress}, td.updateCalls)
+			},
+		},
+		{
+			name: "Should update order of another user for admins",
+			given: func(td *testData) {
+				td.ctx = asUser("admin-1", auth.RoleAdmin)
+				td.request = connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+					Id:     "order-123",
+					Status: string(entity.OrderStatusInProgress),
+				})
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.Equal(td.t, []entity.OrderStatus{entity.OrderStatusNew, entity.OrderStatusInProgress}, td.updateCalls)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			td := setupTestData(t)
+			td.t = t
+			tc.given(td)
+			tc.when(td)
+			tc.then(td)
+		})
+	}
+}
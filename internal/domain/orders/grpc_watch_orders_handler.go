@@ -0,0 +1,219 @@
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/logging"
+	"github.com/demo/order/internal/store"
+	"github.com/demo/order/internal/tracing"
+	"github.com/demo/order/internal/validation"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// watchOrdersReplayBatchSize is the number of events read at once when resuming from a cursor.
+	watchOrdersReplayBatchSize = 500
+	// maxWatchOrdersReplay is the number of events after a cursor that can be replayed.
+	// A client further behind has to reload its orders with ListOrders.
+	maxWatchOrdersReplay = 10000
+)
+
+var (
+	errCursorTooOld = errors.New("cursor is too far behind, reload the orders and watch without a cursor")
+	errShuttingDown = errors.New("server is shutting down")
+)
+
+type watchOrdersHandler struct {
+	store store.OrderStore
+
+	// stopped is closed by stop to end all streams.
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func newWatchOrdersHandler(store store.OrderStore) *watchOrdersHandler {
+	return &watchOrdersHandler{store: store, stopped: make(chan struct{})}
+}
+
+// stop ends the open streams and makes new ones fail with CodeUnavailable.
+func (h *watchOrdersHandler) stop() {
+	h.stopOnce.Do(func() {
+		close(h.stopped)
+	})
+}
+
+func (h *watchOrdersHandler) Handle(
+	ctx context.Context,
+	req *connect.Request[orderv1.WatchOrdersRequest],
+	stream *connect.ServerStream[orderv1.WatchOrdersResponse],
+) error {
+	return h.watch(ctx, req.Msg, stream.Send)
+}
+
+// watch sends the updates of the matching orders until ctx is done. Updates
+// recorded after the cursor are replayed first. send is called with nil to send
+// the response headers once the updates are subscribed to. The stream ends with
+// CodeUnavailable when the client falls behind or the server shuts down, the
+// client is expected to reconnect with the cursor of the last received update.
+func (h *watchOrdersHandler) watch(
+	ctx context.Context,
+	req *orderv1.WatchOrdersRequest,
+	send func(*orderv1.WatchOrdersResponse) error,
+) error {
+	if err := tracing.Run(ctx, "WatchOrders.validate", func() error { return h.validate(req) }); err != nil {
+		return err
+	}
+	policy, err := policyFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	userID, err := policy.userFilter(req.UserId)
+	if err != nil {
+		return err
+	}
+	if userID != req.UserId {
+		// Users who watch without a user filter get the updates of their own orders.
+		req = proto.Clone(req).(*orderv1.WatchOrdersRequest)
+		req.UserId = userID
+	}
+
+	select {
+	case <-h.stopped:
+		return connect.NewError(connect.CodeUnavailable, errShuttingDown)
+	default:
+	}
+
+	// Subscribing before the replay makes sure no update recorded in between is lost.
+	sub, err := h.store.SubscribeOrderEvents(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to subscribe to order events", "error", err)
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	defer sub.Close()
+	// Clients block until the response headers arrive, so they know no update made afterwards is missed.
+	if err := send(nil); err != nil {
+		return err
+	}
+
+	var replayed map[int64]struct{}
+	if req.Cursor != "" {
+		cursor, _ := parseWatchCursor(req.Cursor)
+		if replayed, err = h.replay(ctx, req, cursor, send); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-h.stopped:
+			return connect.NewError(connect.CodeUnavailable, errShuttingDown)
+		case event, ok := <-sub.Events():
+			if !ok {
+				if err := sub.Err(); err != nil {
+					return connect.NewError(connect.CodeUnavailable, err)
+				}
+				return connect.NewError(connect.CodeUnavailable, errors.New("order updates are no longer available"))
+			}
+			// Events recorded while replaying are received twice.
+			if _, ok := replayed[event.ID]; ok {
+				delete(replayed, event.ID)
+				continue
+			}
+			if err := h.send(ctx, req, event, send); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// replay sends the matching updates recorded after cursor and returns the IDs of the replayed events.
+func (h *watchOrdersHandler) replay(
+	ctx context.Context,
+	req *orderv1.WatchOrdersRequest,
+	cursor int64,
+	send func(*orderv1.WatchOrdersResponse) error,
+) (map[int64]struct{}, error) {
+	replayed := make(map[int64]struct{})
+	for {
+		events, err := h.store.ListOrderEvents(ctx, cursor, watchOrdersReplayBatchSize)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to list order events", "cursor", cursor, "error", err)
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		if len(replayed)+len(events) > maxWatchOrdersReplay {
+			return nil, connect.NewError(connect.CodeOutOfRange, errCursorTooOld)
+		}
+
+		for _, event := range events {
+			replayed[event.ID] = struct{}{}
+			if err := h.send(ctx, req, event, send); err != nil {
+				return nil, err
+			}
+			cursor = event.ID
+		}
+		if len(events) < watchOrdersReplayBatchSize {
+			return replayed, nil
+		}
+	}
+}
+
+// send sends the event if the order matches the request filters.
+func (h *watchOrdersHandler) send(
+	ctx context.Context,
+	req *orderv1.WatchOrdersRequest,
+	event entity.OrderEvent,
+	send func(*orderv1.WatchOrdersResponse) error,
+) error {
+	var payload entity.OrderEventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		logging.FromContext(ctx).Error("Failed to decode order event", "event_id", event.ID, "error", err)
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("decode order event %d: %w", event.ID, err))
+	}
+	order, err := payload.Order()
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to decode order event", "event_id", event.ID, "error", err)
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("decode order event %d: %w", event.ID, err))
+	}
+
+	if req.UserId != "" && order.UserID != req.UserId {
+		return nil
+	}
+	if req.Status != "" && order.Status != entity.OrderStatus(req.Status) {
+		return nil
+	}
+
+	return send(&orderv1.WatchOrdersResponse{
+		Cursor:    strconv.FormatInt(event.ID, 10),
+		EventType: string(event.Type),
+		Order:     entityToProto(order),
+	})
+}
+
+func (h *watchOrdersHandler) validate(req *orderv1.WatchOrdersRequest) error {
+	var v validation.Violations
+	if req.Cursor != "" {
+		if _, err := parseWatchCursor(req.Cursor); err != nil {
+			v.Add("cursor", err.Error())
+		}
+	}
+	return v.Err()
+}
+
+// parseWatchCursor parses a cursor returned in WatchOrdersResponse, which is the ID of the order event.
+func parseWatchCursor(cursor string) (int64, error) {
+	id, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	return id, nil
+}
@@ -0,0 +1,393 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/auth"
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchOrdersHandler(t *testing.T) {
+	// testData contains all data needed for a single test case
+	type testData struct {
+		ctx       context.Context
+		t         *testing.T
+		handler   *watchOrdersHandler
+		mockStore *store.MockOrderStore
+		request   *orderv1.WatchOrdersRequest
+		sent      []*orderv1.WatchOrdersResponse
+		err       error
+
+		headersSent bool
+
+		// Helper fields for test setup
+		liveEvents   []entity.OrderEvent
+		storedEvents []entity.OrderEvent
+		listAfterIDs []int64
+	}
+
+	// testCase defines the GWT structure for each test
+	type testCase struct {
+		name  string
+		given func(*testData)
+		when  func(*testData)
+		then  func(*testData)
+	}
+
+	newEvent := func(t *testing.T, id int64, eventType entity.OrderEventType, userID string, status entity.OrderStatus) entity.OrderEvent {
+		amount := entity.NewMoney(4250, entity.DefaultCurrency)
+		order := &entity.Order{
+			ID:        "order-" + userID,
+			UserID:    userID,
+			Items:     []entity.OrderItem{{SKU: "SKU-1", Name: "Widget", Quantity: 1, UnitPrice: amount}},
+			Amount:    amount,
+			Status:    status,
+			CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		}
+		event, err := entity.NewOrderEvent(eventType, order, "", order.CreatedAt)
+		require.NoError(t, err)
+		event.ID = id
+		return event
+	}
+
+	cursors := func(responses []*orderv1.WatchOrdersResponse) []string {
+		result := make([]string, len(responses))
+		for i, r := range responses {
+			result[i] = r.Cursor
+		}
+		return result
+	}
+
+	// setupTestData creates isolated test data for each test case
+	setupTestData := func(t *testing.T) *testData {
+		td := &testData{
+			ctx:       auth.WithDisabled(context.Background()),
+			t:         t,
+			mockStore: &store.MockOrderStore{},
+			request:   &orderv1.WatchOrdersRequest{},
+		}
+
+		// Default mock behavior: the live events are published right after
+		// subscribing and then the hub is closed, which ends the stream.
+		td.mockStore.SubscribeOrderEventsFunc = func(_ context.Context) (*store.OrderEventSubscription, error) {
+			hub := store.NewOrderEventHub()
+			sub := hub.Subscribe()
+			for _, event := range td.liveEvents {
+				hub.Publish(event)
+			}
+			hub.Close()
+			return sub, nil
+		}
+		td.mockStore.ListOrderEventsFunc = func(_ context.Context, afterID int64, limit int) ([]entity.OrderEvent, error) {
+			td.listAfterIDs = append(td.listAfterIDs, afterID)
+			var events []entity.OrderEvent
+			for _, event := range td.storedEvents {
+				if event.ID > afterID && len(events) < limit {
+					events = append(events, event)
+				}
+			}
+			return events, nil
+		}
+
+		td.handler = newWatchOrdersHandler(td.mockStore)
+
+		return td
+	}
+
+	watch := func(td *testData) {
+		td.err = td.handler.watch(td.ctx, td.request, func(resp *orderv1.WatchOrdersResponse) error {
+			if resp == nil {
+				td.headersSent = true
+				return nil
+			}
+			td.sent = append(td.sent, resp)
+			return nil
+		})
+	}
+
+	testCases := []testCase{
+		{
+			name: "Should stream live order updates",
+			given: func(td *testData) {
+				td.liveEvents = []entity.OrderEvent{
+					newEvent(td.t, 1, entity.OrderEventCreated, "user-1", entity.OrderStatusNew),
+					newEvent(td.t, 2, entity.OrderEventStatusChanged, "user-1", entity.OrderStatusInProgress),
+				}
+			},
+			when: watch,
+			then: func(td *testData) {
+				require.Len(td.t, td.sent, 2)
+				assert.Equal(td.t, "1", td.sent[0].Cursor)
+				assert.Equal(td.t, "ORDER_CREATED", td.sent[0].EventType)
+				assert.Equal(td.t, "order-user-1", td.sent[0].Order.Id)
+				assert.Equal(td.t, "user-1", td.sent[0].Order.UserId)
+				assert.Equal(td.t, "NEW", td.sent[0].Order.Status)
+				assert.Equal(td.t, int64(4250), td.sent[0].Order.AmountMinorUnits)
+				require.Len(td.t, td.sent[0].Order.Items, 1)
+				assert.Equal(td.t, "SKU-1", td.sent[0].Order.Items[0].Sku)
+
+				assert.Equal(td.t, "2", td.sent[1].Cursor)
+				assert.Equal(td.t, "ORDER_STATUS_CHANGED", td.sent[1].EventType)
+				assert.Equal(td.t, "IN_PROGRESS", td.sent[1].Order.Status)
+				assert.True(td.t, td.headersSent, "Response headers should be sent once subscribed")
+				assert.Empty(td.t, td.listAfterIDs, "Nothing should be replayed without a cursor")
+
+				// The stream ends when the updates are no longer available.
+				assert.Equal(td.t, connect.CodeUnavailable, connect.CodeOf(td.err))
+			},
+		},
+		{
+			name: "Should only stream updates of the requested user",
+			given: func(td *testData) {
+				td.request.UserId = "user-1"
+				td.liveEvents = []entity.OrderEvent{
+					newEvent(td.t, 1, entity.OrderEventCreated, "user-1", entity.OrderStatusNew),
+					newEvent(td.t, 2, entity.OrderEventCreated, "user-2", entity.OrderStatusNew),
+				}
+			},
+			when: watch,
+			then: func(td *testData) {
+				assert.Equal(td.t, []string{"1"}, cursors(td.sent))
+			},
+		},
+		{
+			name: "Should only stream updates leaving the order in the requested status",
+			given: func(td *testData) {
+				td.request.Status = "IN_PROGRESS"
+				td.liveEvents = []entity.OrderEvent{
+					newEvent(td.t, 1, entity.OrderEventCreated, "user-1", entity.OrderStatusNew),
+					newEvent(td.t, 2, entity.OrderEventStatusChanged, "user-1", entity.OrderStatusInProgress),
+				}
+			},
+			when: watch,
+			then: func(td *testData) {
+				assert.Equal(td.t, []string{"2"}, cursors(td.sent))
+			},
+		},
+		{
+			name: "Should replay updates after the cursor before the live ones",
+			given: func(td *testData) {
+				td.request.Cursor = "1"
+				td.storedEvents = []entity.OrderEvent{
+					newEvent(td.t, 1, entity.OrderEventCreated, "user-1", entity.OrderStatusNew),
+					newEvent(td.t, 2, entity.OrderEventStatusChanged, "user-1", entity.OrderStatusInProgress),
+					newEvent(td.t, 3, entity.OrderEventCreated, "user-2", entity.OrderStatusNew),
+				}
+				// Event 3 was recorded after subscribing, so it is both replayed and received live.
+				td.liveEvents = []entity.OrderEvent{
+					td.storedEvents[2],
+					newEvent(td.t, 4, entity.OrderEventCreated, "user-3", entity.OrderStatusNew),
+				}
+			},
+			when: watch,
+			then: func(td *testData) {
+				assert.Equal(td.t, []string{"2", "3", "4"}, cursors(td.sent))
+				assert.Equal(td.t, []int64{1}, td.listAfterIDs)
+			},
+		},
+		{
+			name: "Should replay in batches",
+			given: func(td *testData) {
+				td.request.Cursor = "0"
+				for i := int64(1); i <= watchOrdersReplayBatchSize+1; i++ {
+					td.storedEvents = append(td.storedEvents, newEvent(td.t, i, entity.OrderEventCreated, "user-1", entity.OrderStatusNew))
+				}
+			},
+			when: watch,
+			then: func(td *testData) {
+				assert.Len(td.t, td.sent, watchOrdersReplayBatchSize+1)
+				assert.Equal(td.t, []int64{0, watchOrdersReplayBatchSize}, td.listAfterIDs)
+			},
+		},
+		{
+			name: "Should reject a cursor too far behind",
+			given: func(td *testData) {
+				td.request.Cursor = "0"
+				td.mockStore.ListOrderEventsFunc = func(_ context.Context, afterID int64, limit int) ([]entity.OrderEvent, error) {
+					events := make([]entity.OrderEvent, limit)
+					for i := range events {
+						events[i] = newEvent(td.t, afterID+int64(i)+1, entity.OrderEventCreated, "user-1", entity.OrderStatusNew)
+					}
+					return events, nil
+				}
+			},
+			when: watch,
+			then: func(td *testData) {
+				assert.Equal(td.t, connect.CodeOutOfRange, connect.CodeOf(td.err))
+			},
+		},
+		{
+			name: "Should end the stream when the client falls behind",
+			given: func(td *testData) {
+				td.mockStore.SubscribeOrderEventsFunc = func(_ context.Context) (*store.OrderEventSubscription, error) {
+					hub := store.NewOrderEventHub()
+					sub := hub.Subscribe()
+					for i := int64(1); ; i++ {
+						hub.Publish(newEvent(td.t, i, entity.OrderEventCreated, "user-1", entity.OrderStatusNew))
+						if sub.Err() != nil {
+							return sub, nil
+						}
+					}
+				}
+			},
+			when: watch,
+			then: func(td *testData) {
+				assert.Equal(td.t, connect.CodeUnavailable, connect.CodeOf(td.err))
+				assert.ErrorIs(td.t, td.err, store.ErrOrderEventSubscriptionLagged)
+				assert.NotEmpty(td.t, td.sent, "Buffered updates should still be sent")
+			},
+		},
+		{
+			name: "Should end the stream without error when the client goes away",
+			given: func(td *testData) {
+				ctx, cancel := context.WithCancel(td.ctx)
+				cancel()
+				td.ctx = ctx
+				td.mockStore.SubscribeOrderEventsFunc = func(_ context.Context) (*store.OrderEventSubscription, error) {
+					return store.NewOrderEventHub().Subscribe(), nil
+				}
+			},
+			when: watch,
+			then: func(td *testData) {
+				assert.NoError(td.t, td.err)
+			},
+		},
+		{
+			name: "Should end open streams when the server shuts down",
+			given: func(td *testData) {
+				td.mockStore.SubscribeOrderEventsFunc = func(_ context.Context) (*store.OrderEventSubscription, error) {
+					return store.NewOrderEventHub().Subscribe(), nil
+				}
+			},
+			when: func(td *testData) {
+				go td.handler.stop()
+				watch(td)
+			},
+			then: func(td *testData) {
+				assert.Equal(td.t, connect.CodeUnavailable, connect.CodeOf(td.err))
+				assert.ErrorIs(td.t, td.err, errShuttingDown)
+			},
+		},
+		{
+			name: "Should reject new streams after shutdown",
+			given: func(td *testData) {
+				td.handler.stop()
+				td.handler.stop()
+			},
+			when: watch,
+			then: func(td *testData) {
+				assert.Equal(td.t, connect.CodeUnavailable, connect.CodeOf(td.err))
+				assert.False(td.t, td.headersSent)
+			},
+		},
+		{
+			name: "Should return send error",
+			given: func(td *testData) {
+				td.liveEvents = []entity.OrderEvent{
+					newEvent(td.t, 1, entity.OrderEventCreated, "user-1", entity.OrderStatusNew),
+				}
+			},
+			when: func(td *testData) {
+				td.err = td.handler.watch(td.ctx, td.request, func(resp *orderv1.WatchOrdersResponse) error {
+					if resp == nil {
+						return nil
+					}
+					return errors.New("connection reset")
+				})
+			},
+			then: func(td *testData) {
+				assert.EqualError(td.t, td.err, "connection reset")
+			},
+		},
+		{
+			name: "Should return InvalidArgument for malformed cursor",
+			given: func(td *testData) {
+				td.request.Cursor = "abc"
+			},
+			when: watch,
+			then: func(td *testData) {
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+			},
+		},
+		{
+			name: "Should return InvalidArgument for negative cursor",
+			given: func(td *testData) {
+				td.request.Cursor = "-1"
+			},
+			when: watch,
+			then: func(td *testData) {
+				assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+			},
+		},
+		{
+			name: "Should return Internal when subscribing fails",
+			given: func(td *testData) {
+				td.mockStore.SubscribeOrderEventsFunc = func(_ context.Context) (*store.OrderEventSubscription, error) {
+					return nil, errors.New("database connection failed")
+				}
+			},
+			when: watch,
+			then: func(td *testData) {
+				assert.Equal(td.t, connect.CodeInternal, connect.CodeOf(td.err))
+			},
+		},
+		{
+			name: "Should return Internal when replaying fails",
+			given: func(td *testData) {
+				td.request.Cursor = "1"
+				td.mockStore.ListOrderEventsFunc = func(_ context.Context, _ int64, _ int) ([]entity.OrderEvent, error) {
+					return nil, errors.New("database connection failed")
+				}
+			},
+			when: watch,
+			then: func(td *testData) {
+				assert.Equal(td.t, connect.CodeInternal, connect.CodeOf(td.err))
+			},
+		},
+		{
+			name: "Should only stream updates of the caller's orders without user filter",
+			given: func(td *testData) {
+				td.ctx = asUser("user-1")
+				td.liveEvents = []entity.OrderEvent{
+					newEvent(td.t, 1, entity.OrderEventCreated, "user-1", entity.OrderStatusNew),
+					newEvent(td.t, 2, entity.OrderEventCreated, "user-2", entity.OrderStatusNew),
+				}
+			},
+			when: watch,
+			then: func(td *testData) {
+				assert.Equal(td.t, []string{"1"}, cursors(td.sent))
+				assert.Empty(td.t, td.request.UserId, "The request should not be modified")
+			},
+		},
+		{
+			name: "Should return PermissionDenied when watching another user",
+			given: func(td *testData) {
+				td.ctx = asUser("user-1")
+				td.request.UserId = "user-2"
+			},
+			when: watch,
+			then: func(td *testData) {
+				assert.Equal(td.t, connect.CodePermissionDenied, connect.CodeOf(td.err))
+				assert.False(td.t, td.headersSent, "Nothing should be subscribed to")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			td := setupTestData(t)
+			td.t = t
+			tc.given(td)
+			tc.when(td)
+			tc.then(td)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package orders
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/validation"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client-generated key that makes CreateOrder retries safe.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from an earlier request with the same key.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	idempotencyKeyTTL       = 24 * time.Hour
+	maxIdempotencyKeyLength = 255
+)
+
+// idempotencyKey returns the key from the Idempotency-Key header or the request field.
+// An empty key means the request is not idempotent.
+func idempotencyKey(req *connect.Request[orderv1.CreateOrderRequest]) string {
+	if key := req.Header().Get(IdempotencyKeyHeader); key != "" {
+		return key
+	}
+	return req.Msg.IdempotencyKey
+}
+
+// checkIdempotencyKey records the violations of the idempotency key of a request
+// sending headerKey in the Idempotency-Key header.
+func checkIdempotencyKey(v *validation.Violations, req *orderv1.CreateOrderRequest, headerKey string) {
+	v.Check(headerKey == "" || req.IdempotencyKey == "" || headerKey == req.IdempotencyKey,
+		"idempotency_key", "differs from the "+IdempotencyKeyHeader+" header")
+	key := headerKey
+	if key == "" {
+		key = req.IdempotencyKey
+	}
+	v.Check(len(key) <= maxIdempotencyKeyLength, "idempotency_key",
+		fmt.Sprintf("must be at most %d bytes long", maxIdempotencyKeyLength))
+}
+
+// requestFingerprint identifies the payload of a CreateOrder request, ignoring the idempotency key itself.
+func requestFingerprint(req *orderv1.CreateOrderRequest) (string, error) {
+	payload := proto.Clone(req).(*orderv1.CreateOrderRequest)
+	payload.IdempotencyKey = ""
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+type idempotencyRecordDeleter interface {
+	DeleteExpiredIdempotencyRecords(ctx context.Context, now time.Time) (int64, error)
+}
+
+// PurgeExpiredIdempotencyKeys deletes expired idempotency records every interval until ctx is done.
+func PurgeExpiredIdempotencyKeys(ctx context.Context, store idempotencyRecordDeleter, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := store.DeleteExpiredIdempotencyRecords(ctx, time.Now().UTC())
+			if err != nil {
+				slog.Error("Failed to purge expired idempotency keys", "error", err)
+				continue
+			}
+			if deleted > 0 {
+				slog.Info("Purged expired idempotency keys", "deleted", deleted)
+			}
+		}
+	}
+}
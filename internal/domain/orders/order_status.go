@@ -0,0 +1,19 @@
+package orders
+
+import (
+	"slices"
+
+	"github.com/demo/order/internal/entity"
+)
+
+// orderStatusTransitions lists the statuses an order is allowed to move to from each status.
+var orderStatusTransitions = map[entity.OrderStatus][]entity.OrderStatus{
+	entity.OrderStatusNew:        {entity.OrderStatusInProgress, entity.OrderStatusCancelled},
+	entity.OrderStatusInProgress: {entity.OrderStatusFinished, entity.OrderStatusCancelled},
+	entity.OrderStatusFinished:   {},
+	entity.OrderStatusCancelled:  {},
+}
+
+func canTransitionOrderStatus(from, to entity.OrderStatus) bool {
+	return slices.Contains(orderStatusTransitions[from], to)
+}
@@ -0,0 +1,43 @@
+package orders
+
+import (
+	"testing"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// TestContractValues checks that the values the contract rules accept are the ones the domain knows.
+func TestContractValues(t *testing.T) {
+	var statuses []string
+	for status := range orderStatusTransitions {
+		statuses = append(statuses, string(status))
+	}
+	for _, msg := range []proto.Message{
+		&orderv1.ListOrdersRequest{},
+		&orderv1.UpdateOrderStatusRequest{},
+		&orderv1.WatchOrdersRequest{},
+	} {
+		assert.ElementsMatch(t, statuses, contractRules(msg, "status").GetString().GetIn(),
+			"%s.status", msg.ProtoReflect().Descriptor().Name())
+	}
+
+	assert.ElementsMatch(t, []string{
+		string(entity.CancelReasonCustomerRequest),
+		string(entity.CancelReasonCreatedByMistake),
+		string(entity.CancelReasonDuplicate),
+		string(entity.CancelReasonOutOfStock),
+		string(entity.CancelReasonFraudSuspected),
+		string(entity.CancelReasonOther),
+	}, contractRules(&orderv1.CancelOrderRequest{}, "reason").GetString().GetIn())
+}
+
+// contractRules returns the rules of the field of msg.
+func contractRules(msg proto.Message, name protoreflect.Name) *validate.FieldRules {
+	field := msg.ProtoReflect().Descriptor().Fields().ByName(name)
+	return proto.GetExtension(field.Options(), validate.E_Field).(*validate.FieldRules)
+}
@@ -0,0 +1,97 @@
+package orders
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	"github.com/demo/order/internal/auth"
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+)
+
+var (
+	errNoPrincipal = errors.New("caller is not authenticated")
+	errOtherUser   = errors.New("orders of other users are not accessible")
+	errNotAdmin    = errors.New("only admins may access deleted orders")
+	errNotStaff    = errors.New("only support and admins may change the order status")
+)
+
+// policy decides which orders the caller of an RPC may act on. Users act on
+// their own orders, admins and support on the orders of every user. Only
+// admins see and restore deleted orders, only admins and support move orders
+// through their statuses. When authentication is disabled, every order is
+// accessible.
+//
+// An order the caller may not access is reported exactly like a missing
+// order, so that order IDs cannot be probed. A request naming another user,
+// e.g. in a user_id filter, is denied with CodePermissionDenied instead, since
+// that reveals nothing about the orders.
+type policy struct {
+	// userID is the acting user, empty if every order is accessible.
+	userID string
+	// deletedAccessible is set if deleted orders are accessible too.
+	deletedAccessible bool
+}
+
+// policyFromContext returns the policy of the caller. It fails with
+// CodeUnauthenticated for a caller without a principal, unless authentication
+// is disabled, so that no order leaks from a service mounted without auth.Interceptor.
+func policyFromContext(ctx context.Context) (policy, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	switch {
+	case !ok && auth.Disabled(ctx):
+		return policy{deletedAccessible: true}, nil
+	case !ok:
+		return policy{}, connect.NewError(connect.CodeUnauthenticated, errNoPrincipal)
+	case principal.HasRole(auth.RoleAdmin):
+		return policy{deletedAccessible: true}, nil
+	case principal.HasRole(auth.RoleSupport):
+		return policy{}, nil
+	}
+	return policy{userID: principal.Subject}, nil
+}
+
+// checkUser denies requests for the orders of userID, unless the caller may act for that user.
+func (p policy) checkUser(userID string) error {
+	if p.userID != "" && userID != p.userID {
+		return connect.NewError(connect.CodePermissionDenied, errOtherUser)
+	}
+	return nil
+}
+
+// userFilter returns the user whose orders a request filtered by userID
+// returns. Users who leave it empty get their own orders.
+func (p policy) userFilter(userID string) (string, error) {
+	if userID == "" {
+		return p.userID, nil
+	}
+	if err := p.checkUser(userID); err != nil {
+		return "", err
+	}
+	return userID, nil
+}
+
+// checkOrder returns the error of a missing order if the caller may not access order.
+func (p policy) checkOrder(order *entity.Order) error {
+	if p.userID != "" && order.UserID != p.userID {
+		return connect.NewError(connect.CodeNotFound, store.ErrOrderNotFound)
+	}
+	return nil
+}
+
+// checkDeleted denies requests for deleted orders, unless the caller may access them.
+func (p policy) checkDeleted() error {
+	if !p.deletedAccessible {
+		return connect.NewError(connect.CodePermissionDenied, errNotAdmin)
+	}
+	return nil
+}
+
+// checkStatusChange denies status changes to users, who may not finish their own orders.
+func (p policy) checkStatusChange() error {
+	if p.userID != "" {
+		return connect.NewError(connect.CodePermissionDenied, errNotStaff)
+	}
+	return nil
+}
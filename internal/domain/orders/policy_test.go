@@ -0,0 +1,122 @@
+package orders
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/demo/order/internal/auth"
+	"github.com/demo/order/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// asUser returns a context authenticated as userID with roles.
+func asUser(userID string, roles ...string) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{Subject: userID, Roles: roles})
+}
+
+func TestPolicy(t *testing.T) {
+	order := &entity.Order{ID: "order-1", UserID: "user-1"}
+
+	testCases := []struct {
+		name            string
+		ctx             context.Context
+		wantFilter      string
+		wantOwnOrder    bool
+		wantOtherOrders bool
+		wantDeleted     bool
+		// wantStatusChange is set if the caller may change the status of orders.
+		wantStatusChange bool
+	}{
+		{
+			name:             "with authentication disabled every order is accessible",
+			ctx:              auth.WithDisabled(context.Background()),
+			wantOwnOrder:     true,
+			wantOtherOrders:  true,
+			wantDeleted:      true,
+			wantStatusChange: true,
+		},
+		{
+			name:         "users access their own orders",
+			ctx:          asUser("user-1"),
+			wantFilter:   "user-1",
+			wantOwnOrder: true,
+		},
+		{
+			name:       "users do not access the orders of others",
+			ctx:        asUser("user-2", "customer"),
+			wantFilter: "user-2",
+		},
+		{
+			name:             "admins access every order",
+			ctx:              asUser("admin-1", auth.RoleAdmin),
+			wantOwnOrder:     true,
+			wantOtherOrders:  true,
+			wantDeleted:      true,
+			wantStatusChange: true,
+		},
+		{
+			name:             "support accesses every order",
+			ctx:              asUser("support-1", auth.RoleSupport),
+			wantOwnOrder:     true,
+			wantOtherOrders:  true,
+			wantStatusChange: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := policyFromContext(tc.ctx)
+			require.NoError(t, err)
+
+			filter, err := p.userFilter("")
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantFilter, filter, "An empty user filter should default to the caller")
+
+			err = p.checkOrder(order)
+			if tc.wantOwnOrder || tc.wantOtherOrders {
+				require.NoError(t, err)
+			} else {
+				assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+				assert.EqualError(t, err, "not_found: order not found", "The error should be the one of a missing order")
+			}
+
+			err = p.checkUser("user-1")
+			if tc.wantOwnOrder {
+				require.NoError(t, err)
+			} else {
+				assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+			}
+
+			filter, err = p.userFilter("user-3")
+			if tc.wantOtherOrders {
+				require.NoError(t, err)
+				assert.Equal(t, "user-3", filter)
+			} else {
+				assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+			}
+
+			err = p.checkDeleted()
+			if tc.wantDeleted {
+				require.NoError(t, err)
+			} else {
+				assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+			}
+
+			err = p.checkStatusChange()
+			if tc.wantStatusChange {
+				require.NoError(t, err)
+			} else {
+				assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+			}
+		})
+	}
+}
+
+func TestPolicy_WithoutPrincipal(t *testing.T) {
+	_, err := policyFromContext(context.Background())
+
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err),
+		"Callers without a principal should be rejected unless authentication is disabled")
+}
@@ -9,21 +9,37 @@ import (
 )
 
 type Server struct {
-	createOrderHandler     *createOrderHandler
-	getOrderHandler        *getOrderHandler
-	listOrdersHandler      *listOrdersHandler
-	checkOrderOwnerHandler *checkOrderOwnerHandler
+	createOrderHandler       *createOrderHandler
+	getOrderHandler          *getOrderHandler
+	listOrdersHandler        *listOrdersHandler
+	checkOrderOwnerHandler   *checkOrderOwnerHandler
+	updateOrderStatusHandler *updateOrderStatusHandler
+	cancelOrderHandler       *cancelOrderHandler
+	watchOrdersHandler       *watchOrdersHandler
+	deleteOrderHandler       *deleteOrderHandler
+	restoreOrderHandler      *restoreOrderHandler
 }
 
 func NewServer(store store.OrderStore) *Server {
 	return &Server{
-		createOrderHandler:     newCreateOrderHandler(store),
-		getOrderHandler:        newGetOrderHandler(store),
-		listOrdersHandler:      newListOrdersHandler(store),
-		checkOrderOwnerHandler: newCheckOrderOwnerHandler(store),
+		createOrderHandler:       newCreateOrderHandler(store),
+		getOrderHandler:          newGetOrderHandler(store),
+		listOrdersHandler:        newListOrdersHandler(store),
+		checkOrderOwnerHandler:   newCheckOrderOwnerHandler(store),
+		updateOrderStatusHandler: newUpdateOrderStatusHandler(store),
+		cancelOrderHandler:       newCancelOrderHandler(store),
+		watchOrdersHandler:       newWatchOrdersHandler(store),
+		deleteOrderHandler:       newDeleteOrderHandler(store),
+		restoreOrderHandler:      newRestoreOrderHandler(store),
 	}
 }
 
+// Shutdown ends the open WatchOrders streams with CodeUnavailable, so that
+// clients reconnect to another instance. It does not wait for them to end.
+func (s *Server) Shutdown() {
+	s.watchOrdersHandler.stop()
+}
+
 func (s *Server) CreateOrder(
 	ctx context.Context,
 	req *connect.Request[orderv1.CreateOrderRequest],
@@ -51,3 +67,39 @@ func (s *Server) CheckOrderOwner(
 ) (*connect.Response[orderv1.CheckOrderOwnerResponse], error) {
 	return s.checkOrderOwnerHandler.Handle(ctx, req)
 }
+
+func (s *Server) UpdateOrderStatus(
+	ctx context.Context,
+	req *connect.Request[orderv1.UpdateOrderStatusRequest],
+) (*connect.Response[orderv1.UpdateOrderStatusResponse], error) {
+	return s.updateOrderStatusHandler.Handle(ctx, req)
+}
+
+func (s *Server) CancelOrder(
+	ctx context.Context,
+	req *connect.Request[orderv1.CancelOrderRequest],
+) (*connect.Response[orderv1.CancelOrderResponse], error) {
+	return s.cancelOrderHandler.Handle(ctx, req)
+}
+
+func (s *Server) WatchOrders(
+	ctx context.Context,
+	req *connect.Request[orderv1.WatchOrdersRequest],
+	stream *connect.ServerStream[orderv1.WatchOrdersResponse],
+) error {
+	return s.watchOrdersHandler.Handle(ctx, req, stream)
+}
+
+func (s *Server) DeleteOrder(
+	ctx context.Context,
+	req *connect.Request[orderv1.DeleteOrderRequest],
+) (*connect.Response[orderv1.DeleteOrderResponse], error) {
+	return s.deleteOrderHandler.Handle(ctx, req)
+}
+
+func (s *Server) RestoreOrder(
+	ctx context.Context,
+	req *connect.Request[orderv1.RestoreOrderRequest],
+) (*connect.Response[orderv1.RestoreOrderResponse], error) {
+	return s.restoreOrderHandler.Handle(ctx, req)
+}
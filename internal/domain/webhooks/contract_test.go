@@ -0,0 +1,35 @@
+package webhooks
+
+import (
+	"testing"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// TestContractValues checks that the values the contract rules accept are the ones the domain knows.
+func TestContractValues(t *testing.T) {
+	assert.ElementsMatch(t, []string{
+		string(entity.OrderEventCreated),
+		string(entity.OrderEventStatusChanged),
+		string(entity.OrderEventCancelled),
+		string(entity.OrderEventDeleted),
+		string(entity.OrderEventRestored),
+	}, contractRules(&orderv1.CreateWebhookSubscriptionRequest{}, "event_types").GetRepeated().GetItems().GetString().GetIn())
+
+	assert.ElementsMatch(t, []string{
+		string(entity.WebhookDeliveryPending),
+		string(entity.WebhookDeliveryDelivered),
+		string(entity.WebhookDeliveryDead),
+	}, contractRules(&orderv1.ListWebhookDeliveriesRequest{}, "status").GetString().GetIn())
+}
+
+// contractRules returns the rules of the field of msg.
+func contractRules(msg proto.Message, name protoreflect.Name) *validate.FieldRules {
+	field := msg.ProtoReflect().Descriptor().Fields().ByName(name)
+	return proto.GetExtension(field.Options(), validate.E_Field).(*validate.FieldRules)
+}
@@ -0,0 +1,44 @@
+package webhooks
+
+import (
+	"strconv"
+	"time"
+
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/entity"
+)
+
+// subscriptionToProto leaves out the secret, it is only returned when the subscription is created.
+func subscriptionToProto(e *entity.WebhookSubscription) *orderv1.WebhookSubscription {
+	s := &orderv1.WebhookSubscription{
+		Id:         e.ID,
+		Url:        e.URL,
+		EventTypes: make([]string, len(e.EventTypes)),
+		CreatedAt:  e.CreatedAt.Format(time.RFC3339),
+	}
+	for i, eventType := range e.EventTypes {
+		s.EventTypes[i] = string(eventType)
+	}
+	return s
+}
+
+func deliveryToProto(e *entity.WebhookDelivery) *orderv1.WebhookDelivery {
+	d := &orderv1.WebhookDelivery{
+		Id:             strconv.FormatInt(e.ID, 10),
+		SubscriptionId: e.SubscriptionID,
+		EventId:        strconv.FormatInt(e.EventID, 10),
+		EventType:      string(e.EventType),
+		OrderId:        e.OrderID,
+		Status:         string(e.Status),
+		Attempts:       int32(e.Attempts),
+		LastError:      e.LastError,
+		CreatedAt:      e.CreatedAt.Format(time.RFC3339),
+	}
+	if e.Status == entity.WebhookDeliveryPending {
+		d.NextAttemptAt = e.NextAttemptAt.Format(time.RFC3339)
+	}
+	if e.DeliveredAt != nil {
+		d.DeliveredAt = e.DeliveredAt.Format(time.RFC3339)
+	}
+	return d
+}
@@ -0,0 +1,106 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"slices"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/logging"
+	"github.com/demo/order/internal/store"
+	"github.com/demo/order/internal/tracing"
+	"github.com/demo/order/internal/webhook"
+	"github.com/google/uuid"
+)
+
+// generatedSecretBytes is the entropy of secrets generated for subscriptions created without one.
+const generatedSecretBytes = 32
+
+type createWebhookSubscriptionHandler struct {
+	store     store.OrderStore
+	endpoints webhook.EndpointPolicy
+}
+
+func newCreateWebhookSubscriptionHandler(store store.OrderStore, endpoints webhook.EndpointPolicy) *createWebhookSubscriptionHandler {
+	return &createWebhookSubscriptionHandler{store: store, endpoints: endpoints}
+}
+
+func (h *createWebhookSubscriptionHandler) Handle(
+	ctx context.Context,
+	req *connect.Request[orderv1.CreateWebhookSubscriptionRequest],
+) (*connect.Response[orderv1.CreateWebhookSubscriptionResponse], error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := tracing.Run(ctx, "CreateWebhookSubscription.validate", func() error { return h.validate(ctx, req.Msg) }); err != nil {
+		return nil, err
+	}
+
+	secret := req.Msg.Secret
+	if secret == "" {
+		generated, err := generateSecret()
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to generate webhook secret", "error", err)
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		secret = generated
+	}
+
+	subscription := &entity.WebhookSubscription{
+		ID:         uuid.New().String(),
+		URL:        req.Msg.Url,
+		Secret:     secret,
+		EventTypes: eventTypes(req.Msg.EventTypes),
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := h.store.CreateWebhookSubscription(ctx, subscription); err != nil {
+		logging.FromContext(ctx).Error("Failed to create webhook subscription", "subscription_id", subscription.ID, "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	protoSubscription := subscriptionToProto(subscription)
+	protoSubscription.Secret = subscription.Secret
+	return connect.NewResponse(&orderv1.CreateWebhookSubscriptionResponse{
+		Subscription: protoSubscription,
+	}), nil
+}
+
+func (h *createWebhookSubscriptionHandler) validate(ctx context.Context, req *orderv1.CreateWebhookSubscriptionRequest) error {
+	u, err := url.Parse(req.Url)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return connect.NewError(connect.CodeInvalidArgument, errors.New("url must be an absolute http or https URL"))
+	}
+	if u.User != nil {
+		return connect.NewError(connect.CodeInvalidArgument, errors.New("url must not contain credentials"))
+	}
+	if err := h.endpoints.CheckURL(ctx, u); err != nil {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return nil
+}
+
+// eventTypes drops repeated event types of an already validated request.
+func eventTypes(names []string) []entity.OrderEventType {
+	var types []entity.OrderEventType
+	for _, name := range names {
+		if eventType := entity.OrderEventType(name); !slices.Contains(types, eventType) {
+			types = append(types, eventType)
+		}
+	}
+	return types
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, generatedSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,38 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/logging"
+	"github.com/demo/order/internal/store"
+)
+
+type deleteWebhookSubscriptionHandler struct {
+	store store.OrderStore
+}
+
+func newDeleteWebhookSubscriptionHandler(store store.OrderStore) *deleteWebhookSubscriptionHandler {
+	return &deleteWebhookSubscriptionHandler{store: store}
+}
+
+func (h *deleteWebhookSubscriptionHandler) Handle(
+	ctx context.Context,
+	req *connect.Request[orderv1.DeleteWebhookSubscriptionRequest],
+) (*connect.Response[orderv1.DeleteWebhookSubscriptionResponse], error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := h.store.DeleteWebhookSubscription(ctx, req.Msg.Id); err != nil {
+		if errors.Is(err, store.ErrWebhookSubscriptionNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+		logging.FromContext(ctx).Error("Failed to delete webhook subscription", "subscription_id", req.Msg.Id, "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&orderv1.DeleteWebhookSubscriptionResponse{}), nil
+}
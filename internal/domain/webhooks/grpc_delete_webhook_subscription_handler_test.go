@@ -0,0 +1,122 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/auth"
+	"github.com/demo/order/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteWebhookSubscriptionHandler(t *testing.T) {
+	// testData holds all data needed for each test case
+	type testData struct {
+		ctx       context.Context
+		t         *testing.T
+		handler   *deleteWebhookSubscriptionHandler
+		mockStore *store.MockOrderStore
+		request   *connect.Request[orderv1.DeleteWebhookSubscriptionRequest]
+		response  *connect.Response[orderv1.DeleteWebhookSubscriptionResponse]
+		err       error
+
+		// Track deleted IDs for verification
+		deleted []string
+	}
+
+	// testCase defines GWT structure for each test scenario
+	type testCase struct {
+		name  string
+		given func(*testData)
+		when  func(*testData)
+		then  func(*testData)
+	}
+
+	// setupTestData creates isolated test data for each test case
+	setupTestData := func(t *testing.T) *testData {
+		td := &testData{
+			ctx:       asUser("admin-1", auth.RoleAdmin),
+			t:         t,
+			mockStore: &store.MockOrderStore{},
+			request:   connect.NewRequest(&orderv1.DeleteWebhookSubscriptionRequest{Id: "subscription-1"}),
+		}
+		td.mockStore.DeleteWebhookSubscriptionFunc = func(_ context.Context, id string) error {
+			td.deleted = append(td.deleted, id)
+			return nil
+		}
+		td.handler = newDeleteWebhookSubscriptionHandler(td.mockStore)
+		return td
+	}
+
+	handle := func(td *testData) {
+		td.response, td.err = td.handler.Handle(td.ctx, td.request)
+	}
+
+	testCases := []testCase{
+		{
+			name:  "Should delete subscription",
+			given: func(_ *testData) {},
+			when:  handle,
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.NotNil(td.t, td.response)
+				assert.Equal(td.t, []string{"subscription-1"}, td.deleted)
+			},
+		},
+		{
+			name: "Should return NotFound when subscription does not exist",
+			given: func(td *testData) {
+				td.mockStore.DeleteWebhookSubscriptionFunc = func(context.Context, string) error {
+					return store.ErrWebhookSubscriptionNotFound
+				}
+			},
+			when: handle,
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeNotFound, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+			},
+		},
+		{
+			name: "Should return Internal when store fails",
+			given: func(td *testData) {
+				td.mockStore.DeleteWebhookSubscriptionFunc = func(context.Context, string) error {
+					return errors.New("database connection failed")
+				}
+			},
+			when: handle,
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInternal, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+			},
+		},
+		{
+			name: "Should return PermissionDenied when caller is not an admin",
+			given: func(td *testData) {
+				td.ctx = asUser("support-1", auth.RoleSupport)
+			},
+			when: handle,
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodePermissionDenied, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+				assert.Empty(td.t, td.deleted, "Store should not be called")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			td := setupTestData(t)
+			td.t = t
+			tc.given(td)
+			tc.when(td)
+			tc.then(td)
+		})
+	}
+}
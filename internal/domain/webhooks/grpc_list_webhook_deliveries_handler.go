@@ -0,0 +1,72 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/logging"
+	"github.com/demo/order/internal/store"
+)
+
+const (
+	defaultListWebhookDeliveriesPageSize = 50
+	maxListWebhookDeliveriesPageSize     = 500
+)
+
+type listWebhookDeliveriesHandler struct {
+	store store.OrderStore
+}
+
+func newListWebhookDeliveriesHandler(store store.OrderStore) *listWebhookDeliveriesHandler {
+	return &listWebhookDeliveriesHandler{store: store}
+}
+
+func (h *listWebhookDeliveriesHandler) Handle(
+	ctx context.Context,
+	req *connect.Request[orderv1.ListWebhookDeliveriesRequest],
+) (*connect.Response[orderv1.ListWebhookDeliveriesResponse], error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	page, err := h.store.ListWebhookDeliveries(ctx, h.query(req.Msg))
+	if err != nil {
+		if errors.Is(err, store.ErrInvalidPageToken) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		logging.FromContext(ctx).Error("Failed to list webhook deliveries", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	protoDeliveries := make([]*orderv1.WebhookDelivery, len(page.Deliveries))
+	for i, d := range page.Deliveries {
+		protoDeliveries[i] = deliveryToProto(d)
+	}
+
+	return connect.NewResponse(&orderv1.ListWebhookDeliveriesResponse{
+		Deliveries:    protoDeliveries,
+		NextPageToken: page.NextPageToken,
+	}), nil
+}
+
+// query builds the store query from a request that satisfies the contract rules.
+func (h *listWebhookDeliveriesHandler) query(req *orderv1.ListWebhookDeliveriesRequest) store.WebhookDeliveryQuery {
+	pageSize := int(req.PageSize)
+	switch {
+	case pageSize == 0:
+		pageSize = defaultListWebhookDeliveriesPageSize
+	case pageSize > maxListWebhookDeliveriesPageSize:
+		pageSize = maxListWebhookDeliveriesPageSize
+	}
+
+	return store.WebhookDeliveryQuery{
+		PageSize:       pageSize,
+		PageToken:      req.PageToken,
+		SubscriptionID: req.SubscriptionId,
+		Status:         entity.WebhookDeliveryStatus(req.Status),
+		OrderID:        req.OrderId,
+	}
+}
@@ -0,0 +1,218 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/auth"
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListWebhookDeliveriesHandler(t *testing.T) {
+	// testData holds all data needed for each test case
+	type testData struct {
+		ctx       context.Context
+		t         *testing.T
+		handler   *listWebhookDeliveriesHandler
+		mockStore *store.MockOrderStore
+		request   *connect.Request[orderv1.ListWebhookDeliveriesRequest]
+		response  *connect.Response[orderv1.ListWebhookDeliveriesResponse]
+		err       error
+
+		// Track store queries for verification
+		queries []store.WebhookDeliveryQuery
+	}
+
+	// testCase defines GWT structure for each test scenario
+	type testCase struct {
+		name  string
+		given func(*testData)
+		when  func(*testData)
+		then  func(*testData)
+	}
+
+	createdAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	deliveredAt := createdAt.Add(time.Second)
+
+	// setupTestData creates isolated test data for each test case
+	setupTestData := func(t *testing.T) *testData {
+		td := &testData{
+			ctx:       asUser("admin-1", auth.RoleAdmin),
+			t:         t,
+			mockStore: &store.MockOrderStore{},
+			request:   connect.NewRequest(&orderv1.ListWebhookDeliveriesRequest{}),
+		}
+		td.mockStore.ListWebhookDeliveriesFunc = func(
+			_ context.Context,
+			query store.WebhookDeliveryQuery,
+		) (*store.WebhookDeliveryPage, error) {
+			td.queries = append(td.queries, query)
+			return &store.WebhookDeliveryPage{
+				Deliveries: []*entity.WebhookDelivery{
+					{
+						ID:             2,
+						SubscriptionID: "subscription-1",
+						EventID:        7,
+						EventType:      entity.OrderEventCancelled,
+						OrderID:        "order-123",
+						Status:         entity.WebhookDeliveryDead,
+						Attempts:       15,
+						LastError:      "endpoint responded with status 500",
+						NextAttemptAt:  createdAt,
+						CreatedAt:      createdAt,
+					},
+					{
+						ID:             1,
+						SubscriptionID: "subscription-1",
+						EventID:        6,
+						EventType:      entity.OrderEventCreated,
+						OrderID:        "order-123",
+						Status:         entity.WebhookDeliveryDelivered,
+						CreatedAt:      createdAt,
+						DeliveredAt:    &deliveredAt,
+					},
+				},
+				NextPageToken: "next-page",
+			}, nil
+		}
+		td.handler = newListWebhookDeliveriesHandler(td.mockStore)
+		return td
+	}
+
+	handle := func(td *testData) {
+		td.response, td.err = td.handler.Handle(td.ctx, td.request)
+	}
+
+	expectInvalidArgument := func(td *testData) {
+		require.Error(td.t, td.err)
+		assert.Equal(td.t, connect.CodeInvalidArgument, connect.CodeOf(td.err))
+		assert.Nil(td.t, td.response)
+	}
+
+	testCases := []testCase{
+		{
+			name:  "Should list deliveries with default page size",
+			given: func(_ *testData) {},
+			when:  handle,
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.Len(td.t, td.response.Msg.Deliveries, 2)
+				assert.Equal(td.t, "next-page", td.response.Msg.NextPageToken)
+
+				dead := td.response.Msg.Deliveries[0]
+				assert.Equal(td.t, "2", dead.Id)
+				assert.Equal(td.t, "subscription-1", dead.SubscriptionId)
+				assert.Equal(td.t, "7", dead.EventId)
+				assert.Equal(td.t, "ORDER_CANCELLED", dead.EventType)
+				assert.Equal(td.t, "order-123", dead.OrderId)
+				assert.Equal(td.t, "DEAD", dead.Status)
+				assert.Equal(td.t, int32(15), dead.Attempts)
+				assert.Equal(td.t, "endpoint responded with status 500", dead.LastError)
+				assert.Empty(td.t, dead.NextAttemptAt, "Dead delivery has no next attempt")
+
+				delivered := td.response.Msg.Deliveries[1]
+				assert.Equal(td.t, "DELIVERED", delivered.Status)
+				assert.Equal(td.t, "2024-01-15T10:30:01Z", delivered.DeliveredAt)
+
+				require.Len(td.t, td.queries, 1)
+				assert.Equal(td.t, store.WebhookDeliveryQuery{PageSize: defaultListWebhookDeliveriesPageSize}, td.queries[0])
+			},
+		},
+		{
+			name: "Should pass filters and page token to store",
+			given: func(td *testData) {
+				td.request.Msg = &orderv1.ListWebhookDeliveriesRequest{
+					PageSize:       10,
+					PageToken:      "token",
+					SubscriptionId: "subscription-1",
+					Status:         "DEAD",
+					OrderId:        "order-123",
+				}
+			},
+			when: handle,
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.Len(td.t, td.queries, 1)
+				assert.Equal(td.t, store.WebhookDeliveryQuery{
+					PageSize:       10,
+					PageToken:      "token",
+					SubscriptionID: "subscription-1",
+					Status:         entity.WebhookDeliveryDead,
+					OrderID:        "order-123",
+				}, td.queries[0])
+			},
+		},
+		{
+			name: "Should cap page size",
+			given: func(td *testData) {
+				td.request.Msg.PageSize = maxListWebhookDeliveriesPageSize + 1
+			},
+			when: handle,
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.Len(td.t, td.queries, 1)
+				assert.Equal(td.t, maxListWebhookDeliveriesPageSize, td.queries[0].PageSize)
+			},
+		},
+		{
+			name: "Should return InvalidArgument when page token is invalid",
+			given: func(td *testData) {
+				td.mockStore.ListWebhookDeliveriesFunc = func(
+					context.Context,
+					store.WebhookDeliveryQuery,
+				) (*store.WebhookDeliveryPage, error) {
+					return nil, store.ErrInvalidPageToken
+				}
+			},
+			when: handle,
+			then: expectInvalidArgument,
+		},
+		{
+			name: "Should return Internal when store fails",
+			given: func(td *testData) {
+				td.mockStore.ListWebhookDeliveriesFunc = func(
+					context.Context,
+					store.WebhookDeliveryQuery,
+				) (*store.WebhookDeliveryPage, error) {
+					return nil, errors.New("database connection failed")
+				}
+			},
+			when: handle,
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInternal, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+			},
+		},
+		{
+			name: "Should return PermissionDenied when caller is not an admin",
+			given: func(td *testData) {
+				td.ctx = asUser("support-1", auth.RoleSupport)
+			},
+			when: handle,
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodePermissionDenied, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+				assert.Empty(td.t, td.queries, "Store should not be called")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			td := setupTestData(t)
+			td.t = t
+			tc.given(td)
+			tc.when(td)
+			tc.then(td)
+		})
+	}
+}
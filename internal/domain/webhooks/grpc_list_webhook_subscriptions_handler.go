@@ -0,0 +1,42 @@
+package webhooks
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/logging"
+	"github.com/demo/order/internal/store"
+)
+
+type listWebhookSubscriptionsHandler struct {
+	store store.OrderStore
+}
+
+func newListWebhookSubscriptionsHandler(store store.OrderStore) *listWebhookSubscriptionsHandler {
+	return &listWebhookSubscriptionsHandler{store: store}
+}
+
+func (h *listWebhookSubscriptionsHandler) Handle(
+	ctx context.Context,
+	_ *connect.Request[orderv1.ListWebhookSubscriptionsRequest],
+) (*connect.Response[orderv1.ListWebhookSubscriptionsResponse], error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := h.store.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list webhook subscriptions", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	protoSubscriptions := make([]*orderv1.WebhookSubscription, len(subscriptions))
+	for i, s := range subscriptions {
+		protoSubscriptions[i] = subscriptionToProto(s)
+	}
+
+	return connect.NewResponse(&orderv1.ListWebhookSubscriptionsResponse{
+		Subscriptions: protoSubscriptions,
+	}), nil
+}
@@ -0,0 +1,140 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/auth"
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListWebhookSubscriptionsHandler(t *testing.T) {
+	// testData holds all data needed for each test case
+	type testData struct {
+		ctx       context.Context
+		t         *testing.T
+		handler   *listWebhookSubscriptionsHandler
+		mockStore *store.MockOrderStore
+		request   *connect.Request[orderv1.ListWebhookSubscriptionsRequest]
+		response  *connect.Response[orderv1.ListWebhookSubscriptionsResponse]
+		err       error
+	}
+
+	// testCase defines GWT structure for each test scenario
+	type testCase struct {
+		name  string
+		given func(*testData)
+		when  func(*testData)
+		then  func(*testData)
+	}
+
+	// setupTestData creates isolated test data for each test case
+	setupTestData := func(t *testing.T) *testData {
+		mockStore := &store.MockOrderStore{}
+		return &testData{
+			ctx:       asUser("admin-1", auth.RoleAdmin),
+			t:         t,
+			handler:   newListWebhookSubscriptionsHandler(mockStore),
+			mockStore: mockStore,
+			request:   connect.NewRequest(&orderv1.ListWebhookSubscriptionsRequest{}),
+		}
+	}
+
+	testCases := []testCase{
+		{
+			name: "Should list subscriptions without their secrets",
+			given: func(td *testData) {
+				td.mockStore.ListWebhookSubscriptionsFunc = func(context.Context) ([]*entity.WebhookSubscription, error) {
+					return []*entity.WebhookSubscription{
+						{
+							ID:         "subscription-1",
+							URL:        "https://partner.example.com/hooks",
+							Secret:     "partner-secret-0123456789",
+							EventTypes: []entity.OrderEventType{entity.OrderEventCancelled},
+							CreatedAt:  time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+						},
+						{ID: "subscription-2", URL: "https://other.example.com/hooks", Secret: "other-secret-0123456789"},
+					}, nil
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.Len(td.t, td.response.Msg.Subscriptions, 2)
+
+				subscription := td.response.Msg.Subscriptions[0]
+				assert.Equal(td.t, "subscription-1", subscription.Id)
+				assert.Equal(td.t, "https://partner.example.com/hooks", subscription.Url)
+				assert.Equal(td.t, []string{"ORDER_CANCELLED"}, subscription.EventTypes)
+				assert.Equal(td.t, "2024-01-15T10:30:00Z", subscription.CreatedAt)
+				for _, s := range td.response.Msg.Subscriptions {
+					assert.Empty(td.t, s.Secret, "Secret should not be listed")
+				}
+			},
+		},
+		{
+			name:  "Should return empty list when there are no subscriptions",
+			given: func(_ *testData) {},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				assert.Empty(td.t, td.response.Msg.Subscriptions)
+			},
+		},
+		{
+			name: "Should return Internal when store fails",
+			given: func(td *testData) {
+				td.mockStore.ListWebhookSubscriptionsFunc = func(context.Context) ([]*entity.WebhookSubscription, error) {
+					return nil, errors.New("database connection failed")
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodeInternal, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+			},
+		},
+		{
+			name: "Should return PermissionDenied when caller is not an admin",
+			given: func(td *testData) {
+				td.ctx = asUser("support-1", auth.RoleSupport)
+				td.mockStore.ListWebhookSubscriptionsFunc = func(context.Context) ([]*entity.WebhookSubscription, error) {
+					td.t.Error("Store should not be called")
+					return nil, nil
+				}
+			},
+			when: func(td *testData) {
+				td.response, td.err = td.handler.Handle(td.ctx, td.request)
+			},
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodePermissionDenied, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			td := setupTestData(t)
+			td.t = t
+			tc.given(td)
+			tc.when(td)
+			tc.then(td)
+		})
+	}
+}
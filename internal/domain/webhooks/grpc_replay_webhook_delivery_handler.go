@@ -0,0 +1,66 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/logging"
+	"github.com/demo/order/internal/store"
+	"github.com/demo/order/internal/tracing"
+)
+
+type replayWebhookDeliveryHandler struct {
+	store store.OrderStore
+}
+
+func newReplayWebhookDeliveryHandler(store store.OrderStore) *replayWebhookDeliveryHandler {
+	return &replayWebhookDeliveryHandler{store: store}
+}
+
+// Handle puts a dead delivery back in the queue with a fresh set of attempts.
+func (h *replayWebhookDeliveryHandler) Handle(
+	ctx context.Context,
+	req *connect.Request[orderv1.ReplayWebhookDeliveryRequest],
+) (*connect.Response[orderv1.ReplayWebhookDeliveryResponse], error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	var id int64
+	err := tracing.Run(ctx, "ReplayWebhookDelivery.validate", func() (err error) {
+		id, err = h.validate(req.Msg)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := h.store.ReplayWebhookDelivery(ctx, id, time.Now().UTC())
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrWebhookDeliveryNotFound):
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		case errors.Is(err, store.ErrWebhookDeliveryNotDead):
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+		}
+		logging.FromContext(ctx).Error("Failed to replay webhook delivery", "delivery_id", id, "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&orderv1.ReplayWebhookDeliveryResponse{
+		Delivery: deliveryToProto(delivery),
+	}), nil
+}
+
+// validate returns the delivery ID of a valid request.
+func (h *replayWebhookDeliveryHandler) validate(req *orderv1.ReplayWebhookDeliveryRequest) (int64, error) {
+	id, err := strconv.ParseInt(req.Id, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, connect.NewError(connect.CodeInvalidArgument, errors.New("id must be a delivery ID"))
+	}
+	return id, nil
+}
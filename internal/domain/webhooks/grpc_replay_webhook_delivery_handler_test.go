@@ -0,0 +1,169 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/auth"
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayWebhookDeliveryHandler(t *testing.T) {
+	// testData holds all data needed for each test case
+	type testData struct {
+		ctx       context.Context
+		t         *testing.T
+		handler   *replayWebhookDeliveryHandler
+		mockStore *store.MockOrderStore
+		request   *connect.Request[orderv1.ReplayWebhookDeliveryRequest]
+		response  *connect.Response[orderv1.ReplayWebhookDeliveryResponse]
+		err       error
+
+		// Track replayed IDs for verification
+		replayed []int64
+	}
+
+	// testCase defines GWT structure for each test scenario
+	type testCase struct {
+		name  string
+		given func(*testData)
+		when  func(*testData)
+		then  func(*testData)
+	}
+
+	// setupTestData creates isolated test data for each test case
+	setupTestData := func(t *testing.T) *testData {
+		td := &testData{
+			ctx:       asUser("admin-1", auth.RoleAdmin),
+			t:         t,
+			mockStore: &store.MockOrderStore{},
+			request:   connect.NewRequest(&orderv1.ReplayWebhookDeliveryRequest{Id: "42"}),
+		}
+		td.mockStore.ReplayWebhookDeliveryFunc = func(
+			_ context.Context,
+			id int64,
+			now time.Time,
+		) (*entity.WebhookDelivery, error) {
+			td.replayed = append(td.replayed, id)
+			return &entity.WebhookDelivery{
+				ID:             id,
+				SubscriptionID: "subscription-1",
+				EventID:        7,
+				EventType:      entity.OrderEventCreated,
+				OrderID:        "order-123",
+				Status:         entity.WebhookDeliveryPending,
+				NextAttemptAt:  now,
+				CreatedAt:      now.Add(-time.Hour),
+			}, nil
+		}
+		td.handler = newReplayWebhookDeliveryHandler(td.mockStore)
+		return td
+	}
+
+	handle := func(td *testData) {
+		td.response, td.err = td.handler.Handle(td.ctx, td.request)
+	}
+
+	expectCode := func(code connect.Code) func(*testData) {
+		return func(td *testData) {
+			require.Error(td.t, td.err)
+			assert.Equal(td.t, code, connect.CodeOf(td.err))
+			assert.Nil(td.t, td.response)
+		}
+	}
+
+	testCases := []testCase{
+		{
+			name:  "Should replay dead delivery",
+			given: func(_ *testData) {},
+			when:  handle,
+			then: func(td *testData) {
+				require.NoError(td.t, td.err)
+				require.NotNil(td.t, td.response.Msg.Delivery)
+
+				delivery := td.response.Msg.Delivery
+				assert.Equal(td.t, "42", delivery.Id)
+				assert.Equal(td.t, "PENDING", delivery.Status)
+				assert.Equal(td.t, int32(0), delivery.Attempts)
+				assert.NotEmpty(td.t, delivery.NextAttemptAt)
+				assert.Equal(td.t, []int64{42}, td.replayed)
+			},
+		},
+		{
+			name: "Should return InvalidArgument when id is empty",
+			given: func(td *testData) {
+				td.request.Msg.Id = ""
+			},
+			when: handle,
+			then: expectCode(connect.CodeInvalidArgument),
+		},
+		{
+			name: "Should return InvalidArgument when id is not a number",
+			given: func(td *testData) {
+				td.request.Msg.Id = "delivery-42"
+			},
+			when: handle,
+			then: expectCode(connect.CodeInvalidArgument),
+		},
+		{
+			name: "Should return NotFound when delivery does not exist",
+			given: func(td *testData) {
+				td.mockStore.ReplayWebhookDeliveryFunc = func(context.Context, int64, time.Time) (*entity.WebhookDelivery, error) {
+					return nil, store.ErrWebhookDeliveryNotFound
+				}
+			},
+			when: handle,
+			then: expectCode(connect.CodeNotFound),
+		},
+		{
+			name: "Should return FailedPrecondition when delivery is not dead",
+			given: func(td *testData) {
+				td.mockStore.ReplayWebhookDeliveryFunc = func(context.Context, int64, time.Time) (*entity.WebhookDelivery, error) {
+					return nil, store.ErrWebhookDeliveryNotDead
+				}
+			},
+			when: handle,
+			then: expectCode(connect.CodeFailedPrecondition),
+		},
+		{
+			name: "Should return Internal when store fails",
+			given: func(td *testData) {
+				td.mockStore.ReplayWebhookDeliveryFunc = func(context.Context, int64, time.Time) (*entity.WebhookDelivery, error) {
+					return nil, errors.New("database connection failed")
+				}
+			},
+			when: handle,
+			then: expectCode(connect.CodeInternal),
+		},
+		{
+			name: "Should return PermissionDenied when caller is not an admin",
+			given: func(td *testData) {
+				td.ctx = asUser("support-1", auth.RoleSupport)
+			},
+			when: handle,
+			then: func(td *testData) {
+				require.Error(td.t, td.err)
+				assert.Equal(td.t, connect.CodePermissionDenied, connect.CodeOf(td.err))
+				assert.Nil(td.t, td.response)
+				assert.Empty(td.t, td.replayed, "Store should not be called")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			td := setupTestData(t)
+			td.t = t
+			tc.given(td)
+			tc.when(td)
+			tc.then(td)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	"github.com/demo/order/internal/auth"
+)
+
+var errNotAdmin = errors.New("only admins may manage webhooks")
+
+// requireAdmin denies every WebhookService RPC to callers without auth.RoleAdmin:
+// subscriptions carry signing secrets and receive the orders of every user.
+func requireAdmin(ctx context.Context) error {
+	if !auth.CallerHasRole(ctx, auth.RoleAdmin) {
+		return connect.NewError(connect.CodePermissionDenied, errNotAdmin)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/demo/order/internal/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+// asUser returns a context authenticated as userID with roles.
+func asUser(userID string, roles ...string) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{Subject: userID, Roles: roles})
+}
+
+func TestRequireAdmin(t *testing.T) {
+	testCases := []struct {
+		name    string
+		ctx     context.Context
+		allowed bool
+	}{
+		{name: "callers without a principal are denied", ctx: context.Background()},
+		{name: "callers are denied when authentication is disabled", ctx: auth.WithDisabled(context.Background())},
+		{name: "admins are allowed", ctx: asUser("admin-1", auth.RoleAdmin), allowed: true},
+		{name: "support is denied", ctx: asUser("support-1", auth.RoleSupport)},
+		{name: "users are denied", ctx: asUser("user-1")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := requireAdmin(tc.ctx)
+			if tc.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+			}
+		})
+	}
+}
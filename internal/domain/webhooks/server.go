@@ -0,0 +1,65 @@
+package webhooks
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/store"
+	"github.com/demo/order/internal/webhook"
+)
+
+// Server implements the admin WebhookService.
+type Server struct {
+	createWebhookSubscriptionHandler *createWebhookSubscriptionHandler
+	listWebhookSubscriptionsHandler  *listWebhookSubscriptionsHandler
+	deleteWebhookSubscriptionHandler *deleteWebhookSubscriptionHandler
+	listWebhookDeliveriesHandler     *listWebhookDeliveriesHandler
+	replayWebhookDeliveryHandler     *replayWebhookDeliveryHandler
+}
+
+// NewServer returns the service. Subscriptions may only point to endpoints allowed by endpoints.
+func NewServer(store store.OrderStore, endpoints webhook.EndpointPolicy) *Server {
+	return &Server{
+		createWebhookSubscriptionHandler: newCreateWebhookSubscriptionHandler(store, endpoints),
+		listWebhookSubscriptionsHandler:  newListWebhookSubscriptionsHandler(store),
+		deleteWebhookSubscriptionHandler: newDeleteWebhookSubscriptionHandler(store),
+		listWebhookDeliveriesHandler:     newListWebhookDeliveriesHandler(store),
+		replayWebhookDeliveryHandler:     newReplayWebhookDeliveryHandler(store),
+	}
+}
+
+func (s *Server) CreateWebhookSubscription(
+	ctx context.Context,
+	req *connect.Request[orderv1.CreateWebhookSubscriptionRequest],
+) (*connect.Response[orderv1.CreateWebhookSubscriptionResponse], error) {
+	return s.createWebhookSubscriptionHandler.Handle(ctx, req)
+}
+
+func (s *Server) ListWebhookSubscriptions(
+	ctx context.Context,
+	req *connect.Request[orderv1.ListWebhookSubscriptionsRequest],
+) (*connect.Response[orderv1.ListWebhookSubscriptionsResponse], error) {
+	return s.listWebhookSubscriptionsHandler.Handle(ctx, req)
+}
+
+func (s *Server) DeleteWebhookSubscription(
+	ctx context.Context,
+	req *connect.Request[orderv1.DeleteWebhookSubscriptionRequest],
+) (*connect.Response[orderv1.DeleteWebhookSubscriptionResponse], error) {
+	return s.deleteWebhookSubscriptionHandler.Handle(ctx, req)
+}
+
+func (s *Server) ListWebhookDeliveries(
+	ctx context.Context,
+	req *connect.Request[orderv1.ListWebhookDeliveriesRequest],
+) (*connect.Response[orderv1.ListWebhookDeliveriesResponse], error) {
+	return s.listWebhookDeliveriesHandler.Handle(ctx, req)
+}
+
+func (s *Server) ReplayWebhookDelivery(
+	ctx context.Context,
+	req *connect.Request[orderv1.ReplayWebhookDeliveryRequest],
+) (*connect.Response[orderv1.ReplayWebhookDeliveryResponse], error) {
+	return s.replayWebhookDeliveryHandler.Handle(ctx, req)
+}
@@ -0,0 +1,197 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultCurrency is the ISO-4217 code used when a request does not name a currency.
+	DefaultCurrency = "EUR"
+
+	// maxMajorUnitDigits is the number of integer digits that fit into the DECIMAL(12, 4) amount column.
+	maxMajorUnitDigits = 8
+)
+
+var (
+	ErrInvalidMoney    = errors.New("invalid money amount")
+	ErrMoneyScale      = errors.New("money amount has too many decimal places")
+	ErrMoneyOutOfRange = errors.New("money amount is out of range")
+)
+
+// Money is an exact amount in the minor units of its currency, e.g. cents for EUR.
+type Money struct {
+	MinorUnits int64
+	Currency   string `db:"currency"`
+}
+
+func NewMoney(minorUnits int64, currency string) Money {
+	return Money{MinorUnits: minorUnits, Currency: currency}
+}
+
+// ParseMoney parses a decimal string such as "12.34". It fails instead of
+// rounding if the amount has more decimal places than the currency allows.
+func ParseMoney(amount, currency string) (Money, error) {
+	scale, ok := CurrencyMinorUnits(currency)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+	}
+
+	digits, negative := strings.CutPrefix(amount, "-")
+	intPart, fracPart, _ := strings.Cut(digits, ".")
+	if intPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, amount)
+	}
+
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > scale {
+		return Money{}, fmt.Errorf("%w: %q, %s allows %d", ErrMoneyScale, amount, currency, scale)
+	}
+	fracPart += strings.Repeat("0", scale-len(fracPart))
+
+	intPart = strings.TrimLeft(intPart, "0")
+	if len(intPart) > maxMajorUnitDigits {
+		return Money{}, fmt.Errorf("%w: %q", ErrMoneyOutOfRange, amount)
+	}
+	minorUnits, err := strconv.ParseInt("0"+intPart+fracPart, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, amount)
+	}
+	if negative {
+		minorUnits = -minorUnits
+	}
+
+	return NewMoney(minorUnits, currency), nil
+}
+
+// MoneyFromFloat converts an amount received as a float. The shortest decimal
+// representation of f is used, so 0.1 is exactly 10 cents.
+func MoneyFromFloat(f float64, currency string) (Money, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Money{}, fmt.Errorf("%w: %v", ErrInvalidMoney, f)
+	}
+	if math.Abs(f) >= 1e15 {
+		return Money{}, fmt.Errorf("%w: %v", ErrMoneyOutOfRange, f)
+	}
+	return ParseMoney(strconv.FormatFloat(f, 'f', -1, 64), currency)
+}
+
+// MaxMoney returns the largest amount of the currency that fits into the amount column.
+func MaxMoney(currency string) Money {
+	scale, _ := CurrencyMinorUnits(currency)
+	return NewMoney(pow10(maxMajorUnitDigits+scale)-1, currency)
+}
+
+// Validate reports whether the currency is known and the amount fits into the amount column.
+func (m Money) Validate() error {
+	if !IsKnownCurrency(m.Currency) {
+		return fmt.Errorf("%w: %q", ErrUnknownCurrency, m.Currency)
+	}
+	limit := MaxMoney(m.Currency).MinorUnits
+	if m.MinorUnits > limit || m.MinorUnits < -limit {
+		return fmt.Errorf("%w: %s", ErrMoneyOutOfRange, m)
+	}
+	return nil
+}
+
+// Decimal formats the amount in major units, e.g. "12.34" for EUR or "1234" for JPY.
+func (m Money) Decimal() string {
+	units := m.MinorUnits
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+	scale, _ := CurrencyMinorUnits(m.Currency)
+	if scale == 0 {
+		return sign + strconv.FormatInt(units, 10)
+	}
+	divisor := pow10(scale)
+	return fmt.Sprintf("%s%d.%0*d", sign, units/divisor, scale, units%divisor)
+}
+
+// Float64 returns the float closest to the amount, for APIs that still use doubles.
+func (m Money) Float64() float64 {
+	f, _ := strconv.ParseFloat(m.Decimal(), 64)
+	return f
+}
+
+// Cmp compares the amounts of m and other in major units, ignoring their currencies,
+// the same way Postgres compares the amount column.
+func (m Money) Cmp(other Money) int {
+	a, b := m.scaled(), other.scaled()
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// scaled returns the amount in units of the smallest minor unit of any currency.
+func (m Money) scaled() int64 {
+	scale, _ := CurrencyMinorUnits(m.Currency)
+	return m.MinorUnits * pow10(maxCurrencyMinorUnits-scale)
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// Value stores the amount into a DECIMAL column. The currency is stored separately.
+func (m Money) Value() (driver.Value, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m.Decimal(), nil
+}
+
+// Scan reads the amount from a DECIMAL column. The currency must be scanned
+// first, amounts without one are read as DefaultCurrency.
+func (m *Money) Scan(src any) error {
+	var amount string
+	switch v := src.(type) {
+	case []byte:
+		amount = string(v)
+	case string:
+		amount = v
+	case int64:
+		amount = strconv.FormatInt(v, 10)
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+
+	currency := m.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	parsed, err := ParseMoney(amount, currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+func pow10(n int) int64 {
+	result := int64(1)
+	for i := 0; i < n; i++ {
+		result *= 10
+	}
+	return result
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	testCases := []struct {
+		name     string
+		amount   string
+		currency string
+		want     int64
+		wantErr  error
+	}{
+		{name: "Should parse whole amount", amount: "12", currency: "EUR", want: 1200},
+		{name: "Should parse cents", amount: "12.34", currency: "EUR", want: 1234},
+		{name: "Should pad single decimal", amount: "0.5", currency: "EUR", want: 50},
+		{name: "Should ignore trailing zeros", amount: "1.2300", currency: "EUR", want: 123},
+		{name: "Should parse negative amount", amount: "-0.01", currency: "EUR", want: -1},
+		{name: "Should parse largest amount", amount: "99999999.99", currency: "EUR", want: 9999999999},
+		{name: "Should parse currency without minor unit", amount: "1500", currency: "JPY", want: 1500},
+		{name: "Should parse three decimal currency", amount: "1.005", currency: "KWD", want: 1005},
+		{name: "Should reject sub-cent amount", amount: "0.001", currency: "EUR", wantErr: ErrMoneyScale},
+		{name: "Should reject fractional yen", amount: "1.5", currency: "JPY", wantErr: ErrMoneyScale},
+		{name: "Should reject amount over column range", amount: "100000000", currency: "EUR", wantErr: ErrMoneyOutOfRange},
+		{name: "Should reject huge amount", amount: "1000000000000000000000", currency: "EUR", wantErr: ErrMoneyOutOfRange},
+		{name: "Should reject empty amount", amount: "", currency: "EUR", wantErr: ErrInvalidMoney},
+		{name: "Should reject exponent", amount: "1e5", currency: "EUR", wantErr: ErrInvalidMoney},
+		{name: "Should reject missing integer part", amount: ".5", currency: "EUR", wantErr: ErrInvalidMoney},
+		{name: "Should reject unknown currency", amount: "1", currency: "XXX", wantErr: ErrUnknownCurrency},
+		{name: "Should reject lowercase currency", amount: "1", currency: "eur", wantErr: ErrUnknownCurrency},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseMoney(tc.amount, tc.currency)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, NewMoney(tc.want, tc.currency), got)
+		})
+	}
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	assert.Equal(t, "12.05", NewMoney(1205, "EUR").Decimal())
+	assert.Equal(t, "-0.01", NewMoney(-1, "EUR").Decimal())
+	assert.Equal(t, "1500", NewMoney(1500, "JPY").Decimal())
+	assert.Equal(t, "1.005", NewMoney(1005, "KWD").Decimal())
+}
+
+func TestMoneyCmp(t *testing.T) {
+	assert.Equal(t, 0, NewMoney(500, "EUR").Cmp(NewMoney(5, "JPY")), "Amounts compare in major units")
+	assert.Equal(t, -1, NewMoney(1005, "KWD").Cmp(NewMoney(101, "EUR")))
+	assert.Equal(t, 1, NewMoney(2, "EUR").Cmp(NewMoney(1, "EUR")))
+}
+
+func TestMoneyFromFloat(t *testing.T) {
+	a, b := 0.1, 0.2
+	_, err := MoneyFromFloat(a+b, DefaultCurrency)
+	require.ErrorIs(t, err, ErrMoneyScale, "Float noise must not be rounded away silently")
+
+	got, err := MoneyFromFloat(100.5, DefaultCurrency)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10050), got.MinorUnits)
+	assert.Equal(t, 100.5, got.Float64())
+
+	_, err = MoneyFromFloat(1e12, DefaultCurrency)
+	require.ErrorIs(t, err, ErrMoneyOutOfRange)
+}
+
+func TestMoneyValueScan(t *testing.T) {
+	m := NewMoney(-1205, DefaultCurrency)
+
+	value, err := m.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "-12.05", value)
+
+	var scanned Money
+	require.NoError(t, scanned.Scan([]byte("-12.05")))
+	assert.Equal(t, m, scanned)
+
+	scanned = Money{Currency: "JPY"}
+	require.NoError(t, scanned.Scan([]byte("1500.0000")))
+	assert.Equal(t, NewMoney(1500, "JPY"), scanned, "Scan should use an already scanned currency")
+
+	tooLarge := MaxMoney(DefaultCurrency)
+	tooLarge.MinorUnits++
+	_, err = tooLarge.Value()
+	require.ErrorIs(t, err, ErrMoneyOutOfRange)
+}
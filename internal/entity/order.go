@@ -8,13 +8,40 @@ const (
 	OrderStatusNew        OrderStatus = "NEW"
 	OrderStatusInProgress OrderStatus = "IN_PROGRESS"
 	OrderStatusFinished   OrderStatus = "FINISHED"
+	OrderStatusCancelled  OrderStatus = "CANCELLED"
+)
+
+type CancelReason string
+
+const (
+	CancelReasonCustomerRequest  CancelReason = "CUSTOMER_REQUEST"
+	CancelReasonCreatedByMistake CancelReason = "CREATED_BY_MISTAKE"
+	CancelReasonDuplicate        CancelReason = "DUPLICATE"
+	CancelReasonOutOfStock       CancelReason = "OUT_OF_STOCK"
+	CancelReasonFraudSuspected   CancelReason = "FRAUD_SUSPECTED"
+	CancelReasonOther            CancelReason = "OTHER"
 )
 
 type Order struct {
-	ID        string      `db:"id"`
-	UserID    string      `db:"user_id"`
-	Item      string      `db:"item"`
-	Amount    float64     `db:"amount"`
+	ID     string `db:"id"`
+	UserID string `db:"user_id"`
+	// Items are stored separately from the order row and are never empty.
+	Items []OrderItem `db:"-"`
+	// Amount is the total of the items.
+	Amount    Money       `db:"amount"`
 	Status    OrderStatus `db:"status"`
 	CreatedAt time.Time   `db:"created_at"`
+	// DeletedAt is set while the order is soft-deleted.
+	DeletedAt *time.Time `db:"deleted_at"`
+
+	OrderCancellation
+}
+
+// OrderCancellation records why, by whom and when an order was cancelled.
+// It is empty for orders that have never been cancelled.
+type OrderCancellation struct {
+	CancelReason  CancelReason `db:"cancel_reason" json:"cancel_reason"`
+	CancelComment string       `db:"cancel_comment" json:"cancel_comment"`
+	CancelledBy   string       `db:"cancelled_by" json:"cancelled_by"`
+	CancelledAt   *time.Time   `db:"cancelled_at" json:"cancelled_at"`
 }
@@ -0,0 +1,121 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type OrderEventType string
+
+const (
+	OrderEventCreated       OrderEventType = "ORDER_CREATED"
+	OrderEventStatusChanged OrderEventType = "ORDER_STATUS_CHANGED"
+	OrderEventCancelled     OrderEventType = "ORDER_CANCELLED"
+	OrderEventDeleted       OrderEventType = "ORDER_DELETED"
+	OrderEventRestored      OrderEventType = "ORDER_RESTORED"
+)
+
+// OrderEvent is a domain event recorded in the outbox together with the order change it describes.
+type OrderEvent struct {
+	// ID grows with every recorded event, so it orders the events of an order.
+	ID      int64          `db:"id"`
+	OrderID string         `db:"order_id"`
+	Type    OrderEventType `db:"type"`
+	// Payload is an OrderEventPayload encoded as JSON.
+	Payload   []byte    `db:"payload"`
+	CreatedAt time.Time `db:"created_at"`
+	// Attempts is the number of failed deliveries so far.
+	Attempts int `db:"attempts"`
+}
+
+// OrderEventPayload is the state of the order right after the change.
+type OrderEventPayload struct {
+	OrderID        string             `json:"order_id"`
+	UserID         string             `json:"user_id"`
+	Status         OrderStatus        `json:"status"`
+	PreviousStatus OrderStatus        `json:"previous_status,omitempty"`
+	Amount         string             `json:"amount"`
+	Currency       string             `json:"currency"`
+	Items          []OrderEventItem   `json:"items"`
+	CreatedAt      time.Time          `json:"created_at"`
+	Cancellation   *OrderCancellation `json:"cancellation,omitempty"`
+	DeletedAt      *time.Time         `json:"deleted_at,omitempty"`
+}
+
+type OrderEventItem struct {
+	SKU       string `json:"sku"`
+	Name      string `json:"name"`
+	Quantity  int64  `json:"quantity"`
+	UnitPrice string `json:"unit_price"`
+}
+
+// NewOrderEvent describes a change of order. previousStatus is empty for created orders.
+func NewOrderEvent(eventType OrderEventType, order *Order, previousStatus OrderStatus, now time.Time) (OrderEvent, error) {
+	payload := OrderEventPayload{
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		Status:         order.Status,
+		PreviousStatus: previousStatus,
+		Amount:         order.Amount.Decimal(),
+		Currency:       order.Amount.Currency,
+		Items:          make([]OrderEventItem, len(order.Items)),
+		CreatedAt:      order.CreatedAt,
+		DeletedAt:      order.DeletedAt,
+	}
+	for i, item := range order.Items {
+		payload.Items[i] = OrderEventItem{
+			SKU:       item.SKU,
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice.Decimal(),
+		}
+	}
+	if order.Status == OrderStatusCancelled {
+		cancellation := order.OrderCancellation
+		payload.Cancellation = &cancellation
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return OrderEvent{}, err
+	}
+	return OrderEvent{
+		OrderID:   order.ID,
+		Type:      eventType,
+		Payload:   data,
+		CreatedAt: now,
+	}, nil
+}
+
+// Order restores the order as it was right after the change the event describes.
+func (p *OrderEventPayload) Order() (*Order, error) {
+	amount, err := ParseMoney(p.Amount, p.Currency)
+	if err != nil {
+		return nil, err
+	}
+	order := &Order{
+		ID:        p.OrderID,
+		UserID:    p.UserID,
+		Items:     make([]OrderItem, len(p.Items)),
+		Amount:    amount,
+		Status:    p.Status,
+		CreatedAt: p.CreatedAt,
+		DeletedAt: p.DeletedAt,
+	}
+	for i, item := range p.Items {
+		unitPrice, err := ParseMoney(item.UnitPrice, p.Currency)
+		if err != nil {
+			return nil, err
+		}
+		order.Items[i] = OrderItem{
+			SKU:       item.SKU,
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			UnitPrice: unitPrice,
+		}
+	}
+	if p.Cancellation != nil {
+		order.OrderCancellation = *p.Cancellation
+	}
+	return order, nil
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderEventPayload_Order(t *testing.T) {
+	cancelledAt := time.Date(2024, 1, 16, 8, 0, 0, 0, time.UTC)
+	deletedAt := time.Date(2024, 1, 17, 9, 0, 0, 0, time.UTC)
+	order := &Order{
+		ID:     "order-1",
+		UserID: "user-1",
+		Items: []OrderItem{
+			{SKU: "A", Name: "Dinar item", Quantity: 3, UnitPrice: NewMoney(1005, "KWD")},
+			{SKU: "B", Quantity: 1, UnitPrice: NewMoney(1, "KWD")},
+		},
+		Amount:    NewMoney(3016, "KWD"),
+		Status:    OrderStatusCancelled,
+		CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 123000, time.UTC),
+		DeletedAt: &deletedAt,
+		OrderCancellation: OrderCancellation{
+			CancelReason: CancelReasonOutOfStock,
+			CancelledBy:  "support-agent",
+			CancelledAt:  &cancelledAt,
+		},
+	}
+
+	event, err := NewOrderEvent(OrderEventCancelled, order, OrderStatusNew, time.Now())
+	require.NoError(t, err)
+
+	var payload OrderEventPayload
+	require.NoError(t, json.Unmarshal(event.Payload, &payload))
+	assert.Equal(t, OrderStatusNew, payload.PreviousStatus)
+
+	got, err := payload.Order()
+	require.NoError(t, err)
+	assert.Equal(t, order, got)
+
+	payload.Amount = "not-a-number"
+	_, err = payload.Order()
+	assert.ErrorIs(t, err, ErrInvalidMoney)
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrItemCurrencyMismatch = errors.New("item currency differs from order currency")
+
+// OrderItem is a line of an order: a quantity of one SKU at a unit price.
+// The unit price is in the currency of the order.
+type OrderItem struct {
+	SKU       string `db:"sku"`
+	Name      string `db:"name"`
+	Quantity  int64  `db:"quantity"`
+	UnitPrice Money  `db:"unit_price"`
+}
+
+// Total returns Quantity * UnitPrice.
+func (i OrderItem) Total() (Money, error) {
+	if i.Quantity < 0 || i.UnitPrice.MinorUnits < 0 {
+		return Money{}, fmt.Errorf("%w: negative item total", ErrInvalidMoney)
+	}
+	if i.Quantity > 0 && i.UnitPrice.MinorUnits > math.MaxInt64/i.Quantity {
+		return Money{}, fmt.Errorf("%w: %d x %s", ErrMoneyOutOfRange, i.Quantity, i.UnitPrice)
+	}
+	total := NewMoney(i.Quantity*i.UnitPrice.MinorUnits, i.UnitPrice.Currency)
+	return total, total.Validate()
+}
+
+// ItemsTotal returns the sum of the item totals. All items must be priced in currency.
+func ItemsTotal(items []OrderItem, currency string) (Money, error) {
+	sum := NewMoney(0, currency)
+	for _, item := range items {
+		if item.UnitPrice.Currency != currency {
+			return Money{}, fmt.Errorf("%w: %s, order is in %s", ErrItemCurrencyMismatch, item.UnitPrice.Currency, currency)
+		}
+		total, err := item.Total()
+		if err != nil {
+			return Money{}, err
+		}
+		// Both values fit into the amount column, so the sum cannot overflow.
+		sum.MinorUnits += total.MinorUnits
+		if err := sum.Validate(); err != nil {
+			return Money{}, err
+		}
+	}
+	return sum, nil
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemsTotal(t *testing.T) {
+	items := []OrderItem{
+		{SKU: "A", Quantity: 3, UnitPrice: NewMoney(1005, "KWD")},
+		{SKU: "B", Quantity: 1, UnitPrice: NewMoney(1, "KWD")},
+	}
+
+	total, err := ItemsTotal(items, "KWD")
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(3016, "KWD"), total)
+
+	_, err = ItemsTotal(items, "EUR")
+	require.ErrorIs(t, err, ErrItemCurrencyMismatch)
+
+	huge := []OrderItem{
+		{SKU: "A", Quantity: 2, UnitPrice: MaxMoney("EUR")},
+	}
+	_, err = ItemsTotal(huge, "EUR")
+	require.ErrorIs(t, err, ErrMoneyOutOfRange)
+
+	overflow := []OrderItem{
+		{SKU: "A", Quantity: 1 << 40, UnitPrice: NewMoney(1<<40, "EUR")},
+	}
+	_, err = ItemsTotal(overflow, "EUR")
+	require.ErrorIs(t, err, ErrMoneyOutOfRange)
+}
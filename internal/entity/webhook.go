@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// WebhookSubscription is a partner endpoint notified of order events.
+type WebhookSubscription struct {
+	ID  string `db:"id"`
+	URL string `db:"url"`
+	// Secret is the HMAC-SHA256 key deliveries are signed with.
+	Secret string `db:"secret"`
+	// EventTypes are the events delivered to the endpoint, all events if empty.
+	EventTypes []OrderEventType `db:"-"`
+	CreatedAt  time.Time        `db:"created_at"`
+}
+
+// Matches reports whether events of eventType are delivered to the subscription.
+func (s *WebhookSubscription) Matches(eventType OrderEventType) bool {
+	return len(s.EventTypes) == 0 || slices.Contains(s.EventTypes, eventType)
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending deliveries are waiting for their next attempt.
+	WebhookDeliveryPending WebhookDeliveryStatus = "PENDING"
+	// WebhookDeliveryDelivered deliveries were accepted by the endpoint with a 2xx response.
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "DELIVERED"
+	// WebhookDeliveryDead deliveries ran out of attempts and are only retried when replayed.
+	WebhookDeliveryDead WebhookDeliveryStatus = "DEAD"
+)
+
+// WebhookDelivery is an order event to be sent to one subscription.
+type WebhookDelivery struct {
+	ID             int64          `db:"id"`
+	SubscriptionID string         `db:"subscription_id"`
+	EventID        int64          `db:"event_id"`
+	EventType      OrderEventType `db:"event_type"`
+	OrderID        string         `db:"order_id"`
+	// Payload is the OrderEventPayload of the event.
+	Payload []byte                `db:"payload"`
+	Status  WebhookDeliveryStatus `db:"status"`
+	// Attempts is the number of failed attempts since the delivery was created or replayed.
+	Attempts      int        `db:"attempts"`
+	LastError     string     `db:"last_error"`
+	NextAttemptAt time.Time  `db:"next_attempt_at"`
+	CreatedAt     time.Time  `db:"created_at"`
+	DeliveredAt   *time.Time `db:"delivered_at"`
+}
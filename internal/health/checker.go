@@ -0,0 +1,207 @@
+// Package health reports whether the service is alive and ready to serve,
+// through the grpc.health.v1 service and the /livez and /readyz endpoints.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"connectrpc.com/grpchealth"
+)
+
+var errNotChecked = errors.New("not checked yet")
+
+// Config configures how often the readiness checks run.
+type Config struct {
+	Interval time.Duration
+	// Timeout bounds every check.
+	Timeout time.Duration
+}
+
+// DefaultConfig returns the settings used for everything that is not set.
+func DefaultConfig() Config {
+	return Config{
+		Interval: 5 * time.Second,
+		Timeout:  2 * time.Second,
+	}
+}
+
+// Check is a readiness check. It returns an error when the service cannot
+// serve requests, e.g. because the database is unreachable.
+type Check struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type result struct {
+	name string
+	err  error
+}
+
+// Checker runs the readiness checks periodically, so that probes only read
+// the latest results and never wait for a slow dependency. The service is
+// ready once every check passed on the last run, until Drain is called.
+//
+// Checker implements grpchealth.Checker: the whole process and each of its
+// services are SERVING when ready and NOT_SERVING otherwise.
+type Checker struct {
+	config   Config
+	checks   []Check
+	services map[string]bool
+
+	mu       sync.RWMutex
+	results  []result
+	draining bool
+}
+
+// NewChecker creates a checker of the given checks for the named services.
+// It reports the service as not ready until Run completes the first round of checks.
+func NewChecker(config Config, services []string, checks ...Check) *Checker {
+	c := &Checker{
+		config:   config,
+		checks:   checks,
+		services: make(map[string]bool, len(services)),
+		results:  make([]result, len(checks)),
+	}
+	for _, service := range services {
+		c.services[service] = true
+	}
+	for i, check := range checks {
+		c.results[i] = result{name: check.Name, err: errNotChecked}
+	}
+	return c
+}
+
+// Run runs the checks every interval until ctx is done.
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+	for {
+		c.runChecks(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runChecks runs the checks concurrently and records their results.
+func (c *Checker) runChecks(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	results := make([]result, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = result{name: check.Name, err: check.Check(ctx)}
+		}(i, check)
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, r := range results {
+		previous := c.results[i].err
+		if r.err != nil && (previous == nil || previous == errNotChecked) {
+			slog.Warn("Health check failed", "check", r.name, "error", r.err)
+		} else if r.err == nil && previous != nil && previous != errNotChecked {
+			slog.Info("Health check recovered", "check", r.name)
+		}
+	}
+	c.results = results
+}
+
+// Drain marks the service as not ready for the rest of its life.
+func (c *Checker) Drain() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.draining = true
+}
+
+// Ready reports whether the service can serve requests.
+func (c *Checker) Ready() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ready()
+}
+
+// ready must be called with c.mu held.
+func (c *Checker) ready() bool {
+	if c.draining {
+		return false
+	}
+	for _, r := range c.results {
+		if r.err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Check implements grpchealth.Checker.
+func (c *Checker) Check(_ context.Context, req *grpchealth.CheckRequest) (*grpchealth.CheckResponse, error) {
+	if req.Service != "" && !c.services[req.Service] {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("unknown service %s", req.Service))
+	}
+	if c.Ready() {
+		return &grpchealth.CheckResponse{Status: grpchealth.StatusServing}, nil
+	}
+	return &grpchealth.CheckResponse{Status: grpchealth.StatusNotServing}, nil
+}
+
+// Livez answers the liveness probe. The process is alive as long as it can
+// answer, a failing dependency only makes it not ready.
+func (c *Checker) Livez(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = io.WriteString(w, "ok\n")
+}
+
+// Readyz answers the readiness probe with 200 when ready and 503 otherwise.
+// The body lists whether every check passed. The endpoint is unauthenticated,
+// so the errors of failed checks are only logged by runChecks.
+func (c *Checker) Readyz(w http.ResponseWriter, _ *http.Request) {
+	c.mu.RLock()
+	ready := c.ready()
+	draining := c.draining
+	results := c.results
+	c.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(w, "[-] %s: fail\n", r.name)
+		} else {
+			fmt.Fprintf(w, "[+] %s: ok\n", r.name)
+		}
+	}
+	if draining {
+		_, _ = io.WriteString(w, "[-] shutdown: fail\n")
+	}
+	if ready {
+		_, _ = io.WriteString(w, "ready\n")
+	} else {
+		_, _ = io.WriteString(w, "not ready\n")
+	}
+}
+
+// Register mounts grpc.health.v1 and the /livez and /readyz endpoints on mux.
+func (c *Checker) Register(mux *http.ServeMux, options ...connect.HandlerOption) {
+	mux.Handle(grpchealth.NewHandler(c, options...))
+	mux.HandleFunc("/livez", c.Livez)
+	mux.HandleFunc("/readyz", c.Readyz)
+}
@@ -0,0 +1,172 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"connectrpc.com/grpchealth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testService = "order.v1.OrderService"
+
+// switchCheck is a check whose result is changed by the test.
+type switchCheck struct {
+	err atomic.Pointer[error]
+}
+
+func (s *switchCheck) set(err error) {
+	s.err.Store(&err)
+}
+
+func (s *switchCheck) check(context.Context) error {
+	if err := s.err.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+func newChecker(checks ...Check) *Checker {
+	return NewChecker(Config{Interval: time.Hour, Timeout: time.Second}, []string{testService}, checks...)
+}
+
+func status(t *testing.T, c *Checker, service string) grpchealth.Status {
+	resp, err := c.Check(context.Background(), &grpchealth.CheckRequest{Service: service})
+	require.NoError(t, err)
+	return resp.Status
+}
+
+func get(t *testing.T, handler http.HandlerFunc) (int, string) {
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	body, err := io.ReadAll(rec.Result().Body)
+	require.NoError(t, err)
+	return rec.Code, string(body)
+}
+
+func TestChecker_Readiness(t *testing.T) {
+	store := &switchCheck{}
+	workers := &switchCheck{}
+	c := newChecker(Check{Name: "store", Check: store.check}, Check{Name: "workers", Check: workers.check})
+	ctx := context.Background()
+
+	assert.False(t, c.Ready(), "Should not be ready before the first check")
+	code, body := get(t, c.Readyz)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, body, "[-] store: fail")
+
+	c.runChecks(ctx)
+	assert.True(t, c.Ready())
+	assert.Equal(t, grpchealth.StatusServing, status(t, c, ""))
+	assert.Equal(t, grpchealth.StatusServing, status(t, c, testService))
+	code, body = get(t, c.Readyz)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "[+] store: ok\n[+] workers: ok\nready\n", body)
+
+	store.set(errors.New("connection refused"))
+	c.runChecks(ctx)
+	assert.False(t, c.Ready())
+	assert.Equal(t, grpchealth.StatusNotServing, status(t, c, ""))
+	assert.Equal(t, grpchealth.StatusNotServing, status(t, c, testService))
+	code, body = get(t, c.Readyz)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "[-] store: fail\n[+] workers: ok\nnot ready\n", body)
+	assert.NotContains(t, body, "connection refused", "Errors should not be exposed to unauthenticated callers")
+
+	store.set(nil)
+	c.runChecks(ctx)
+	assert.True(t, c.Ready(), "Should be ready again once the check recovers")
+}
+
+func TestChecker_Drain(t *testing.T) {
+	c := newChecker(Check{Name: "store", Check: func(context.Context) error { return nil }})
+	c.runChecks(context.Background())
+	require.True(t, c.Ready())
+
+	c.Drain()
+	c.runChecks(context.Background())
+
+	assert.False(t, c.Ready(), "Passing checks should not make a draining service ready")
+	assert.Equal(t, grpchealth.StatusNotServing, status(t, c, testService))
+	code, body := get(t, c.Readyz)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, body, "[-] shutdown: fail")
+
+	code, body = get(t, c.Livez)
+	assert.Equal(t, http.StatusOK, code, "A draining service is still alive")
+	assert.Equal(t, "ok\n", body)
+}
+
+func TestChecker_CheckTimeout(t *testing.T) {
+	c := NewChecker(Config{Interval: time.Hour, Timeout: 10 * time.Millisecond}, nil, Check{
+		Name: "slow",
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	c.runChecks(context.Background())
+
+	assert.False(t, c.Ready())
+	assert.ErrorIs(t, c.results[0].err, context.DeadlineExceeded)
+	_, body := get(t, c.Readyz)
+	assert.Contains(t, body, "[-] slow: fail")
+}
+
+func TestChecker_Run(t *testing.T) {
+	var runs atomic.Int64
+	c := NewChecker(Config{Interval: 10 * time.Millisecond, Timeout: time.Second}, nil, Check{
+		Name: "store",
+		Check: func(context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	assert.True(t, c.Ready())
+	cancel()
+	<-done
+}
+
+func TestChecker_UnknownService(t *testing.T) {
+	c := newChecker()
+
+	_, err := c.Check(context.Background(), &grpchealth.CheckRequest{Service: "order.v1.UnknownService"})
+
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func TestChecker_Register(t *testing.T) {
+	c := newChecker()
+	c.runChecks(context.Background())
+	mux := http.NewServeMux()
+	c.Register(mux)
+
+	for path, want := range map[string]int{
+		"/livez":  http.StatusOK,
+		"/readyz": http.StatusOK,
+		// The Connect handler rejects GET requests to a unary procedure.
+		"/grpc.health.v1.Health/Check": http.StatusMethodNotAllowed,
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, want, rec.Code, path)
+	}
+}
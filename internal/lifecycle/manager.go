@@ -0,0 +1,247 @@
+// Package lifecycle runs the server and the background workers of the service
+// and shuts them down gracefully.
+package lifecycle
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// drainPollInterval is how often the number of in-flight requests is checked during shutdown.
+const drainPollInterval = 50 * time.Millisecond
+
+// Manager serves RPCs over HTTP/1.1 and HTTP/2, with or without TLS, and runs
+// background workers. When its context is done it shuts down in order:
+//
+//  1. runs the drain hooks and keeps serving for the drain delay;
+//  2. stops accepting connections and runs the shutdown hooks;
+//  3. waits for in-flight requests until the shutdown timeout;
+//  4. stops the workers one by one in the order they were added;
+//  5. runs the closers in the order they were added.
+//
+// The shutdown timeout bounds steps 3 and 4 together.
+type Manager struct {
+	server          *http.Server
+	shutdownTimeout time.Duration
+	drainDelay      time.Duration
+	tls             bool
+
+	// inflight counts the requests being handled. http.Server.Shutdown does not
+	// wait for requests on HTTP/2 connections, which h2c takes over from it.
+	inflight   atomic.Int64
+	drainHooks []func()
+	workers    []*worker
+	closers    []closer
+	// stopping is set once shutdown begins, workers may stop from then on.
+	stopping atomic.Bool
+}
+
+type worker struct {
+	name   string
+	run    func(ctx context.Context)
+	exited atomic.Bool
+}
+
+type closer struct {
+	name  string
+	close func() error
+}
+
+type ServerConfig struct {
+	// ShutdownTimeout bounds draining in-flight requests and stopping the workers.
+	ShutdownTimeout time.Duration
+	// DrainDelay is how long the server keeps accepting requests after the
+	// drain hooks run, so that load balancers see the instance is not ready
+	// before its connections are refused.
+	DrainDelay        time.Duration
+	ReadHeaderTimeout time.Duration
+	IdleTimeout       time.Duration
+	// TLSCertFile and TLSKeyFile enable TLS. Without them the server speaks
+	// HTTP/1.1 and cleartext HTTP/2.
+	TLSCertFile string
+	TLSKeyFile  string
+}
+
+func NewManager(handler http.Handler, config ServerConfig) (*Manager, error) {
+	m := &Manager{shutdownTimeout: config.ShutdownTimeout, drainDelay: config.DrainDelay}
+
+	h2s := &http2.Server{}
+	m.server = &http.Server{
+		Handler:           h2c.NewHandler(m.track(handler), h2s),
+		ReadHeaderTimeout: config.ReadHeaderTimeout,
+		IdleTimeout:       config.IdleTimeout,
+	}
+	if config.TLSCertFile != "" || config.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load TLS certificate: %w", err)
+		}
+		m.server.TLSConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+		m.tls = true
+	}
+	// Enables HTTP/2 over TLS and lets Shutdown send GOAWAY on HTTP/2
+	// connections, so clients stop sending new requests on them.
+	if err := http2.ConfigureServer(m.server, h2s); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Go adds a worker that runs until its ctx is done.
+func (m *Manager) Go(name string, run func(ctx context.Context)) {
+	m.workers = append(m.workers, &worker{name: name, run: run})
+}
+
+// OnDrain adds a hook run as soon as shutdown begins, while the server still
+// accepts requests. It must not block, and is meant to fail readiness checks.
+func (m *Manager) OnDrain(hook func()) {
+	m.drainHooks = append(m.drainHooks, hook)
+}
+
+// OnShutdown adds a hook run once the server stops accepting connections. It
+// must not block, and is meant to end long-lived requests such as streams.
+func (m *Manager) OnShutdown(hook func()) {
+	m.server.RegisterOnShutdown(hook)
+}
+
+// OnClose adds a function run after the server and the workers are stopped.
+func (m *Manager) OnClose(name string, close func() error) {
+	m.closers = append(m.closers, closer{name: name, close: close})
+}
+
+// Run serves on ln and runs the workers until ctx is done or the server fails,
+// then shuts down. It returns the error that stopped the server, if any.
+func (m *Manager) Run(ctx context.Context, ln net.Listener) error {
+	// Workers get their own contexts, so they keep running while the server drains.
+	stops := make([]context.CancelFunc, len(m.workers))
+	stopped := make([]chan struct{}, len(m.workers))
+	for i, w := range m.workers {
+		var workerCtx context.Context
+		workerCtx, stops[i] = context.WithCancel(context.Background())
+		defer stops[i]()
+		stopped[i] = make(chan struct{})
+		go func(w *worker, workerCtx context.Context, stopped chan struct{}) {
+			defer close(stopped)
+			w.run(workerCtx)
+			w.exited.Store(true)
+			if !m.stopping.Load() {
+				slog.Error("Worker stopped unexpectedly", "worker", w.name)
+			}
+		}(w, workerCtx, stopped[i])
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if m.tls {
+			serveErr <- m.server.ServeTLS(ln, "", "")
+		} else {
+			serveErr <- m.server.Serve(ln)
+		}
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		slog.Info("Shutting down")
+		m.drain()
+	case err := <-serveErr:
+		runErr = err
+		m.stopping.Store(true)
+		slog.Error("Server failed, shutting down", "error", err)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+	defer cancel()
+
+	if err := m.shutdownServer(shutdownCtx); err != nil {
+		slog.Warn("In-flight requests did not finish in time, closing connections", "error", err)
+		m.server.Close()
+	}
+
+	for i, w := range m.workers {
+		stops[i]()
+		select {
+		case <-stopped[i]:
+		case <-shutdownCtx.Done():
+			slog.Warn("Worker did not stop in time", "worker", w.name)
+		}
+	}
+
+	for _, c := range m.closers {
+		if err := c.close(); err != nil {
+			slog.Error("Failed to close", "closer", c.name, "error", err)
+		}
+	}
+	slog.Info("Shutdown complete")
+	return runErr
+}
+
+// drain runs the drain hooks and waits for the drain delay.
+func (m *Manager) drain() {
+	m.stopping.Store(true)
+	for _, hook := range m.drainHooks {
+		hook()
+	}
+	if m.drainDelay > 0 {
+		slog.Info("Draining before closing the listener", "delay", m.drainDelay)
+		time.Sleep(m.drainDelay)
+	}
+}
+
+// CheckWorkers returns an error naming the workers that stopped before shutdown.
+func (m *Manager) CheckWorkers(context.Context) error {
+	if m.stopping.Load() {
+		return nil
+	}
+	var names []string
+	for _, w := range m.workers {
+		if w.exited.Load() {
+			names = append(names, w.name)
+		}
+	}
+	if len(names) > 0 {
+		return fmt.Errorf("workers stopped unexpectedly: %s", strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// shutdownServer stops accepting connections and waits for in-flight requests until ctx is done.
+func (m *Manager) shutdownServer(ctx context.Context) error {
+	if err := m.server.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for m.inflight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// track counts the requests being handled by next.
+func (m *Manager) track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.inflight.Add(1)
+		defer m.inflight.Add(-1)
+		next.ServeHTTP(w, r)
+	})
+}
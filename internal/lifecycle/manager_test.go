@@ -0,0 +1,238 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// steps records the order in which the parts of the service were stopped.
+type steps struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (s *steps) add(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.names = append(s.names, name)
+}
+
+func (s *steps) get() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.names...)
+}
+
+func newManager(t *testing.T, handler http.Handler, shutdownTimeout time.Duration) *Manager {
+	return newManagerWithConfig(t, handler, ServerConfig{ShutdownTimeout: shutdownTimeout, ReadHeaderTimeout: time.Second})
+}
+
+func newManagerWithConfig(t *testing.T, handler http.Handler, config ServerConfig) *Manager {
+	m, err := NewManager(handler, config)
+	require.NoError(t, err)
+	return m
+}
+
+func listen(t *testing.T) net.Listener {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	return ln
+}
+
+// run starts the manager and returns a function that stops it and returns the result of Run.
+func run(m *Manager, ln net.Listener) func() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- m.Run(ctx, ln)
+	}()
+	return func() error {
+		cancel()
+		return <-result
+	}
+}
+
+func TestManager_DrainsInFlightRequests(t *testing.T) {
+	var stopped steps
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		stopped.add("request")
+		_, _ = io.WriteString(w, "done")
+	})
+
+	m := newManager(t, handler, 5*time.Second)
+	for _, name := range []string{"first worker", "second worker"} {
+		name := name
+		m.Go(name, func(ctx context.Context) {
+			<-ctx.Done()
+			stopped.add(name)
+		})
+	}
+	m.OnShutdown(func() { stopped.add("shutdown hook") })
+	m.OnClose("store", func() error {
+		stopped.add("store")
+		return errors.New("already closed")
+	})
+
+	ln := listen(t)
+	stop := run(m, ln)
+
+	response := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			response <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		response <- string(body)
+	}()
+	<-started
+
+	result := make(chan error, 1)
+	go func() { result <- stop() }()
+
+	// New connections are refused while the request is in flight.
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err == nil {
+			conn.Close()
+		}
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+	// Hooks run in the background.
+	require.Eventually(t, func() bool { return len(stopped.get()) > 0 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"shutdown hook"}, stopped.get(), "Workers should keep running while requests drain")
+
+	close(release)
+	assert.Equal(t, "done", <-response)
+	assert.NoError(t, <-result, "A failing closer should only be logged")
+	assert.Equal(t, []string{"shutdown hook", "request", "first worker", "second worker", "store"}, stopped.get())
+}
+
+func TestManager_ShutdownTimeout(t *testing.T) {
+	var stopped steps
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	})
+
+	m := newManager(t, handler, 100*time.Millisecond)
+	m.Go("stuck worker", func(ctx context.Context) {
+		select {}
+	})
+	m.OnClose("store", func() error {
+		stopped.add("store")
+		return nil
+	})
+
+	ln := listen(t)
+	stop := run(m, ln)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+
+	begin := time.Now()
+	require.NoError(t, stop())
+	assert.Less(t, time.Since(begin), time.Second, "Shutdown should not wait past the timeout")
+	assert.Equal(t, []string{"store"}, stopped.get(), "Closers should run after the timeout")
+}
+
+func TestManager_ServerFailure(t *testing.T) {
+	var stopped steps
+	m := newManager(t, http.NotFoundHandler(), time.Second)
+	m.Go("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		stopped.add("worker")
+	})
+	m.OnClose("store", func() error {
+		stopped.add("store")
+		return nil
+	})
+
+	ln := listen(t)
+	require.NoError(t, ln.Close())
+
+	err := m.Run(context.Background(), ln)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"worker", "store"}, stopped.get())
+}
+
+func TestManager_DrainDelay(t *testing.T) {
+	var stopped steps
+	var draining atomic.Bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if draining.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+
+	m := newManagerWithConfig(t, handler, ServerConfig{
+		ShutdownTimeout:   time.Second,
+		DrainDelay:        300 * time.Millisecond,
+		ReadHeaderTimeout: time.Second,
+	})
+	m.OnDrain(func() {
+		draining.Store(true)
+		stopped.add("drain hook")
+	})
+	m.OnShutdown(func() { stopped.add("shutdown hook") })
+
+	ln := listen(t)
+	stop := run(m, ln)
+	result := make(chan error, 1)
+	go func() { result <- stop() }()
+
+	require.Eventually(t, draining.Load, time.Second, 10*time.Millisecond)
+	// New requests are still accepted during the delay, and see the service draining.
+	resp, err := http.Get("http://" + ln.Addr().String())
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, []string{"drain hook"}, stopped.get())
+
+	assert.NoError(t, <-result)
+	// Shutdown hooks run in the background.
+	require.Eventually(t, func() bool { return len(stopped.get()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"drain hook", "shutdown hook"}, stopped.get())
+}
+
+func TestManager_CheckWorkers(t *testing.T) {
+	m := newManager(t, http.NotFoundHandler(), time.Second)
+	m.Go("running worker", func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	failed := make(chan struct{})
+	m.Go("failed worker", func(ctx context.Context) {
+		<-failed
+	})
+
+	stop := run(m, listen(t))
+	assert.NoError(t, m.CheckWorkers(context.Background()))
+
+	close(failed)
+	require.Eventually(t, func() bool { return m.CheckWorkers(context.Background()) != nil }, time.Second, 10*time.Millisecond)
+	assert.EqualError(t, m.CheckWorkers(context.Background()), "workers stopped unexpectedly: failed worker")
+
+	require.NoError(t, stop())
+	assert.NoError(t, m.CheckWorkers(context.Background()), "Workers stopped by shutdown are expected")
+}
@@ -0,0 +1,133 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader carries the request ID. A request ID sent by the caller is
+// kept, otherwise one is generated. It is returned in the response headers.
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength bounds the request IDs accepted from callers.
+const maxRequestIDLength = 128
+
+// Interceptor puts a request logger in the context of the handlers it is added
+// to and writes an access log line for every RPC.
+type Interceptor struct {
+	logger *slog.Logger
+}
+
+var _ connect.Interceptor = (*Interceptor)(nil)
+
+func NewInterceptor(logger *slog.Logger) *Interceptor {
+	return &Interceptor{logger: logger}
+}
+
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		start := time.Now()
+		requestID := requestID(req.Header().Get(RequestIDHeader))
+		logger := i.requestLogger(ctx, requestID, req.Spec().Procedure)
+
+		resp, err := next(WithLogger(ctx, logger), req)
+		if err != nil {
+			var connectErr *connect.Error
+			if errors.As(err, &connectErr) {
+				connectErr.Meta().Set(RequestIDHeader, requestID)
+			}
+		} else {
+			resp.Header().Set(RequestIDHeader, requestID)
+		}
+		access(ctx, logger, start, userID(req.Any()), err)
+		return resp, err
+	}
+}
+
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		start := time.Now()
+		requestID := requestID(conn.RequestHeader().Get(RequestIDHeader))
+		logger := i.requestLogger(ctx, requestID, conn.Spec().Procedure)
+		conn.ResponseHeader().Set(RequestIDHeader, requestID)
+
+		received := &receivedConn{StreamingHandlerConn: conn}
+		err := next(WithLogger(ctx, logger), received)
+		access(ctx, logger, start, received.userID, err)
+		return err
+	}
+}
+
+// requestLogger returns the logger of an RPC, with the trace ID if the RPC is traced.
+func (i *Interceptor) requestLogger(ctx context.Context, requestID, procedure string) *slog.Logger {
+	logger := i.logger.With(slog.String("request_id", requestID), slog.String("procedure", procedure))
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		logger = logger.With(slog.String("trace_id", span.TraceID().String()))
+	}
+	return logger
+}
+
+// receivedConn remembers the user ID of the first request message of a stream.
+type receivedConn struct {
+	connect.StreamingHandlerConn
+	userID string
+}
+
+func (c *receivedConn) Receive(msg any) error {
+	err := c.StreamingHandlerConn.Receive(msg)
+	if err == nil && c.userID == "" {
+		c.userID = userID(msg)
+	}
+	return err
+}
+
+func requestID(header string) string {
+	if header != "" && len(header) <= maxRequestIDLength {
+		return header
+	}
+	return uuid.NewString()
+}
+
+// userID returns the user ID of request messages that have one, such as CreateOrderRequest.
+func userID(msg any) string {
+	if m, ok := msg.(interface{ GetUserId() string }); ok {
+		return m.GetUserId()
+	}
+	return ""
+}
+
+// access writes the access log line of an RPC. Failures of the service are
+// logged as errors, failures of the request as information.
+func access(ctx context.Context, logger *slog.Logger, start time.Time, userID string, err error) {
+	level := slog.LevelInfo
+	attrs := []slog.Attr{
+		slog.String("code", "ok"),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if userID != "" {
+		attrs = append(attrs, slog.String("user_id", userID))
+	}
+	if err != nil {
+		code := connect.CodeOf(err)
+		attrs[0] = slog.String("code", code.String())
+		attrs = append(attrs, slog.String("error", err.Error()))
+		switch code {
+		case connect.CodeInternal, connect.CodeUnknown, connect.CodeDataLoss:
+			level = slog.LevelError
+		}
+	}
+	logger.LogAttrs(ctx, level, "RPC handled", attrs...)
+}
@@ -0,0 +1,148 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/contracts/gen/go/order/v1/orderv1connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderService creates orders, fails on GetOrder for "broken" and streams a
+// single update on WatchOrders. Every handler logs with the request logger.
+type orderService struct {
+	orderv1connect.UnimplementedOrderServiceHandler
+}
+
+func (orderService) CreateOrder(
+	ctx context.Context,
+	_ *connect.Request[orderv1.CreateOrderRequest],
+) (*connect.Response[orderv1.CreateOrderResponse], error) {
+	FromContext(ctx).Info("Creating order")
+	return connect.NewResponse(&orderv1.CreateOrderResponse{}), nil
+}
+
+func (orderService) GetOrder(
+	ctx context.Context,
+	req *connect.Request[orderv1.GetOrderRequest],
+) (*connect.Response[orderv1.GetOrderResponse], error) {
+	if req.Msg.Id == "broken" {
+		err := errors.New("connection reset")
+		FromContext(ctx).Error("Failed to get order", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return nil, connect.NewError(connect.CodeNotFound, errors.New("order not found"))
+}
+
+func (orderService) WatchOrders(
+	_ context.Context,
+	_ *connect.Request[orderv1.WatchOrdersRequest],
+	stream *connect.ServerStream[orderv1.WatchOrdersResponse],
+) error {
+	return stream.Send(&orderv1.WatchOrdersResponse{})
+}
+
+// newClient serves orderService with the interceptor and returns the log lines written so far.
+func newClient(t *testing.T) (orderv1connect.OrderServiceClient, func() []map[string]any) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, nil))
+	path, handler := orderv1connect.NewOrderServiceHandler(orderService{},
+		connect.WithInterceptors(NewInterceptor(logger)))
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	lines := func() []map[string]any {
+		var result []map[string]any
+		decoder := json.NewDecoder(bytes.NewReader(out.Bytes()))
+		for decoder.More() {
+			var line map[string]any
+			require.NoError(t, decoder.Decode(&line))
+			result = append(result, line)
+		}
+		return result
+	}
+	return orderv1connect.NewOrderServiceClient(server.Client(), server.URL), lines
+}
+
+func TestInterceptor_Unary(t *testing.T) {
+	client, lines := newClient(t)
+
+	resp, err := client.CreateOrder(context.Background(), connect.NewRequest(&orderv1.CreateOrderRequest{UserId: "user-1"}))
+	require.NoError(t, err)
+
+	requestID := resp.Header().Get(RequestIDHeader)
+	require.NotEmpty(t, requestID, "A request ID should be generated")
+	logged := lines()
+	require.Len(t, logged, 2)
+	assert.Equal(t, "Creating order", logged[0]["msg"])
+	assert.Equal(t, requestID, logged[0]["request_id"], "Handlers should log with the request logger")
+
+	access := logged[1]
+	assert.Equal(t, "RPC handled", access["msg"])
+	assert.Equal(t, "INFO", access["level"])
+	assert.Equal(t, requestID, access["request_id"])
+	assert.Equal(t, "/order.v1.OrderService/CreateOrder", access["procedure"])
+	assert.Equal(t, "ok", access["code"])
+	assert.Equal(t, "user-1", access["user_id"])
+	assert.Contains(t, access, "duration")
+}
+
+func TestInterceptor_RequestIDFromCaller(t *testing.T) {
+	client, lines := newClient(t)
+
+	req := connect.NewRequest(&orderv1.GetOrderRequest{Id: "missing"})
+	req.Header().Set(RequestIDHeader, "req-42")
+	_, err := client.GetOrder(context.Background(), req)
+
+	var connectErr *connect.Error
+	require.ErrorAs(t, err, &connectErr)
+	assert.Equal(t, "req-42", connectErr.Meta().Get(RequestIDHeader), "Errors should carry the request ID")
+	logged := lines()
+	require.Len(t, logged, 1)
+	assert.Equal(t, "req-42", logged[0]["request_id"])
+	assert.Equal(t, "not_found", logged[0]["code"])
+	assert.Equal(t, "INFO", logged[0]["level"], "Failures of the request are not errors of the service")
+	assert.NotContains(t, logged[0], "user_id")
+}
+
+func TestInterceptor_InternalError(t *testing.T) {
+	client, lines := newClient(t)
+
+	_, err := client.GetOrder(context.Background(), connect.NewRequest(&orderv1.GetOrderRequest{Id: "broken"}))
+	require.Error(t, err)
+
+	logged := lines()
+	require.Len(t, logged, 2)
+	assert.Equal(t, "Failed to get order", logged[0]["msg"])
+	assert.Equal(t, "connection reset", logged[0]["error"])
+	assert.Equal(t, "ERROR", logged[1]["level"])
+	assert.Equal(t, "internal", logged[1]["code"])
+	assert.Equal(t, "internal: connection reset", logged[1]["error"])
+}
+
+func TestInterceptor_Streaming(t *testing.T) {
+	client, lines := newClient(t)
+
+	stream, err := client.WatchOrders(context.Background(), connect.NewRequest(&orderv1.WatchOrdersRequest{UserId: "user-1"}))
+	require.NoError(t, err)
+	for stream.Receive() {
+	}
+	require.NoError(t, stream.Close())
+
+	assert.NotEmpty(t, stream.ResponseHeader().Get(RequestIDHeader))
+	logged := lines()
+	require.Len(t, logged, 1)
+	assert.Equal(t, "/order.v1.OrderService/WatchOrders", logged[0]["procedure"])
+	assert.Equal(t, "user-1", logged[0]["user_id"], "The user ID should be taken from the stream request")
+}
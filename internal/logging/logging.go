@@ -0,0 +1,57 @@
+// Package logging sets up structured logging with log/slog and carries a
+// request-scoped logger in the context of every RPC.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// Formats of Config.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+type Config struct {
+	// Format is FormatJSON or FormatText.
+	Format string
+	// Level is the minimum level logged: "debug", "info", "warn" or "error".
+	Level string
+}
+
+// New creates a logger writing to w.
+func New(w io.Writer, config Config) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(config.Level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", config.Level)
+	}
+	options := &slog.HandlerOptions{Level: level}
+
+	switch config.Format {
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q", config.Format)
+	}
+}
+
+type contextKey struct{}
+
+// WithLogger returns a copy of ctx carrying logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger of ctx, e.g. the request logger set by
+// Interceptor with the request ID and the procedure, or slog.Default.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
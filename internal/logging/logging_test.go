@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  Config
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "json",
+			config: Config{Format: FormatJSON, Level: "info"},
+			want:   `"msg":"Order created","order_id":"1"`,
+		},
+		{
+			name:   "text",
+			config: Config{Format: FormatText, Level: "info"},
+			want:   `msg="Order created" order_id=1`,
+		},
+		{
+			name:    "unknown format",
+			config:  Config{Format: "xml", Level: "info"},
+			wantErr: `unknown log format "xml"`,
+		},
+		{
+			name:    "unknown level",
+			config:  Config{Format: FormatJSON, Level: "trace"},
+			wantErr: `invalid log level "trace"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			logger, err := New(&out, tc.config)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			logger.Debug("Order fetched", "order_id", "1")
+			logger.Info("Order created", "order_id", "1")
+			assert.Contains(t, out.String(), tc.want)
+			assert.NotContains(t, out.String(), "Order fetched", "Debug should be below the level")
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Same(t, slog.Default(), FromContext(context.Background()))
+
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, nil)).With("request_id", "abc")
+	FromContext(WithLogger(context.Background(), logger)).Info("Order created")
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &line))
+	assert.Equal(t, "abc", line["request_id"])
+}
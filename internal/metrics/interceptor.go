@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// codeOK labels RPCs that succeeded, connect.Code has no value for it.
+const codeOK = "ok"
+
+// Interceptor records the number, the Connect error codes and the latency of
+// the RPCs served by the handlers it is added to.
+type Interceptor struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+var _ connect.Interceptor = (*Interceptor)(nil)
+
+func NewInterceptor(registerer prometheus.Registerer) *Interceptor {
+	i := &Interceptor{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rpc_requests_total",
+			Help:      "RPCs handled, by procedure and Connect code.",
+		}, []string{"procedure", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rpc_duration_seconds",
+			Help:      "Time to handle an RPC, for streams until the stream ends.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"procedure"}),
+	}
+	registerer.MustRegister(i.requests, i.duration)
+	return i
+}
+
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		start := time.Now()
+		resp, err := next(ctx, req)
+		i.observe(req.Spec().Procedure, start, err)
+		return resp, err
+	}
+}
+
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		start := time.Now()
+		err := next(ctx, conn)
+		i.observe(conn.Spec().Procedure, start, err)
+		return err
+	}
+}
+
+func (i *Interceptor) observe(procedure string, start time.Time, err error) {
+	code := codeOK
+	if err != nil {
+		code = connect.CodeOf(err).String()
+	}
+	i.requests.WithLabelValues(procedure, code).Inc()
+	i.duration.WithLabelValues(procedure).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/contracts/gen/go/order/v1/orderv1connect"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderService answers GetOrder for "found" and fails otherwise, and streams a
+// single update on WatchOrders.
+type orderService struct {
+	orderv1connect.UnimplementedOrderServiceHandler
+}
+
+func (orderService) GetOrder(
+	_ context.Context,
+	req *connect.Request[orderv1.GetOrderRequest],
+) (*connect.Response[orderv1.GetOrderResponse], error) {
+	if req.Msg.Id != "found" {
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("order not found"))
+	}
+	return connect.NewResponse(&orderv1.GetOrderResponse{Order: &orderv1.Order{Id: req.Msg.Id}}), nil
+}
+
+func (orderService) WatchOrders(
+	_ context.Context,
+	_ *connect.Request[orderv1.WatchOrdersRequest],
+	stream *connect.ServerStream[orderv1.WatchOrdersResponse],
+) error {
+	return stream.Send(&orderv1.WatchOrdersResponse{})
+}
+
+func TestInterceptor(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	path, handler := orderv1connect.NewOrderServiceHandler(orderService{},
+		connect.WithInterceptors(NewInterceptor(registry)))
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := orderv1connect.NewOrderServiceClient(server.Client(), server.URL)
+	ctx := context.Background()
+
+	for _, id := range []string{"found", "found", "missing"} {
+		_, _ = client.GetOrder(ctx, connect.NewRequest(&orderv1.GetOrderRequest{Id: id}))
+	}
+	_, err := client.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{}))
+	require.Error(t, err)
+	stream, err := client.WatchOrders(ctx, connect.NewRequest(&orderv1.WatchOrdersRequest{}))
+	require.NoError(t, err)
+	for stream.Receive() {
+	}
+	require.NoError(t, stream.Close())
+
+	want := `
+# HELP order_rpc_requests_total RPCs handled, by procedure and Connect code.
+# TYPE order_rpc_requests_total counter
+order_rpc_requests_total{code="not_found",procedure="/order.v1.OrderService/GetOrder"} 1
+order_rpc_requests_total{code="ok",procedure="/order.v1.OrderService/GetOrder"} 2
+order_rpc_requests_total{code="ok",procedure="/order.v1.OrderService/WatchOrders"} 1
+order_rpc_requests_total{code="unimplemented",procedure="/order.v1.OrderService/ListOrders"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(want), "order_rpc_requests_total"))
+
+	count, err := testutil.GatherAndCount(registry, "order_rpc_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 3, count, "Latency should be recorded for every procedure")
+}
@@ -0,0 +1,35 @@
+// Package metrics exports Prometheus metrics of the RPCs, the order store and
+// the orders themselves.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the metrics of the service.
+const namespace = "order"
+
+// NewRegistry returns a registry with the Go runtime and process metrics.
+func NewRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return registry
+}
+
+// RegisterDBStats exports the connection pool statistics of db as go_sql_* gauges and counters.
+func RegisterDBStats(registerer prometheus.Registerer, db *sql.DB) {
+	registerer.MustRegister(collectors.NewDBStatsCollector(db, "orders"))
+}
+
+// Handler serves the metrics of gatherer in the Prometheus text format.
+func Handler(gatherer prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+}
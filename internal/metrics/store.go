@@ -0,0 +1,335 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// expectedErrors are outcomes reported by the store rather than failures of it,
+// so they are not counted as errors.
+var expectedErrors = []error{
+	context.Canceled,
+	store.ErrOrderNotFound,
+	store.ErrOrderAlreadyExists,
+	store.ErrOrderStatusConflict,
+	store.ErrOrderEventNotFound,
+	store.ErrOrderNotDeleted,
+	store.ErrIdempotencyKeyNotFound,
+	store.ErrIdempotencyKeyExists,
+	store.ErrInvalidPageToken,
+	store.ErrWebhookSubscriptionNotFound,
+	store.ErrWebhookDeliveryNotFound,
+	store.ErrWebhookDeliveryNotDead,
+}
+
+// Store records the duration, the errors and the rows of every call to the
+// wrapped store, and counts the orders created and moved to each status.
+type Store struct {
+	next store.OrderStore
+
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	rows     *prometheus.CounterVec
+	orders   *prometheus.CounterVec
+	amount   *prometheus.CounterVec
+}
+
+var _ store.OrderStore = (*Store)(nil)
+
+func NewStore(next store.OrderStore, registerer prometheus.Registerer) *Store {
+	s := &Store{
+		next: next,
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "store_query_duration_seconds",
+			Help:      "Time taken by order store calls, by method.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "store_errors_total",
+			Help:      "Failed order store calls, by method. Expected outcomes such as not found are not counted.",
+		}, []string{"method"}),
+		rows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "store_rows_total",
+			Help:      "Rows returned or changed by order store calls, by method.",
+		}, []string{"method"}),
+		orders: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "orders_total",
+			Help:      "Orders that reached a status, created orders are counted as NEW.",
+		}, []string{"status"}),
+		amount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "orders_created_amount_total",
+			Help:      "Sum of the amounts of created orders in major units, by currency.",
+		}, []string{"currency"}),
+	}
+	registerer.MustRegister(s.duration, s.errors, s.rows, s.orders, s.amount)
+	return s
+}
+
+// observe records a call of method that started at start and returned rows rows.
+func (s *Store) observe(method string, start time.Time, err error, rows int) {
+	s.duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		for _, expected := range expectedErrors {
+			if errors.Is(err, expected) {
+				return
+			}
+		}
+		s.errors.WithLabelValues(method).Inc()
+		return
+	}
+	if rows > 0 {
+		s.rows.WithLabelValues(method).Add(float64(rows))
+	}
+}
+
+func (s *Store) created(order *entity.Order) {
+	s.orders.WithLabelValues(string(order.Status)).Inc()
+	s.amount.WithLabelValues(order.Amount.Currency).Add(order.Amount.Float64())
+}
+
+func (s *Store) Create(ctx context.Context, order *entity.Order) error {
+	start := time.Now()
+	err := s.next.Create(ctx, order)
+	s.observe("Create", start, err, 1)
+	if err == nil {
+		s.created(order)
+	}
+	return err
+}
+
+func (s *Store) CreateWithIdempotencyKey(ctx context.Context, order *entity.Order, record store.IdempotencyRecord) error {
+	start := time.Now()
+	err := s.next.CreateWithIdempotencyKey(ctx, order, record)
+	s.observe("CreateWithIdempotencyKey", start, err, 1)
+	if err == nil {
+		s.created(order)
+	}
+	return err
+}
+
+func (s *Store) GetIdempotencyRecord(ctx context.Context, userID, key string, now time.Time) (*store.IdempotencyRecord, error) {
+	start := time.Now()
+	record, err := s.next.GetIdempotencyRecord(ctx, userID, key, now)
+	s.observe("GetIdempotencyRecord", start, err, 1)
+	return record, err
+}
+
+func (s *Store) DeleteExpiredIdempotencyRecords(ctx context.Context, now time.Time) (int64, error) {
+	start := time.Now()
+	deleted, err := s.next.DeleteExpiredIdempotencyRecords(ctx, now)
+	s.observe("DeleteExpiredIdempotencyRecords", start, err, int(deleted))
+	return deleted, err
+}
+
+func (s *Store) Get(ctx context.Context, id string) (*entity.Order, error) {
+	start := time.Now()
+	order, err := s.next.Get(ctx, id)
+	s.observe("Get", start, err, 1)
+	return order, err
+}
+
+func (s *Store) GetIncludingDeleted(ctx context.Context, id string) (*entity.Order, error) {
+	start := time.Now()
+	order, err := s.next.GetIncludingDeleted(ctx, id)
+	s.observe("GetIncludingDeleted", start, err, 1)
+	return order, err
+}
+
+func (s *Store) List(ctx context.Context, query store.ListQuery) (*store.ListPage, error) {
+	start := time.Now()
+	page, err := s.next.List(ctx, query)
+	rows := 0
+	if page != nil {
+		rows = len(page.Orders)
+	}
+	s.observe("List", start, err, rows)
+	return page, err
+}
+
+func (s *Store) UpdateStatus(ctx context.Context, id string, from, to entity.OrderStatus) (*entity.Order, error) {
+	start := time.Now()
+	order, err := s.next.UpdateStatus(ctx, id, from, to)
+	s.observe("UpdateStatus", start, err, 1)
+	if err == nil {
+		s.orders.WithLabelValues(string(to)).Inc()
+	}
+	return order, err
+}
+
+func (s *Store) Cancel(
+	ctx context.Context,
+	id string,
+	from entity.OrderStatus,
+	cancellation entity.OrderCancellation,
+) (*entity.Order, error) {
+	start := time.Now()
+	order, err := s.next.Cancel(ctx, id, from, cancellation)
+	s.observe("Cancel", start, err, 1)
+	if err == nil {
+		s.orders.WithLabelValues(string(entity.OrderStatusCancelled)).Inc()
+	}
+	return order, err
+}
+
+func (s *Store) Delete(ctx context.Context, id string, deletedAt time.Time) (*entity.Order, error) {
+	start := time.Now()
+	order, err := s.next.Delete(ctx, id, deletedAt)
+	s.observe("Delete", start, err, 1)
+	return order, err
+}
+
+func (s *Store) Restore(ctx context.Context, id string) (*entity.Order, error) {
+	start := time.Now()
+	order, err := s.next.Restore(ctx, id)
+	s.observe("Restore", start, err, 1)
+	return order, err
+}
+
+func (s *Store) PurgeDeletedOrders(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	start := time.Now()
+	purged, err := s.next.PurgeDeletedOrders(ctx, deletedBefore)
+	s.observe("PurgeDeletedOrders", start, err, int(purged))
+	return purged, err
+}
+
+func (s *Store) ClaimOrderEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entity.OrderEvent, error) {
+	start := time.Now()
+	events, err := s.next.ClaimOrderEvents(ctx, now, lease, limit)
+	s.observe("ClaimOrderEvents", start, err, len(events))
+	return events, err
+}
+
+func (s *Store) MarkOrderEventPublished(ctx context.Context, id int64, publishedAt time.Time) error {
+	start := time.Now()
+	err := s.next.MarkOrderEventPublished(ctx, id, publishedAt)
+	s.observe("MarkOrderEventPublished", start, err, 1)
+	return err
+}
+
+func (s *Store) MarkOrderEventFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	start := time.Now()
+	err := s.next.MarkOrderEventFailed(ctx, id, nextAttemptAt, lastError)
+	s.observe("MarkOrderEventFailed", start, err, 1)
+	return err
+}
+
+func (s *Store) PurgePublishedOrderEvents(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	start := time.Now()
+	purged, err := s.next.PurgePublishedOrderEvents(ctx, publishedBefore)
+	s.observe("PurgePublishedOrderEvents", start, err, int(purged))
+	return purged, err
+}
+
+func (s *Store) ListOrderEvents(ctx context.Context, afterID int64, limit int) ([]entity.OrderEvent, error) {
+	start := time.Now()
+	events, err := s.next.ListOrderEvents(ctx, afterID, limit)
+	s.observe("ListOrderEvents", start, err, len(events))
+	return events, err
+}
+
+func (s *Store) SubscribeOrderEvents(ctx context.Context) (*store.OrderEventSubscription, error) {
+	start := time.Now()
+	subscription, err := s.next.SubscribeOrderEvents(ctx)
+	s.observe("SubscribeOrderEvents", start, err, 0)
+	return subscription, err
+}
+
+func (s *Store) CreateWebhookSubscription(ctx context.Context, subscription *entity.WebhookSubscription) error {
+	start := time.Now()
+	err := s.next.CreateWebhookSubscription(ctx, subscription)
+	s.observe("CreateWebhookSubscription", start, err, 1)
+	return err
+}
+
+func (s *Store) ListWebhookSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error) {
+	start := time.Now()
+	subscriptions, err := s.next.ListWebhookSubscriptions(ctx)
+	s.observe("ListWebhookSubscriptions", start, err, len(subscriptions))
+	return subscriptions, err
+}
+
+func (s *Store) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	start := time.Now()
+	err := s.next.DeleteWebhookSubscription(ctx, id)
+	s.observe("DeleteWebhookSubscription", start, err, 1)
+	return err
+}
+
+func (s *Store) EnqueueWebhookDeliveries(ctx context.Context, event entity.OrderEvent, now time.Time) (int64, error) {
+	start := time.Now()
+	enqueued, err := s.next.EnqueueWebhookDeliveries(ctx, event, now)
+	s.observe("EnqueueWebhookDeliveries", start, err, int(enqueued))
+	return enqueued, err
+}
+
+func (s *Store) ClaimWebhookDeliveries(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]store.ClaimedWebhookDelivery, error) {
+	start := time.Now()
+	deliveries, err := s.next.ClaimWebhookDeliveries(ctx, now, lease, limit)
+	s.observe("ClaimWebhookDeliveries", start, err, len(deliveries))
+	return deliveries, err
+}
+
+func (s *Store) MarkWebhookDelivered(ctx context.Context, id int64, deliveredAt time.Time) error {
+	start := time.Now()
+	err := s.next.MarkWebhookDelivered(ctx, id, deliveredAt)
+	s.observe("MarkWebhookDelivered", start, err, 1)
+	return err
+}
+
+func (s *Store) MarkWebhookDeliveryFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	start := time.Now()
+	err := s.next.MarkWebhookDeliveryFailed(ctx, id, nextAttemptAt, lastError)
+	s.observe("MarkWebhookDeliveryFailed", start, err, 1)
+	return err
+}
+
+func (s *Store) MarkWebhookDeliveryDead(ctx context.Context, id int64, lastError string) error {
+	start := time.Now()
+	err := s.next.MarkWebhookDeliveryDead(ctx, id, lastError)
+	s.observe("MarkWebhookDeliveryDead", start, err, 1)
+	return err
+}
+
+func (s *Store) ListWebhookDeliveries(ctx context.Context, query store.WebhookDeliveryQuery) (*store.WebhookDeliveryPage, error) {
+	start := time.Now()
+	page, err := s.next.ListWebhookDeliveries(ctx, query)
+	rows := 0
+	if page != nil {
+		rows = len(page.Deliveries)
+	}
+	s.observe("ListWebhookDeliveries", start, err, rows)
+	return page, err
+}
+
+func (s *Store) ReplayWebhookDelivery(ctx context.Context, id int64, now time.Time) (*entity.WebhookDelivery, error) {
+	start := time.Now()
+	delivery, err := s.next.ReplayWebhookDelivery(ctx, id, now)
+	s.observe("ReplayWebhookDelivery", start, err, 1)
+	return delivery, err
+}
+
+func (s *Store) Ping(ctx context.Context) error {
+	start := time.Now()
+	err := s.next.Ping(ctx)
+	s.observe("Ping", start, err, 0)
+	return err
+}
+
+func (s *Store) Close() error {
+	return s.next.Close()
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	s := NewStore(&store.MockOrderStore{
+		GetFunc: func(ctx context.Context, id string) (*entity.Order, error) {
+			switch id {
+			case "missing":
+				return nil, store.ErrOrderNotFound
+			case "broken":
+				return nil, errors.New("connection reset")
+			}
+			return &entity.Order{ID: id}, nil
+		},
+		ListFunc: func(ctx context.Context, query store.ListQuery) (*store.ListPage, error) {
+			return &store.ListPage{Orders: []*entity.Order{{ID: "1"}, {ID: "2"}, {ID: "3"}}}, nil
+		},
+		UpdateStatusFunc: func(ctx context.Context, id string, from, to entity.OrderStatus) (*entity.Order, error) {
+			return &entity.Order{ID: id, Status: to}, nil
+		},
+		CancelFunc: func(
+			ctx context.Context,
+			id string,
+			from entity.OrderStatus,
+			cancellation entity.OrderCancellation,
+		) (*entity.Order, error) {
+			return nil, store.ErrOrderStatusConflict
+		},
+	}, registry)
+	ctx := context.Background()
+
+	for _, amount := range []int64{1999, 501} {
+		require.NoError(t, s.Create(ctx, &entity.Order{
+			ID:     "order",
+			Status: entity.OrderStatusNew,
+			Amount: entity.NewMoney(amount, "EUR"),
+		}))
+	}
+	require.NoError(t, s.CreateWithIdempotencyKey(ctx, &entity.Order{
+		ID:     "order",
+		Status: entity.OrderStatusNew,
+		Amount: entity.NewMoney(100, "JPY"),
+	}, store.IdempotencyRecord{}))
+	for _, id := range []string{"found", "missing", "broken", "broken"} {
+		_, _ = s.Get(ctx, id)
+	}
+	_, err := s.List(ctx, store.ListQuery{})
+	require.NoError(t, err)
+	_, err = s.UpdateStatus(ctx, "order", entity.OrderStatusNew, entity.OrderStatusInProgress)
+	require.NoError(t, err)
+	_, err = s.Cancel(ctx, "order", entity.OrderStatusInProgress, entity.OrderCancellation{})
+	require.ErrorIs(t, err, store.ErrOrderStatusConflict, "Errors should be passed through")
+
+	want := `
+# HELP order_orders_created_amount_total Sum of the amounts of created orders in major units, by currency.
+# TYPE order_orders_created_amount_total counter
+order_orders_created_amount_total{currency="EUR"} 25
+order_orders_created_amount_total{currency="JPY"} 100
+# HELP order_orders_total Orders that reached a status, created orders are counted as NEW.
+# TYPE order_orders_total counter
+order_orders_total{status="IN_PROGRESS"} 1
+order_orders_total{status="NEW"} 3
+# HELP order_store_errors_total Failed order store calls, by method. Expected outcomes such as not found are not counted.
+# TYPE order_store_errors_total counter
+order_store_errors_total{method="Get"} 2
+# HELP order_store_rows_total Rows returned or changed by order store calls, by method.
+# TYPE order_store_rows_total counter
+order_store_rows_total{method="Create"} 2
+order_store_rows_total{method="CreateWithIdempotencyKey"} 1
+order_store_rows_total{method="Get"} 1
+order_store_rows_total{method="List"} 3
+order_store_rows_total{method="UpdateStatus"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(want),
+		"order_orders_created_amount_total", "order_orders_total", "order_store_errors_total", "order_store_rows_total"))
+
+	count, err := testutil.GatherAndCount(registry, "order_store_query_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 6, count, "Duration should be recorded for every method called")
+}
+
+func TestStore_Delegates(t *testing.T) {
+	var closed, pinged bool
+	s := NewStore(&store.MockOrderStore{
+		PingFunc: func(ctx context.Context) error {
+			pinged = true
+			return nil
+		},
+		CloseFunc: func() error {
+			closed = true
+			return nil
+		},
+		ClaimOrderEventsFunc: func(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entity.OrderEvent, error) {
+			return make([]entity.OrderEvent, limit), nil
+		},
+	}, prometheus.NewRegistry())
+
+	events, err := s.ClaimOrderEvents(context.Background(), time.Now(), time.Minute, 5)
+	require.NoError(t, err)
+	assert.Len(t, events, 5)
+	require.NoError(t, s.Ping(context.Background()))
+	require.NoError(t, s.Close())
+	assert.True(t, pinged)
+	assert.True(t, closed)
+}
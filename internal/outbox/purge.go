@@ -0,0 +1,33 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type publishedEventPurger interface {
+	PurgePublishedOrderEvents(ctx context.Context, publishedBefore time.Time) (int64, error)
+}
+
+// PurgePublishedEvents removes the events published more than retention ago every interval until ctx is done.
+func PurgePublishedEvents(ctx context.Context, store publishedEventPurger, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := store.PurgePublishedOrderEvents(ctx, time.Now().UTC().Add(-retention))
+			if err != nil {
+				slog.Error("Failed to purge published order events", "error", err)
+				continue
+			}
+			if purged > 0 {
+				slog.Info("Purged published order events", "purged", purged)
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Package outbox delivers the order events recorded by the store to other systems.
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/demo/order/internal/entity"
+)
+
+// EventPublisher delivers order events, e.g. to a message broker.
+type EventPublisher interface {
+	// Publish delivers the event. Delivery is at least once, so the same event
+	// may be published again after a failure or a relay restart.
+	Publish(ctx context.Context, event entity.OrderEvent) error
+}
+
+// EventStore is the part of store.OrderStore the relay works with.
+type EventStore interface {
+	ClaimOrderEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entity.OrderEvent, error)
+	MarkOrderEventPublished(ctx context.Context, id int64, publishedAt time.Time) error
+	MarkOrderEventFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+}
+
+type RelayConfig struct {
+	// BatchSize is the maximum number of events claimed at once.
+	BatchSize int
+	// PollInterval is the pause between polls when there are no pending events.
+	PollInterval time.Duration
+	// Lease is how long claimed events are hidden from other relays. It must
+	// be longer than publishing a batch takes, or events are published twice.
+	Lease time.Duration
+	// MinBackoff and MaxBackoff bound the exponential delay between retries of a failed event.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+func DefaultRelayConfig() RelayConfig {
+	return RelayConfig{
+		BatchSize:    100,
+		PollInterval: time.Second,
+		Lease:        time.Minute,
+		MinBackoff:   time.Second,
+		MaxBackoff:   5 * time.Minute,
+	}
+}
+
+// Relay publishes pending events from the outbox. Events of an order are
+// published in order: a failed event is retried, and blocks the later events
+// of its order, until it is published.
+type Relay struct {
+	store     EventStore
+	publisher EventPublisher
+	config    RelayConfig
+}
+
+func NewRelay(store EventStore, publisher EventPublisher, config RelayConfig) *Relay {
+	return &Relay{store: store, publisher: publisher, config: config}
+}
+
+// Run relays events until ctx is done.
+func (r *Relay) Run(ctx context.Context) {
+	for {
+		relayed, err := r.RelayBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("Failed to relay order events", "error", err)
+		}
+		// A full batch means more events are probably waiting.
+		if err == nil && relayed == r.config.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.config.PollInterval):
+		}
+	}
+}
+
+// RelayBatch claims one batch of due events and publishes them. It returns the number of claimed events.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	events, err := r.store.ClaimOrderEvents(ctx, time.Now().UTC(), r.config.Lease, r.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	// Claimed events belong to different orders, so a failure does not hold up the rest of the batch.
+	for _, event := range events {
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			nextAttemptAt := time.Now().UTC().Add(r.backoff(event.Attempts))
+			slog.Warn("Failed to publish order event",
+				"event_id", event.ID, "event_type", event.Type, "order_id", event.OrderID, "attempt", event.Attempts+1, "error", err)
+			if err := r.store.MarkOrderEventFailed(ctx, event.ID, nextAttemptAt, err.Error()); err != nil {
+				slog.Error("Failed to record failure of order event", "event_id", event.ID, "error", err)
+			}
+			continue
+		}
+		// If this fails the event is published again once its lease expires.
+		if err := r.store.MarkOrderEventPublished(ctx, event.ID, time.Now().UTC()); err != nil {
+			slog.Error("Failed to mark order event as published", "event_id", event.ID, "error", err)
+		}
+	}
+	return len(events), nil
+}
+
+// backoff returns the delay before the next attempt after attempts earlier failures.
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.config.MinBackoff
+	for i := 0; i < attempts && delay < r.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, r.config.MaxBackoff)
+}
+
+// LogPublisher logs events instead of delivering them anywhere, for deployments
+// without a message broker.
+type LogPublisher struct{}
+
+func (LogPublisher) Publish(ctx context.Context, event entity.OrderEvent) error {
+	slog.InfoContext(ctx, "Order event",
+		"event_id", event.ID, "event_type", event.Type, "order_id", event.OrderID, "payload", string(event.Payload))
+	return nil
+}
+
+// Publishers publishes every event to each of its publishers in turn. If one
+// fails, the event is retried with all of them, so the publishers that
+// succeeded get it again; delivery is at least once anyway. Without
+// publishers, events are marked published right away.
+type Publishers []EventPublisher
+
+func (p Publishers) Publish(ctx context.Context, event entity.OrderEvent) error {
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
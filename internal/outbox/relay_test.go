@@ -0,0 +1,172 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePublisher struct {
+	mu        sync.Mutex
+	published []entity.OrderEvent
+	// failures is the number of calls to fail before publishing succeeds.
+	failures int
+}
+
+func (p *fakePublisher) Publish(_ context.Context, event entity.OrderEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, event)
+	return nil
+}
+
+func newTestOrder(id string) *entity.Order {
+	amount := entity.NewMoney(1000, entity.DefaultCurrency)
+	return &entity.Order{
+		ID:        id,
+		UserID:    "user-123",
+		Items:     []entity.OrderItem{{SKU: "SKU-1", Name: "Widget", Quantity: 1, UnitPrice: amount}},
+		Amount:    amount,
+		Status:    entity.OrderStatusNew,
+		CreatedAt: time.Now(),
+	}
+}
+
+func testRelayConfig() RelayConfig {
+	config := DefaultRelayConfig()
+	config.MinBackoff = time.Hour
+	config.MaxBackoff = 4 * time.Hour
+	return config
+}
+
+func TestRelay_PublishesEventsInOrderPerOrder(t *testing.T) {
+	ctx := context.Background()
+	orderStore := store.NewMemoryStore()
+	require.NoError(t, orderStore.Create(ctx, newTestOrder("order-1")))
+	require.NoError(t, orderStore.Create(ctx, newTestOrder("order-2")))
+	_, err := orderStore.UpdateStatus(ctx, "order-1", entity.OrderStatusNew, entity.OrderStatusInProgress)
+	require.NoError(t, err)
+
+	publisher := &fakePublisher{}
+	relay := NewRelay(orderStore, publisher, testRelayConfig())
+
+	// Only the oldest event of each order is due at a time.
+	relayed, err := relay.RelayBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, relayed)
+	relayed, err = relay.RelayBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, relayed)
+	relayed, err = relay.RelayBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, relayed)
+
+	require.Len(t, publisher.published, 3)
+	assert.Equal(t, "order-1", publisher.published[0].OrderID)
+	assert.Equal(t, entity.OrderEventCreated, publisher.published[0].Type)
+	assert.Equal(t, "order-2", publisher.published[1].OrderID)
+	assert.Equal(t, entity.OrderEventCreated, publisher.published[1].Type)
+	assert.Equal(t, "order-1", publisher.published[2].OrderID)
+	assert.Equal(t, entity.OrderEventStatusChanged, publisher.published[2].Type)
+	assert.JSONEq(t, `"NEW"`, jsonField(t, publisher.published[2].Payload, "previous_status"))
+}
+
+func TestRelay_RetriesFailedEventBeforeLaterOnes(t *testing.T) {
+	ctx := context.Background()
+	orderStore := store.NewMemoryStore()
+	require.NoError(t, orderStore.Create(ctx, newTestOrder("order-1")))
+	_, err := orderStore.UpdateStatus(ctx, "order-1", entity.OrderStatusNew, entity.OrderStatusInProgress)
+	require.NoError(t, err)
+
+	publisher := &fakePublisher{failures: 1}
+	relay := NewRelay(orderStore, publisher, testRelayConfig())
+
+	relayed, err := relay.RelayBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, relayed)
+	assert.Empty(t, publisher.published)
+
+	// The failed event is backed off and the status change waits behind it.
+	relayed, err = relay.RelayBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, relayed)
+
+	events, err := orderStore.ClaimOrderEvents(ctx, time.Now().Add(2*time.Hour), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, entity.OrderEventCreated, events[0].Type)
+	assert.Equal(t, 1, events[0].Attempts)
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	relay := NewRelay(nil, nil, RelayConfig{MinBackoff: time.Second, MaxBackoff: 10 * time.Second})
+
+	assert.Equal(t, time.Second, relay.backoff(0))
+	assert.Equal(t, 2*time.Second, relay.backoff(1))
+	assert.Equal(t, 8*time.Second, relay.backoff(3))
+	assert.Equal(t, 10*time.Second, relay.backoff(4))
+	assert.Equal(t, 10*time.Second, relay.backoff(100))
+}
+
+func TestRelay_RunStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	orderStore := store.NewMemoryStore()
+	require.NoError(t, orderStore.Create(ctx, newTestOrder("order-1")))
+
+	publisher := &fakePublisher{}
+	config := testRelayConfig()
+	config.PollInterval = time.Millisecond
+	relay := NewRelay(orderStore, publisher, config)
+
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		publisher.mu.Lock()
+		defer publisher.mu.Unlock()
+		return len(publisher.published) == 1
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run should return after the context is cancelled")
+	}
+}
+
+func TestPublishers(t *testing.T) {
+	ctx := context.Background()
+	event := entity.OrderEvent{ID: 1, OrderID: "order-1", Type: entity.OrderEventCreated}
+	first, second := &fakePublisher{}, &fakePublisher{failures: 1}
+	publishers := Publishers{first, second}
+
+	require.Error(t, publishers.Publish(ctx, event), "A failing publisher should fail the event")
+	require.NoError(t, publishers.Publish(ctx, event))
+
+	assert.Len(t, first.published, 2, "The retry should publish to every publisher again")
+	assert.Len(t, second.published, 1)
+	assert.NoError(t, Publishers(nil).Publish(ctx, event))
+}
+
+func jsonField(t *testing.T, payload []byte, field string) string {
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(payload, &fields))
+	return string(fields[field])
+}
@@ -0,0 +1,28 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Backend stores the token buckets. Buckets are stored as the time they are
+// full again, which is all a bucket needs: a call takes a token if that time
+// moved forward by Limit.interval stays within Limit.Period from now (GCRA).
+type Backend interface {
+	// Take takes a token from the bucket of key. When the bucket is empty, it
+	// returns false and the time until a token is available.
+	Take(ctx context.Context, key string, limit Limit) (ok bool, retryAfter time.Duration, err error)
+}
+
+// take takes a token from a bucket full at fullAt and returns when the bucket
+// is full again afterwards.
+func take(fullAt, now time.Time, limit Limit) (newFullAt time.Time, ok bool, retryAfter time.Duration) {
+	if fullAt.Before(now) {
+		fullAt = now
+	}
+	newFullAt = fullAt.Add(limit.interval())
+	if over := newFullAt.Sub(now) - limit.Period; over > 0 {
+		return fullAt, false, over
+	}
+	return newFullAt, true, 0
+}
@@ -0,0 +1,168 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/demo/order/internal/auth"
+	"github.com/demo/order/internal/logging"
+)
+
+// RetryAfterHeader tells callers over the limit how many seconds to wait.
+const RetryAfterHeader = "Retry-After"
+
+// Interceptor limits the RPCs of each caller, which is the authenticated user
+// or the client IP without authentication, and of all callers together. Calls
+// over a limit fail with CodeResourceExhausted. It must run after
+// auth.Interceptor to see the authenticated user.
+type Interceptor struct {
+	backend Backend
+	config  Config
+}
+
+var _ connect.Interceptor = (*Interceptor)(nil)
+
+func NewInterceptor(backend Backend, config Config) *Interceptor {
+	return &Interceptor{backend: backend, config: config}
+}
+
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		if err := i.limit(ctx, req.Spec().Procedure, req.Peer(), req.Header()); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.limit(ctx, conn.Spec().Procedure, conn.Peer(), conn.RequestHeader()); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+// limit takes a token from the bucket of the caller and then from the global
+// bucket of the procedure, so that rejected callers do not use up the global limit.
+func (i *Interceptor) limit(ctx context.Context, procedure string, peer connect.Peer, header http.Header) error {
+	method := procedure[strings.LastIndex(procedure, "/")+1:]
+
+	limit, ok := i.config.PerRPC[method]
+	if !ok {
+		limit = i.config.Default
+	}
+	if limit.Count > 0 {
+		if err := takeToken(ctx, i.backend, procedure+" "+i.caller(ctx, peer, header), method, limit); err != nil {
+			return err
+		}
+	}
+	if limit, ok := i.config.Global[method]; ok {
+		return takeToken(ctx, i.backend, procedure+" global", method, limit)
+	}
+	return nil
+}
+
+// takeToken returns CodeResourceExhausted if the bucket of key, described by what, is empty.
+// Calls are allowed when the backend fails, so that its outage does not fail every RPC.
+func takeToken(ctx context.Context, backend Backend, key, what string, limit Limit) error {
+	ok, retryAfter, err := backend.Take(ctx, key, limit)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to check rate limit", "key", key, "error", err)
+		return nil
+	}
+	if ok {
+		return nil
+	}
+	connectErr := connect.NewError(connect.CodeResourceExhausted,
+		fmt.Errorf("rate limit of %s exceeded, retry in %s", what, retryAfter.Round(time.Millisecond)))
+	// Retry-After has a resolution of seconds, rounding down would make callers retry too early.
+	seconds := max(int(math.Ceil(retryAfter.Seconds())), 1)
+	connectErr.Meta().Set(RetryAfterHeader, strconv.Itoa(seconds))
+	return connectErr
+}
+
+// caller identifies the caller by the authenticated user or the client IP.
+func (i *Interceptor) caller(ctx context.Context, peer connect.Peer, header http.Header) string {
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		return "user:" + principal.Subject
+	}
+	return "ip:" + clientIP(peer, header, i.config.TrustForwardedFor)
+}
+
+// clientIP returns the address of the client, which is the first address of
+// X-Forwarded-For if trustForwardedFor is set.
+func clientIP(peer connect.Peer, header http.Header, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		if first, _, _ := strings.Cut(header.Get("X-Forwarded-For"), ","); strings.TrimSpace(first) != "" {
+			return strings.TrimSpace(first)
+		}
+	}
+	host, _, err := net.SplitHostPort(peer.Addr)
+	if err != nil {
+		return peer.Addr
+	}
+	return host
+}
+
+// IPInterceptor limits the RPCs of each client IP to Config.PerIP, across all
+// RPCs. It must run before auth.Interceptor, so that calls without a valid
+// token, and the JWKS refreshes unknown key IDs trigger, are limited too.
+type IPInterceptor struct {
+	backend Backend
+	config  Config
+}
+
+var _ connect.Interceptor = (*IPInterceptor)(nil)
+
+func NewIPInterceptor(backend Backend, config Config) *IPInterceptor {
+	return &IPInterceptor{backend: backend, config: config}
+}
+
+func (i *IPInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		if err := i.limit(ctx, req.Peer(), req.Header()); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i *IPInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *IPInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.limit(ctx, conn.Peer(), conn.RequestHeader()); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+func (i *IPInterceptor) limit(ctx context.Context, peer connect.Peer, header http.Header) error {
+	if i.config.PerIP.Count == 0 {
+		return nil
+	}
+	ip := clientIP(peer, header, i.config.TrustForwardedFor)
+	return takeToken(ctx, i.backend, "ip:"+ip, "client "+ip, i.config.PerIP)
+}
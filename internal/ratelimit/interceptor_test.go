@@ -0,0 +1,239 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/contracts/gen/go/order/v1/orderv1connect"
+	"github.com/demo/order/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderService struct {
+	orderv1connect.UnimplementedOrderServiceHandler
+}
+
+func (orderService) GetOrder(
+	context.Context,
+	*connect.Request[orderv1.GetOrderRequest],
+) (*connect.Response[orderv1.GetOrderResponse], error) {
+	return connect.NewResponse(&orderv1.GetOrderResponse{}), nil
+}
+
+func (orderService) CreateOrder(
+	context.Context,
+	*connect.Request[orderv1.CreateOrderRequest],
+) (*connect.Response[orderv1.CreateOrderResponse], error) {
+	return connect.NewResponse(&orderv1.CreateOrderResponse{}), nil
+}
+
+func (orderService) WatchOrders(
+	context.Context,
+	*connect.Request[orderv1.WatchOrdersRequest],
+	*connect.ServerStream[orderv1.WatchOrdersResponse],
+) error {
+	return nil
+}
+
+// userHeader authenticates the test calls as the user it contains.
+const userHeader = "X-Test-User"
+
+func authenticate() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if user := req.Header().Get(userHeader); user != "" {
+				ctx = auth.WithPrincipal(ctx, &auth.Principal{Subject: user})
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// failingBackend fails every call.
+type failingBackend struct{}
+
+func (failingBackend) Take(context.Context, string, Limit) (bool, time.Duration, error) {
+	return false, 0, errors.New("backend is down")
+}
+
+func newClient(t *testing.T, backend Backend, config Config) orderv1connect.OrderServiceClient {
+	t.Helper()
+	path, handler := orderv1connect.NewOrderServiceHandler(orderService{},
+		connect.WithInterceptors(authenticate(), NewInterceptor(backend, config)))
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return orderv1connect.NewOrderServiceClient(server.Client(), server.URL)
+}
+
+func getOrder(client orderv1connect.OrderServiceClient, header http.Header) error {
+	req := connect.NewRequest(&orderv1.GetOrderRequest{Id: "1"})
+	for name, values := range header {
+		req.Header()[name] = values
+	}
+	_, err := client.GetOrder(context.Background(), req)
+	return err
+}
+
+func TestInterceptor(t *testing.T) {
+	perSecond := func(n int) Limit { return Limit{Count: n, Period: time.Second} }
+	user := func(name string) http.Header { return http.Header{userHeader: {name}} }
+
+	testCases := []struct {
+		name   string
+		config Config
+		// calls are made in order, want is whether each is allowed.
+		calls []http.Header
+		want  []bool
+	}{
+		{
+			name:   "limits each user",
+			config: Config{Default: perSecond(2)},
+			calls:  []http.Header{user("a"), user("a"), user("a"), user("b")},
+			want:   []bool{true, true, false, true},
+		},
+		{
+			name:   "limits unauthenticated callers by IP",
+			config: Config{Default: perSecond(1)},
+			calls:  []http.Header{nil, nil, user("a")},
+			want:   []bool{true, false, true},
+		},
+		{
+			name:   "ignores X-Forwarded-For by default",
+			config: Config{Default: perSecond(1)},
+			calls:  []http.Header{{"X-Forwarded-For": {"10.0.0.1"}}, {"X-Forwarded-For": {"10.0.0.2"}}},
+			want:   []bool{true, false},
+		},
+		{
+			name:   "trusts X-Forwarded-For when configured",
+			config: Config{Default: perSecond(1), TrustForwardedFor: true},
+			calls: []http.Header{
+				{"X-Forwarded-For": {"10.0.0.1, 192.168.0.1"}},
+				{"X-Forwarded-For": {"10.0.0.2, 192.168.0.1"}},
+				{"X-Forwarded-For": {"10.0.0.1"}},
+			},
+			want: []bool{true, true, false},
+		},
+		{
+			name:   "overrides the default per RPC",
+			config: Config{Default: perSecond(1), PerRPC: map[string]Limit{"GetOrder": perSecond(3)}},
+			calls:  []http.Header{user("a"), user("a"), user("a"), user("a")},
+			want:   []bool{true, true, true, false},
+		},
+		{
+			name:   "does not limit without a default",
+			config: Config{},
+			calls:  []http.Header{user("a"), user("a"), user("a")},
+			want:   []bool{true, true, true},
+		},
+		{
+			name:   "limits all callers together",
+			config: Config{Global: map[string]Limit{"GetOrder": perSecond(2)}},
+			calls:  []http.Header{user("a"), user("b"), user("c")},
+			want:   []bool{true, true, false},
+		},
+		{
+			name:   "rejected callers do not use up the global limit",
+			config: Config{Default: perSecond(1), Global: map[string]Limit{"GetOrder": perSecond(2)}},
+			calls:  []http.Header{user("a"), user("a"), user("a"), user("b")},
+			want:   []bool{true, false, false, true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Given
+			client := newClient(t, NewMemoryBackend(), tc.config)
+
+			// When
+			got := make([]bool, len(tc.calls))
+			for i, header := range tc.calls {
+				err := getOrder(client, header)
+				if err != nil {
+					require.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err), err)
+				}
+				got[i] = err == nil
+			}
+
+			// Then
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestInterceptorRejection(t *testing.T) {
+	client := newClient(t, NewMemoryBackend(), Config{Default: Limit{Count: 1, Period: time.Minute}})
+	require.NoError(t, getOrder(client, nil))
+
+	err := getOrder(client, nil)
+	var connectErr *connect.Error
+	require.ErrorAs(t, err, &connectErr)
+	assert.Equal(t, connect.CodeResourceExhausted, connectErr.Code())
+	assert.Contains(t, connectErr.Message(), "rate limit of GetOrder exceeded, retry in ")
+	assert.Equal(t, "60", connectErr.Meta().Get(RetryAfterHeader), "Retry-After should be rounded up to seconds")
+
+	// Other RPCs have their own buckets.
+	_, err = client.CreateOrder(context.Background(), connect.NewRequest(&orderv1.CreateOrderRequest{}))
+	require.NoError(t, err)
+
+	t.Run("streaming", func(t *testing.T) {
+		stream, err := client.WatchOrders(context.Background(), connect.NewRequest(&orderv1.WatchOrdersRequest{}))
+		require.NoError(t, err)
+		require.False(t, stream.Receive())
+		require.NoError(t, stream.Err())
+		require.NoError(t, stream.Close())
+
+		stream, err = client.WatchOrders(context.Background(), connect.NewRequest(&orderv1.WatchOrdersRequest{}))
+		require.NoError(t, err)
+		require.False(t, stream.Receive())
+		assert.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(stream.Err()))
+	})
+}
+
+func TestIPInterceptor(t *testing.T) {
+	// Given a limit per IP before an interceptor rejecting calls without a user
+	path, handler := orderv1connect.NewOrderServiceHandler(orderService{}, connect.WithInterceptors(
+		NewIPInterceptor(NewMemoryBackend(), Config{PerIP: Limit{Count: 2, Period: time.Minute}}),
+		connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+			return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+				if req.Header().Get(userHeader) == "" {
+					return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("missing token"))
+				}
+				return next(ctx, req)
+			}
+		}),
+	))
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	client := orderv1connect.NewOrderServiceClient(server.Client(), server.URL)
+
+	// When unauthenticated calls and then an authenticated one are made from the same IP
+	codes := make([]connect.Code, 0, 3)
+	for _, header := range []http.Header{nil, nil, {userHeader: {"a"}}} {
+		codes = append(codes, connect.CodeOf(getOrder(client, header)))
+	}
+	_, err := client.CreateOrder(context.Background(), connect.NewRequest(&orderv1.CreateOrderRequest{}))
+
+	// Then the rejected calls use up the limit of the IP on every RPC
+	assert.Equal(t, []connect.Code{connect.CodeUnauthenticated, connect.CodeUnauthenticated, connect.CodeResourceExhausted}, codes)
+	assert.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+	assert.Contains(t, err.Error(), "rate limit of client 127.0.0.1 exceeded")
+}
+
+func TestInterceptorBackendFailure(t *testing.T) {
+	client := newClient(t, failingBackend{}, Config{Default: Limit{Count: 1, Period: time.Minute}})
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, getOrder(client, nil), "Calls should be allowed when the backend fails")
+	}
+}
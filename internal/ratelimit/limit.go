@@ -0,0 +1,90 @@
+// Package ratelimit limits the rate of RPCs with token buckets, per caller and
+// for all callers together.
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Count calls per Period, in bursts of up to Count calls: the
+// bucket holds Count tokens and gets one back every Period/Count.
+type Limit struct {
+	Count  int
+	Period time.Duration
+}
+
+// interval is the time it takes to get a token back.
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Count)
+}
+
+func (l Limit) String() string {
+	return strconv.Itoa(l.Count) + "/" + l.Period.String()
+}
+
+// ParseLimit parses "<count>/<period>", where the period is "s", "m", "h" or
+// a duration, e.g. "10/s" or "100/15m".
+func ParseLimit(s string) (Limit, error) {
+	count, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid limit %q, expected <count>/<period> such as 10/s", s)
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid limit %q, the count must be a positive integer", s)
+	}
+	if period == "s" || period == "m" || period == "h" {
+		period = "1" + period
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid limit %q, the period must be s, m, h or a positive duration", s)
+	}
+	if d < time.Duration(n) {
+		return Limit{}, fmt.Errorf("invalid limit %q, the period is too short for the count", s)
+	}
+	return Limit{Count: n, Period: d}, nil
+}
+
+// ParseLimits parses comma-separated "<method>=<limit>" pairs, e.g.
+// "CreateOrder=10/s,ListOrders=100/s". An empty string has no limits.
+func ParseLimits(s string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	if strings.TrimSpace(s) == "" {
+		return limits, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		method, limit, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || method == "" {
+			return nil, fmt.Errorf("invalid limit %q, expected <method>=<limit> such as CreateOrder=10/s", pair)
+		}
+		if _, ok := limits[method]; ok {
+			return nil, fmt.Errorf("duplicate limit of %s", method)
+		}
+		l, err := ParseLimit(limit)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", method, err)
+		}
+		limits[method] = l
+	}
+	return limits, nil
+}
+
+// Config configures Interceptor.
+type Config struct {
+	// Default limits each caller on every RPC not in PerRPC, a zero Limit does not limit.
+	Default Limit
+	// PerRPC overrides Default by method name, e.g. "CreateOrder".
+	PerRPC map[string]Limit
+	// Global limits all callers together by method name.
+	Global map[string]Limit
+	// PerIP limits each client IP on all RPCs together, see IPInterceptor. A zero Limit does not limit.
+	PerIP Limit
+	// TrustForwardedFor identifies unauthenticated callers and client IPs by the
+	// first address of the X-Forwarded-For header instead of the peer address. It must
+	// only be enabled behind a proxy that sets the header, since callers could forge it.
+	TrustForwardedFor bool
+}
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	testCases := []struct {
+		limit   string
+		want    Limit
+		wantErr string
+	}{
+		{limit: "10/s", want: Limit{Count: 10, Period: time.Second}},
+		{limit: "100/m", want: Limit{Count: 100, Period: time.Minute}},
+		{limit: "5/h", want: Limit{Count: 5, Period: time.Hour}},
+		{limit: "3/10s", want: Limit{Count: 3, Period: 10 * time.Second}},
+		{limit: "10", wantErr: `invalid limit "10", expected <count>/<period> such as 10/s`},
+		{limit: "0/s", wantErr: `invalid limit "0/s", the count must be a positive integer`},
+		{limit: "ten/s", wantErr: `invalid limit "ten/s", the count must be a positive integer`},
+		{limit: "10/d", wantErr: `invalid limit "10/d", the period must be s, m, h or a positive duration`},
+		{limit: "10/-1s", wantErr: `invalid limit "10/-1s", the period must be s, m, h or a positive duration`},
+		{limit: "10/5ns", wantErr: `invalid limit "10/5ns", the period is too short for the count`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.limit, func(t *testing.T) {
+			limit, err := ParseLimit(tc.limit)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, limit)
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits(" CreateOrder=10/s, ListOrders=100/m")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Limit{
+		"CreateOrder": {Count: 10, Period: time.Second},
+		"ListOrders":  {Count: 100, Period: time.Minute},
+	}, limits)
+
+	limits, err = ParseLimits("")
+	require.NoError(t, err)
+	assert.Empty(t, limits)
+
+	_, err = ParseLimits("CreateOrder")
+	assert.EqualError(t, err, `invalid limit "CreateOrder", expected <method>=<limit> such as CreateOrder=10/s`)
+	_, err = ParseLimits("CreateOrder=10/s,CreateOrder=5/s")
+	assert.EqualError(t, err, "duplicate limit of CreateOrder")
+	_, err = ParseLimits("CreateOrder=10")
+	assert.EqualError(t, err, `CreateOrder: invalid limit "10", expected <count>/<period> such as 10/s`)
+}
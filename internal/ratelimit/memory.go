@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// minSweepSize is the number of buckets from which full buckets are removed.
+const minSweepSize = 1024
+
+// MemoryBackend keeps the buckets in memory, so every instance of the service
+// limits its callers on its own.
+type MemoryBackend struct {
+	now func() time.Time
+
+	mu sync.Mutex
+	// fullAt maps the keys to the time their bucket is full again.
+	fullAt map[string]time.Time
+	// sweepSize is the number of buckets at which the full ones are removed.
+	sweepSize int
+}
+
+var _ Backend = (*MemoryBackend)(nil)
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{now: time.Now, fullAt: make(map[string]time.Time), sweepSize: minSweepSize}
+}
+
+func (b *MemoryBackend) Take(_ context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	now := b.now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	fullAt, ok, retryAfter := take(b.fullAt[key], now, limit)
+	b.fullAt[key] = fullAt
+	if len(b.fullAt) >= b.sweepSize {
+		b.sweep(now)
+	}
+	return ok, retryAfter, nil
+}
+
+// sweep removes the full buckets, which are the same as missing ones.
+func (b *MemoryBackend) sweep(now time.Time) {
+	for key, fullAt := range b.fullAt {
+		if !fullAt.After(now) {
+			delete(b.fullAt, key)
+		}
+	}
+	b.sweepSize = max(minSweepSize, 2*len(b.fullAt))
+}
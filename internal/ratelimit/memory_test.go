@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBackend(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	backend := NewMemoryBackend()
+	backend.now = func() time.Time { return now }
+	limit := Limit{Count: 2, Period: time.Second}
+	ctx := context.Background()
+
+	takeN := func(key string, n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			ok, _, err := backend.Take(ctx, key, limit)
+			require.NoError(t, err)
+			require.True(t, ok, "Take %d of %s should be allowed", i+1, key)
+		}
+	}
+
+	// A burst of Count calls is allowed.
+	takeN("a", 2)
+	ok, retryAfter, err := backend.Take(ctx, "a", limit)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, retryAfter, "A token should come back every Period/Count")
+
+	// Buckets are separate.
+	takeN("b", 2)
+
+	// A token comes back after the interval.
+	now = now.Add(200 * time.Millisecond)
+	ok, retryAfter, err = backend.Take(ctx, "a", limit)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 300*time.Millisecond, retryAfter)
+	now = now.Add(300 * time.Millisecond)
+	takeN("a", 1)
+
+	// An unused bucket fills up to Count tokens, not more.
+	now = now.Add(time.Hour)
+	takeN("a", 2)
+	ok, _, err = backend.Take(ctx, "a", limit)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestMemoryBackendSweep(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	backend := NewMemoryBackend()
+	backend.now = func() time.Time { return now }
+	limit := Limit{Count: 10, Period: time.Second}
+
+	for i := 0; i < minSweepSize-1; i++ {
+		_, _, err := backend.Take(context.Background(), strconv.Itoa(i), limit)
+		require.NoError(t, err)
+	}
+	now = now.Add(time.Second)
+	_, _, err := backend.Take(context.Background(), "last", limit)
+	require.NoError(t, err)
+
+	assert.Len(t, backend.fullAt, 1, "Full buckets should be removed")
+	assert.Contains(t, backend.fullAt, "last")
+}
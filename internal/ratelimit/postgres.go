@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// purgeInterval is how often PostgresBackend.Run deletes the full buckets.
+const purgeInterval = 5 * time.Minute
+
+// PostgresBackend keeps the buckets in the rate_limit_buckets table, so the
+// instances of the service sharing the database share the limits. The time of
+// the database is used, so the clocks of the instances do not matter.
+type PostgresBackend struct {
+	db *sql.DB
+}
+
+var _ Backend = (*PostgresBackend)(nil)
+
+func NewPostgresBackend(db *sql.DB) *PostgresBackend {
+	return &PostgresBackend{db: db}
+}
+
+func (b *PostgresBackend) Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	// The update is skipped when the bucket is empty, see take.
+	const query = `INSERT INTO rate_limit_buckets AS b (key, full_at)
+		VALUES ($1, now() + $2 * interval '1 microsecond')
+		ON CONFLICT (key) DO UPDATE SET full_at = GREATEST(b.full_at, now()) + $2 * interval '1 microsecond'
+		WHERE GREATEST(b.full_at, now()) + $2 * interval '1 microsecond' <= now() + $3 * interval '1 microsecond'
+		RETURNING full_at`
+	var fullAt time.Time
+	err := b.db.QueryRowContext(ctx, query, key, limit.interval().Microseconds(), limit.Period.Microseconds()).Scan(&fullAt)
+	if err == nil {
+		return true, 0, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return false, 0, fmt.Errorf("take rate limit token: %w", err)
+	}
+
+	const fullInQuery = `SELECT EXTRACT(EPOCH FROM full_at - now()) FROM rate_limit_buckets WHERE key = $1`
+	var fullIn float64
+	if err := b.db.QueryRowContext(ctx, fullInQuery, key).Scan(&fullIn); err != nil {
+		return false, 0, fmt.Errorf("get rate limit bucket: %w", err)
+	}
+	retryAfter := time.Duration(fullIn*float64(time.Second)) + limit.interval() - limit.Period
+	return false, max(retryAfter, 0), nil
+}
+
+// DeleteFull deletes the full buckets, which are the same as missing ones.
+func (b *PostgresBackend) DeleteFull(ctx context.Context) (int64, error) {
+	res, err := b.db.ExecContext(ctx, `DELETE FROM rate_limit_buckets WHERE full_at <= now()`)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Run deletes the full buckets every purgeInterval until ctx is done.
+func (b *PostgresBackend) Run(ctx context.Context) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := b.DeleteFull(ctx)
+			if err != nil {
+				slog.Error("Failed to delete full rate limit buckets", "error", err)
+				continue
+			}
+			if deleted > 0 {
+				slog.Debug("Deleted full rate limit buckets", "deleted", deleted)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/demo/order/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresBackend(t *testing.T) {
+	connStr := os.Getenv("TEST_DATABASE_URL")
+	if connStr == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	// The store applies the migrations creating rate_limit_buckets.
+	orderStore, err := store.NewPostgresStore(connStr, store.PostgresPool{})
+	require.NoError(t, err)
+	t.Cleanup(func() { orderStore.Close() })
+	backend := NewPostgresBackend(orderStore.DB())
+	ctx := context.Background()
+	key := "test " + uuid.NewString()
+	limit := Limit{Count: 2, Period: time.Minute}
+
+	for i := 0; i < 2; i++ {
+		ok, _, err := backend.Take(ctx, key, limit)
+		require.NoError(t, err)
+		require.True(t, ok, "Take %d should be allowed", i+1)
+	}
+	ok, retryAfter, err := backend.Take(ctx, key, limit)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.InDelta(t, 30*time.Second, retryAfter, float64(5*time.Second), "A token should come back every Period/Count")
+
+	// Another instance shares the bucket.
+	ok, _, err = NewPostgresBackend(orderStore.DB()).Take(ctx, key, limit)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Full buckets are deleted.
+	short := "test " + uuid.NewString()
+	ok, _, err = backend.Take(ctx, short, Limit{Count: 1, Period: time.Millisecond})
+	require.NoError(t, err)
+	require.True(t, ok)
+	time.Sleep(10 * time.Millisecond)
+	deleted, err := backend.DeleteFull(ctx)
+	require.NoError(t, err)
+	assert.Positive(t, deleted)
+}
@@ -0,0 +1,23 @@
+package store
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+	ErrIdempotencyKeyExists   = errors.New("idempotency key already exists")
+)
+
+// IdempotencyRecord binds a client-supplied idempotency key to the order it created.
+// Keys are scoped to the user of the order, so users cannot collide with each other.
+type IdempotencyRecord struct {
+	UserID string `db:"user_id"`
+	Key    string `db:"key"`
+	// Fingerprint identifies the request payload the key was first used with.
+	Fingerprint string    `db:"fingerprint"`
+	OrderID     string    `db:"order_id"`
+	CreatedAt   time.Time `db:"created_at"`
+	ExpiresAt   time.Time `db:"expires_at"`
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/demo/order/internal/entity"
+)
+
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// ListQuery describes a single page of orders requested from OrderStore.List.
+// Orders are returned newest first; zero-valued filters are not applied.
+type ListQuery struct {
+	// PageSize is the maximum number of orders to return, must be positive.
+	PageSize int
+	// PageToken is the NextPageToken of the previous page, empty for the first page.
+	PageToken string
+
+	UserID string
+	Status entity.OrderStatus
+	// Currency is an ISO-4217 code.
+	Currency string
+	// MinAmount and MaxAmount are inclusive bounds in major units, whatever the currency.
+	MinAmount *entity.Money
+	MaxAmount *entity.Money
+	// CreatedAfter is an inclusive bound, CreatedBefore is an exclusive one.
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// IncludeDeleted also returns soft-deleted orders.
+	IncludeDeleted bool
+}
+
+// ListPage is a page of orders returned by OrderStore.List.
+type ListPage struct {
+	Orders []*entity.Order
+	// NextPageToken is empty when there are no more orders.
+	NextPageToken string
+}
+
+// newListPage trims orders fetched with one extra row down to pageSize and
+// derives the next page token from the last order kept.
+func newListPage(orders []*entity.Order, pageSize int) *ListPage {
+	if len(orders) <= pageSize {
+		return &ListPage{Orders: orders}
+	}
+	orders = orders[:pageSize]
+	return &ListPage{
+		Orders:        orders,
+		NextPageToken: encodePageToken(orders[len(orders)-1]),
+	}
+}
+
+// pageCursor is the keyset position encoded in a page token: the sort key of
+// the last order on the previous page.
+type pageCursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+}
+
+func encodePageToken(order *entity.Order) string {
+	data, _ := json.Marshal(pageCursor{CreatedAt: order.CreatedAt, ID: order.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(token string) (*pageCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, ErrInvalidPageToken
+	}
+	return &cursor, nil
+}
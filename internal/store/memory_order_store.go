@@ -0,0 +1,859 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/demo/order/internal/entity"
+)
+
+// MemoryStore is an in-memory OrderStore for local development and tests.
+// It mirrors PostgresStore semantics: amounts must fit the amount column,
+// timestamps are rounded to microseconds, and returned orders never alias stored ones.
+type MemoryStore struct {
+	mu              sync.RWMutex
+	orders          map[string]*entity.Order
+	idempotencyKeys map[idempotencyKey]*IdempotencyRecord
+	// events is the outbox, ordered by ID.
+	events      []*memoryOrderEvent
+	lastEventID int64
+	hub         *OrderEventHub
+
+	webhookSubscriptions map[string]*entity.WebhookSubscription
+	// webhookDeliveries are ordered by ID.
+	webhookDeliveries     []*entity.WebhookDelivery
+	lastWebhookDeliveryID int64
+}
+
+// idempotencyKey identifies an IdempotencyRecord.
+type idempotencyKey struct {
+	userID string
+	key    string
+}
+
+type memoryOrderEvent struct {
+	entity.OrderEvent
+	nextAttemptAt time.Time
+	publishedAt   *time.Time
+	lastError     string
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		orders:          make(map[string]*entity.Order),
+		idempotencyKeys: make(map[idempotencyKey]*IdempotencyRecord),
+		hub:             NewOrderEventHub(),
+
+		webhookSubscriptions: make(map[string]*entity.WebhookSubscription),
+	}
+}
+
+func (s *MemoryStore) Create(ctx context.Context, order *entity.Order) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	stored, err := newStoredOrder(order)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.orders[order.ID]; ok {
+		return ErrOrderAlreadyExists
+	}
+	if err := s.recordEvent(entity.OrderEventCreated, stored, ""); err != nil {
+		return err
+	}
+	s.orders[order.ID] = stored
+	return nil
+}
+
+func (s *MemoryStore) CreateWithIdempotencyKey(
+	ctx context.Context,
+	order *entity.Order,
+	record IdempotencyRecord,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	stored, err := newStoredOrder(order)
+	if err != nil {
+		return err
+	}
+	record.CreatedAt = normalizeTime(record.CreatedAt)
+	record.ExpiresAt = normalizeTime(record.ExpiresAt)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.orders[order.ID]; ok {
+		return ErrOrderAlreadyExists
+	}
+	key := idempotencyKey{userID: record.UserID, key: record.Key}
+	if existing, ok := s.idempotencyKeys[key]; ok && existing.ExpiresAt.After(record.CreatedAt) {
+		return ErrIdempotencyKeyExists
+	}
+	if err := s.recordEvent(entity.OrderEventCreated, stored, ""); err != nil {
+		return err
+	}
+	s.orders[order.ID] = stored
+	s.idempotencyKeys[key] = &record
+	return nil
+}
+
+func (s *MemoryStore) GetIdempotencyRecord(
+	ctx context.Context,
+	userID, key string,
+	now time.Time,
+) (*IdempotencyRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, ok := s.idempotencyKeys[idempotencyKey{userID: userID, key: key}]
+	if !ok || !record.ExpiresAt.After(now) {
+		return nil, ErrIdempotencyKeyNotFound
+	}
+	c := *record
+	return &c, nil
+}
+
+func (s *MemoryStore) DeleteExpiredIdempotencyRecords(ctx context.Context, now time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for key, record := range s.idempotencyKeys {
+		if !record.ExpiresAt.After(now) {
+			delete(s.idempotencyKeys, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, id string) (*entity.Order, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	order, ok := s.orders[id]
+	if !ok || order.DeletedAt != nil {
+		return nil, ErrOrderNotFound
+	}
+	return copyOrder(order), nil
+}
+
+func (s *MemoryStore) GetIncludingDeleted(ctx context.Context, id string) (*entity.Order, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	order, ok := s.orders[id]
+	if !ok {
+		return nil, ErrOrderNotFound
+	}
+	return copyOrder(order), nil
+}
+
+func (s *MemoryStore) List(ctx context.Context, query ListQuery) (*ListPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	cursor, err := decodePageToken(query.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	orders := make([]*entity.Order, 0, len(s.orders))
+	for _, order := range s.orders {
+		if matchesListQuery(order, query, cursor) {
+			orders = append(orders, copyOrder(order))
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(orders, func(i, j int) bool {
+		return orderedBefore(orders[i], orders[j])
+	})
+	if len(orders) > query.PageSize+1 {
+		orders = orders[:query.PageSize+1]
+	}
+	return newListPage(orders, query.PageSize), nil
+}
+
+func (s *MemoryStore) UpdateStatus(
+	ctx context.Context,
+	id string,
+	from, to entity.OrderStatus,
+) (*entity.Order, error) {
+	return s.update(ctx, id, from, entity.OrderEventStatusChanged, func(order *entity.Order) {
+		order.Status = to
+	})
+}
+
+func (s *MemoryStore) Cancel(
+	ctx context.Context,
+	id string,
+	from entity.OrderStatus,
+	cancellation entity.OrderCancellation,
+) (*entity.Order, error) {
+	return s.update(ctx, id, from, entity.OrderEventCancelled, func(order *entity.Order) {
+		order.Status = entity.OrderStatusCancelled
+		order.OrderCancellation = cancellation
+		if cancellation.CancelledAt != nil {
+			cancelledAt := normalizeTime(*cancellation.CancelledAt)
+			order.CancelledAt = &cancelledAt
+		}
+	})
+}
+
+// update applies fn to the order if it is still in status `from` and records the event of the given type.
+func (s *MemoryStore) update(
+	ctx context.Context,
+	id string,
+	from entity.OrderStatus,
+	eventType entity.OrderEventType,
+	fn func(order *entity.Order),
+) (*entity.Order, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[id]
+	if !ok || order.DeletedAt != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.Status != from {
+		return nil, ErrOrderStatusConflict
+	}
+	updated := copyOrder(order)
+	fn(updated)
+	if err := s.recordEvent(eventType, updated, from); err != nil {
+		return nil, err
+	}
+	s.orders[id] = updated
+	return copyOrder(updated), nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, id string, deletedAt time.Time) (*entity.Order, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[id]
+	if !ok || order.DeletedAt != nil {
+		return nil, ErrOrderNotFound
+	}
+	deleted := copyOrder(order)
+	deletedAt = normalizeTime(deletedAt)
+	deleted.DeletedAt = &deletedAt
+	if err := s.recordEvent(entity.OrderEventDeleted, deleted, ""); err != nil {
+		return nil, err
+	}
+	s.orders[id] = deleted
+	return copyOrder(deleted), nil
+}
+
+func (s *MemoryStore) Restore(ctx context.Context, id string) (*entity.Order, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[id]
+	if !ok {
+		return nil, ErrOrderNotFound
+	}
+	if order.DeletedAt == nil {
+		return nil, ErrOrderNotDeleted
+	}
+	restored := copyOrder(order)
+	restored.DeletedAt = nil
+	if err := s.recordEvent(entity.OrderEventRestored, restored, ""); err != nil {
+		return nil, err
+	}
+	s.orders[id] = restored
+	return copyOrder(restored), nil
+}
+
+func (s *MemoryStore) PurgeDeletedOrders(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for id, order := range s.orders {
+		if order.DeletedAt != nil && order.DeletedAt.Before(deletedBefore) {
+			delete(s.orders, id)
+			purged++
+		}
+	}
+	for key, record := range s.idempotencyKeys {
+		if _, ok := s.orders[record.OrderID]; !ok {
+			delete(s.idempotencyKeys, key)
+		}
+	}
+	return purged, nil
+}
+
+// recordEvent appends the event describing the change of order to the outbox. s.mu must be held.
+func (s *MemoryStore) recordEvent(eventType entity.OrderEventType, order *entity.Order, previousStatus entity.OrderStatus) error {
+	now := normalizeTime(time.Now())
+	event, err := entity.NewOrderEvent(eventType, order, previousStatus, now)
+	if err != nil {
+		return err
+	}
+	s.lastEventID++
+	event.ID = s.lastEventID
+	s.events = append(s.events, &memoryOrderEvent{OrderEvent: event, nextAttemptAt: now})
+	// Nothing fails after the event is recorded, so it is as good as committed.
+	s.hub.Publish(event)
+	return nil
+}
+
+func (s *MemoryStore) ListOrderEvents(ctx context.Context, afterID int64, limit int) ([]entity.OrderEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []entity.OrderEvent
+	for i := s.eventIndex(afterID + 1); i < len(s.events) && len(events) < limit; i++ {
+		events = append(events, copyOrderEvent(s.events[i].OrderEvent))
+	}
+	return events, nil
+}
+
+func (s *MemoryStore) SubscribeOrderEvents(ctx context.Context) (*OrderEventSubscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.hub.Subscribe(), nil
+}
+
+func (s *MemoryStore) ClaimOrderEvents(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]entity.OrderEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var claimed []entity.OrderEvent
+	blocked := make(map[string]bool)
+	for _, event := range s.events {
+		if len(claimed) == limit {
+			break
+		}
+		if event.publishedAt != nil || blocked[event.OrderID] {
+			continue
+		}
+		// Later events of the order wait for this one.
+		blocked[event.OrderID] = true
+		if event.nextAttemptAt.After(now) {
+			continue
+		}
+		event.nextAttemptAt = now.Add(lease)
+		claimed = append(claimed, copyOrderEvent(event.OrderEvent))
+	}
+	return claimed, nil
+}
+
+func (s *MemoryStore) MarkOrderEventPublished(ctx context.Context, id int64, publishedAt time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, err := s.event(id)
+	if err != nil {
+		return err
+	}
+	publishedAt = normalizeTime(publishedAt)
+	event.publishedAt = &publishedAt
+	event.lastError = ""
+	return nil
+}
+
+func (s *MemoryStore) MarkOrderEventFailed(
+	ctx context.Context,
+	id int64,
+	nextAttemptAt time.Time,
+	lastError string,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, err := s.event(id)
+	if err != nil {
+		return err
+	}
+	if event.publishedAt != nil {
+		return ErrOrderEventNotFound
+	}
+	event.Attempts++
+	event.nextAttemptAt = nextAttemptAt
+	event.lastError = lastError
+	return nil
+}
+
+// event returns the outbox event with the given ID. s.mu must be held.
+func (s *MemoryStore) PurgePublishedOrderEvents(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := make(map[int64]bool)
+	for _, delivery := range s.webhookDeliveries {
+		if delivery.Status == entity.WebhookDeliveryPending {
+			pending[delivery.EventID] = true
+		}
+	}
+	purged := make(map[int64]bool)
+	events := s.events[:0]
+	for _, event := range s.events {
+		if event.publishedAt != nil && event.publishedAt.Before(publishedBefore) && !pending[event.ID] {
+			purged[event.ID] = true
+			continue
+		}
+		events = append(events, event)
+	}
+	clear(s.events[len(events):])
+	s.events = events
+
+	deliveries := s.webhookDeliveries[:0]
+	for _, delivery := range s.webhookDeliveries {
+		if !purged[delivery.EventID] {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	clear(s.webhookDeliveries[len(deliveries):])
+	s.webhookDeliveries = deliveries
+	return int64(len(purged)), nil
+}
+
+// eventIndex returns the index of the first event in s.events with an ID of at least id. s.mu must be held.
+func (s *MemoryStore) eventIndex(id int64) int {
+	return sort.Search(len(s.events), func(i int) bool { return s.events[i].ID >= id })
+}
+
+// event returns the event with the ID. s.mu must be held.
+func (s *MemoryStore) event(id int64) (*memoryOrderEvent, error) {
+	i := s.eventIndex(id)
+	if i == len(s.events) || s.events[i].ID != id {
+		return nil, ErrOrderEventNotFound
+	}
+	return s.events[i], nil
+}
+
+func (s *MemoryStore) CreateWebhookSubscription(ctx context.Context, subscription *entity.WebhookSubscription) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := copyWebhookSubscription(subscription)
+	stored.CreatedAt = normalizeTime(stored.CreatedAt)
+	s.webhookSubscriptions[stored.ID] = stored
+	return nil
+}
+
+func (s *MemoryStore) ListWebhookSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	subscriptions := make([]*entity.WebhookSubscription, 0, len(s.webhookSubscriptions))
+	for _, subscription := range s.webhookSubscriptions {
+		subscriptions = append(subscriptions, copyWebhookSubscription(subscription))
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(subscriptions, func(i, j int) bool {
+		a, b := subscriptions[i], subscriptions[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+	return subscriptions, nil
+}
+
+func (s *MemoryStore) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.webhookSubscriptions[id]; !ok {
+		return ErrWebhookSubscriptionNotFound
+	}
+	delete(s.webhookSubscriptions, id)
+
+	deliveries := s.webhookDeliveries[:0]
+	for _, delivery := range s.webhookDeliveries {
+		if delivery.SubscriptionID != id {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	s.webhookDeliveries = deliveries
+	return nil
+}
+
+func (s *MemoryStore) EnqueueWebhookDeliveries(ctx context.Context, event entity.OrderEvent, now time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now = normalizeTime(now)
+	var created int64
+	for _, subscription := range s.webhookSubscriptions {
+		if !subscription.Matches(event.Type) || s.hasWebhookDelivery(subscription.ID, event.ID) {
+			continue
+		}
+		s.lastWebhookDeliveryID++
+		s.webhookDeliveries = append(s.webhookDeliveries, &entity.WebhookDelivery{
+			ID:             s.lastWebhookDeliveryID,
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			OrderID:        event.OrderID,
+			Payload:        append([]byte(nil), event.Payload...),
+			Status:         entity.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+		created++
+	}
+	return created, nil
+}
+
+// hasWebhookDelivery reports whether the event is already enqueued for the subscription. s.mu must be held.
+func (s *MemoryStore) hasWebhookDelivery(subscriptionID string, eventID int64) bool {
+	for _, delivery := range s.webhookDeliveries {
+		if delivery.SubscriptionID == subscriptionID && delivery.EventID == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MemoryStore) ClaimWebhookDeliveries(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]ClaimedWebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*entity.WebhookDelivery
+	for _, delivery := range s.webhookDeliveries {
+		if delivery.Status == entity.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]ClaimedWebhookDelivery, len(due))
+	for i, delivery := range due {
+		delivery.NextAttemptAt = normalizeTime(now.Add(lease))
+		subscription := s.webhookSubscriptions[delivery.SubscriptionID]
+		claimed[i] = ClaimedWebhookDelivery{
+			WebhookDelivery: *copyWebhookDelivery(delivery),
+			URL:             subscription.URL,
+			Secret:          subscription.Secret,
+		}
+	}
+	return claimed, nil
+}
+
+func (s *MemoryStore) MarkWebhookDelivered(ctx context.Context, id int64, deliveredAt time.Time) error {
+	return s.updatePendingWebhookDelivery(ctx, id, func(delivery *entity.WebhookDelivery) {
+		deliveredAt = normalizeTime(deliveredAt)
+		delivery.Status = entity.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &deliveredAt
+		delivery.LastError = ""
+	})
+}
+
+func (s *MemoryStore) MarkWebhookDeliveryFailed(
+	ctx context.Context,
+	id int64,
+	nextAttemptAt time.Time,
+	lastError string,
+) error {
+	return s.updatePendingWebhookDelivery(ctx, id, func(delivery *entity.WebhookDelivery) {
+		delivery.Attempts++
+		delivery.NextAttemptAt = normalizeTime(nextAttemptAt)
+		delivery.LastError = lastError
+	})
+}
+
+func (s *MemoryStore) MarkWebhookDeliveryDead(ctx context.Context, id int64, lastError string) error {
+	return s.updatePendingWebhookDelivery(ctx, id, func(delivery *entity.WebhookDelivery) {
+		delivery.Attempts++
+		delivery.Status = entity.WebhookDeliveryDead
+		delivery.LastError = lastError
+	})
+}
+
+// updatePendingWebhookDelivery applies fn to the delivery if it is still pending.
+func (s *MemoryStore) updatePendingWebhookDelivery(
+	ctx context.Context,
+	id int64,
+	fn func(delivery *entity.WebhookDelivery),
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delivery := s.webhookDelivery(id)
+	if delivery == nil || delivery.Status != entity.WebhookDeliveryPending {
+		return ErrWebhookDeliveryNotFound
+	}
+	fn(delivery)
+	return nil
+}
+
+func (s *MemoryStore) ListWebhookDeliveries(ctx context.Context, query WebhookDeliveryQuery) (*WebhookDeliveryPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	afterID, err := decodeDeliveryPageToken(query.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var deliveries []*entity.WebhookDelivery
+	// Deliveries are stored by ID, so walking them backwards lists the newest first.
+	for i := len(s.webhookDeliveries) - 1; i >= 0 && len(deliveries) <= query.PageSize; i-- {
+		delivery := s.webhookDeliveries[i]
+		if matchesWebhookDeliveryQuery(delivery, query, afterID) {
+			deliveries = append(deliveries, copyWebhookDelivery(delivery))
+		}
+	}
+	return newWebhookDeliveryPage(deliveries, query.PageSize), nil
+}
+
+func (s *MemoryStore) ReplayWebhookDelivery(ctx context.Context, id int64, now time.Time) (*entity.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delivery := s.webhookDelivery(id)
+	if delivery == nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	if delivery.Status != entity.WebhookDeliveryDead {
+		return nil, ErrWebhookDeliveryNotDead
+	}
+	delivery.Status = entity.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = normalizeTime(now)
+	delivery.LastError = ""
+	return copyWebhookDelivery(delivery), nil
+}
+
+// webhookDelivery returns the delivery with the given ID or nil. s.mu must be held.
+func (s *MemoryStore) webhookDelivery(id int64) *entity.WebhookDelivery {
+	for _, delivery := range s.webhookDeliveries {
+		if delivery.ID == id {
+			return delivery
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+func (s *MemoryStore) Close() error {
+	s.hub.Close()
+	return nil
+}
+
+func matchesListQuery(order *entity.Order, query ListQuery, cursor *pageCursor) bool {
+	if !query.IncludeDeleted && order.DeletedAt != nil {
+		return false
+	}
+	if cursor != nil && !orderedBefore(&entity.Order{ID: cursor.ID, CreatedAt: cursor.CreatedAt}, order) {
+		return false
+	}
+	if query.UserID != "" && order.UserID != query.UserID {
+		return false
+	}
+	if query.Status != "" && order.Status != query.Status {
+		return false
+	}
+	if query.Currency != "" && order.Amount.Currency != query.Currency {
+		return false
+	}
+	if query.MinAmount != nil && order.Amount.Cmp(*query.MinAmount) < 0 {
+		return false
+	}
+	if query.MaxAmount != nil && order.Amount.Cmp(*query.MaxAmount) > 0 {
+		return false
+	}
+	if query.CreatedAfter != nil && order.CreatedAt.Before(*query.CreatedAfter) {
+		return false
+	}
+	if query.CreatedBefore != nil && !order.CreatedAt.Before(*query.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+func matchesWebhookDeliveryQuery(delivery *entity.WebhookDelivery, query WebhookDeliveryQuery, afterID int64) bool {
+	if afterID > 0 && delivery.ID >= afterID {
+		return false
+	}
+	if query.SubscriptionID != "" && delivery.SubscriptionID != query.SubscriptionID {
+		return false
+	}
+	if query.Status != "" && delivery.Status != query.Status {
+		return false
+	}
+	if query.OrderID != "" && delivery.OrderID != query.OrderID {
+		return false
+	}
+	return true
+}
+
+// orderedBefore reports whether a comes before b in List order: created_at DESC, id DESC.
+func orderedBefore(a, b *entity.Order) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.ID > b.ID
+}
+
+// newStoredOrder returns a copy of order as Postgres would store it.
+func newStoredOrder(order *entity.Order) (*entity.Order, error) {
+	if err := order.Amount.Validate(); err != nil {
+		return nil, err
+	}
+	for _, item := range order.Items {
+		if err := item.UnitPrice.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	stored := copyOrder(order)
+	stored.CreatedAt = normalizeTime(stored.CreatedAt)
+	return stored, nil
+}
+
+// normalizeTime mimics a round trip through a Postgres TIMESTAMP column.
+func normalizeTime(t time.Time) time.Time {
+	return t.UTC().Round(time.Microsecond)
+}
+
+func copyOrderEvent(event entity.OrderEvent) entity.OrderEvent {
+	event.Payload = append([]byte(nil), event.Payload...)
+	return event
+}
+
+func copyOrder(order *entity.Order) *entity.Order {
+	c := *order
+	c.Items = append([]entity.OrderItem(nil), order.Items...)
+	if order.CancelledAt != nil {
+		cancelledAt := *order.CancelledAt
+		c.CancelledAt = &cancelledAt
+	}
+	if order.DeletedAt != nil {
+		deletedAt := *order.DeletedAt
+		c.DeletedAt = &deletedAt
+	}
+	return &c
+}
+
+func copyWebhookSubscription(subscription *entity.WebhookSubscription) *entity.WebhookSubscription {
+	c := *subscription
+	c.EventTypes = append([]entity.OrderEventType(nil), subscription.EventTypes...)
+	return &c
+}
+
+func copyWebhookDelivery(delivery *entity.WebhookDelivery) *entity.WebhookDelivery {
+	c := *delivery
+	c.Payload = append([]byte(nil), delivery.Payload...)
+	if delivery.DeliveredAt != nil {
+		deliveredAt := *delivery.DeliveredAt
+		c.DeliveredAt = &deliveredAt
+	}
+	return &c
+}
@@ -0,0 +1,17 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/demo/order/internal/store"
+	"github.com/demo/order/internal/store/storetest"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestMemoryStore(t *testing.T) {
+	suite.Run(t, &storetest.OrderStoreSuite{
+		NewStore: func() (store.OrderStore, error) {
+			return store.NewMemoryStore(), nil
+		},
+	})
+}
@@ -0,0 +1,81 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbedded(t *testing.T) {
+	migrations, err := Embedded()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, m := range migrations {
+		assert.Equal(t, int64(i+1), m.Version, "Migration versions should be consecutive")
+		assert.NotEmpty(t, m.Name)
+		assert.NotEmpty(t, m.Up)
+		assert.NotEmpty(t, m.Down)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	testCases := []struct {
+		name     string
+		files    fstest.MapFS
+		wantErr  string
+		versions []int64
+	}{
+		{
+			name: "Should order migrations by version",
+			files: fstest.MapFS{
+				"000010_b.up.sql":   {Data: []byte("SELECT 10")},
+				"000010_b.down.sql": {Data: []byte("SELECT -10")},
+				"000002_a.up.sql":   {Data: []byte("SELECT 2")},
+				"000002_a.down.sql": {Data: []byte("SELECT -2")},
+			},
+			versions: []int64{2, 10},
+		},
+		{
+			name: "Should fail when down file is missing",
+			files: fstest.MapFS{
+				"000001_a.up.sql": {Data: []byte("SELECT 1")},
+			},
+			wantErr: "must have both up and down files",
+		},
+		{
+			name: "Should fail when names of a version differ",
+			files: fstest.MapFS{
+				"000001_a.up.sql":   {Data: []byte("SELECT 1")},
+				"000001_b.down.sql": {Data: []byte("SELECT -1")},
+			},
+			wantErr: "conflicting names",
+		},
+		{
+			name: "Should fail on unexpected file name",
+			files: fstest.MapFS{
+				"create_orders.sql": {Data: []byte("SELECT 1")},
+			},
+			wantErr: "unexpected migration file name",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			migrations, err := load(tc.files)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			versions := make([]int64, len(migrations))
+			for i, m := range migrations {
+				versions[i] = m.Version
+			}
+			assert.Equal(t, tc.versions, versions)
+		})
+	}
+}
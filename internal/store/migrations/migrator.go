@@ -0,0 +1,255 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// advisoryLockKey is the Postgres advisory lock held while migrations run, so
+// that service instances starting at the same time do not migrate concurrently.
+const advisoryLockKey int64 = 0x6f72646572 // "order"
+
+// Status describes a known migration and whether it has been applied.
+type Status struct {
+	Migration
+	// AppliedAt is nil for pending migrations.
+	AppliedAt *time.Time
+}
+
+// Migrator applies and rolls back the embedded migrations, recording
+// applied versions in the schema_migrations table.
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sqlx.DB) (*Migrator, error) {
+	migrations, err := Embedded()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Latest returns the version of the newest known migration, or 0 if there are none.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies all pending migrations. Applied versions unknown to this binary,
+// e.g. left by a newer release, are kept as they are.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sqlx.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		return m.applyPending(ctx, conn, applied, m.Latest())
+	})
+}
+
+// Down rolls back the most recently applied migration. It is a no-op if nothing is applied.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sqlx.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		var last int64
+		for version := range applied {
+			last = max(last, version)
+		}
+		if last == 0 {
+			return nil
+		}
+		migration, err := m.find(last)
+		if err != nil {
+			return err
+		}
+		return rollback(ctx, conn, migration)
+	})
+}
+
+// To migrates the schema up or down so that exactly the migrations with
+// version <= target are applied. Target 0 rolls back every migration.
+func (m *Migrator) To(ctx context.Context, target int64) error {
+	if target != 0 {
+		if _, err := m.find(target); err != nil {
+			return err
+		}
+	}
+
+	return m.withLock(ctx, func(conn *sqlx.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for version := range applied {
+			if _, err := m.find(version); err != nil && version > target {
+				return fmt.Errorf("applied migration %d is unknown to this binary and cannot be rolled back", version)
+			}
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if migration.Version <= target || applied[migration.Version] == nil {
+				continue
+			}
+			if err := rollback(ctx, conn, migration); err != nil {
+				return err
+			}
+		}
+
+		return m.applyPending(ctx, conn, applied, target)
+	})
+}
+
+// Status reports every known migration with its applied time.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *sqlx.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		statuses = make([]Status, len(m.migrations))
+		for i, migration := range m.migrations {
+			statuses[i] = Status{Migration: migration, AppliedAt: applied[migration.Version]}
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// Pending returns the known migrations that are not applied yet. Unlike Status
+// it does not wait for migrations running elsewhere, so it is cheap enough for health checks.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := appliedVersions(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if applied[migration.Version] == nil {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// applyPending applies, in order, every migration up to target that is not applied yet.
+func (m *Migrator) applyPending(
+	ctx context.Context,
+	conn *sqlx.Conn,
+	applied map[int64]*time.Time,
+	target int64,
+) error {
+	for _, migration := range m.migrations {
+		if migration.Version > target || applied[migration.Version] != nil {
+			continue
+		}
+		if err := apply(ctx, conn, migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) find(version int64) (Migration, error) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, nil
+		}
+	}
+	return Migration{}, fmt.Errorf("unknown migration version %d", version)
+}
+
+// withLock runs fn on a dedicated connection holding the migrations advisory lock.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sqlx.Conn) error) (err error) {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockKey); err != nil {
+		return fmt.Errorf("acquire migrations lock: %w", err)
+	}
+	defer func() {
+		// The lock is released with the session anyway, so an unlock failure is only reported.
+		_, unlockErr := conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, advisoryLockKey)
+		if err == nil && unlockErr != nil {
+			err = fmt.Errorf("release migrations lock: %w", unlockErr)
+		}
+	}()
+
+	const query = `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL
+		)`
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func appliedVersions(ctx context.Context, q sqlx.QueryerContext) (map[int64]*time.Time, error) {
+	const query = `SELECT version, applied_at FROM schema_migrations`
+	var rows []struct {
+		Version   int64     `db:"version"`
+		AppliedAt time.Time `db:"applied_at"`
+	}
+	if err := sqlx.SelectContext(ctx, q, &rows, query); err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]*time.Time, len(rows))
+	for _, row := range rows {
+		appliedAt := row.AppliedAt
+		applied[row.Version] = &appliedAt
+	}
+	return applied, nil
+}
+
+func apply(ctx context.Context, conn *sqlx.Conn, migration Migration) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+		return fmt.Errorf("apply migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	const query = `INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`
+	if _, err := tx.ExecContext(ctx, query, migration.Version, migration.Name, time.Now().UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func rollback(ctx context.Context, conn *sqlx.Conn, migration Migration) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+		return fmt.Errorf("roll back migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	const query = `DELETE FROM schema_migrations WHERE version = $1`
+	if _, err := tx.ExecContext(ctx, query, migration.Version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,5 @@
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS cancel_reason VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS cancel_comment TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS cancelled_by VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP NULL;
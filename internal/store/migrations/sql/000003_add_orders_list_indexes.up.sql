@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS orders_created_at_id_idx ON orders (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS orders_user_id_created_at_id_idx ON orders (user_id, created_at DESC, id DESC);
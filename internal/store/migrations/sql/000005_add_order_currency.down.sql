@@ -0,0 +1,3 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS currency;
+-- Amounts with more than two decimal places are rounded.
+ALTER TABLE orders ALTER COLUMN amount TYPE DECIMAL(10, 2);
@@ -0,0 +1,2 @@
+ALTER TABLE orders ALTER COLUMN amount TYPE DECIMAL(12, 4);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'EUR';
@@ -0,0 +1,8 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS item VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Only the name of the first line survives the rollback.
+UPDATE orders SET item = order_items.name
+FROM order_items
+WHERE order_items.order_id = orders.id AND order_items.position = 0;
+
+DROP TABLE IF EXISTS order_items;
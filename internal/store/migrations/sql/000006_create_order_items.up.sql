@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS order_items (
+    order_id VARCHAR(36) NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    sku VARCHAR(100) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    unit_price DECIMAL(12, 4) NOT NULL,
+    PRIMARY KEY (order_id, position)
+);
+
+-- Every existing order becomes a single line with its item and amount.
+INSERT INTO order_items (order_id, position, sku, name, quantity, unit_price)
+SELECT id, 0, '', item, 1, amount FROM orders
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS item;
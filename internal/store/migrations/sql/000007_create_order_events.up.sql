@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS order_events (
+    id BIGSERIAL PRIMARY KEY,
+    order_id VARCHAR(36) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    published_at TIMESTAMP NULL
+);
+CREATE INDEX IF NOT EXISTS order_events_pending_idx ON order_events (order_id, id) WHERE published_at IS NULL;
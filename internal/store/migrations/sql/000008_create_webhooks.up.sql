@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id VARCHAR(36) PRIMARY KEY,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id VARCHAR(36) NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL REFERENCES order_events (id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL,
+    order_id VARCHAR(36) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    delivered_at TIMESTAMP NULL,
+    UNIQUE (subscription_id, event_id)
+);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at, id) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_order_id_idx ON webhook_deliveries (order_id, id);
@@ -0,0 +1,2 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+CREATE INDEX IF NOT EXISTS orders_deleted_at_idx ON orders (deleted_at) WHERE deleted_at IS NOT NULL;
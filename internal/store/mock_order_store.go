@@ -2,16 +2,43 @@ package store
 
 import (
 	"context"
+	"time"
 
 	"github.com/demo/order/internal/entity"
 )
 
 // MockOrderStore is a mock implementation of OrderStore for testing.
 type MockOrderStore struct {
-	CreateFunc func(ctx context.Context, order *entity.Order) error
-	GetFunc    func(ctx context.Context, id string) (*entity.Order, error)
-	ListFunc   func(ctx context.Context) ([]*entity.Order, error)
-	CloseFunc  func() error
+	CreateFunc                          func(ctx context.Context, order *entity.Order) error
+	CreateWithIdempotencyKeyFunc        func(ctx context.Context, order *entity.Order, record IdempotencyRecord) error
+	GetIdempotencyRecordFunc            func(ctx context.Context, userID, key string, now time.Time) (*IdempotencyRecord, error)
+	DeleteExpiredIdempotencyRecordsFunc func(ctx context.Context, now time.Time) (int64, error)
+	GetFunc                             func(ctx context.Context, id string) (*entity.Order, error)
+	GetIncludingDeletedFunc             func(ctx context.Context, id string) (*entity.Order, error)
+	ListFunc                            func(ctx context.Context, query ListQuery) (*ListPage, error)
+	UpdateStatusFunc                    func(ctx context.Context, id string, from, to entity.OrderStatus) (*entity.Order, error)
+	CancelFunc                          func(ctx context.Context, id string, from entity.OrderStatus, cancellation entity.OrderCancellation) (*entity.Order, error)
+	DeleteFunc                          func(ctx context.Context, id string, deletedAt time.Time) (*entity.Order, error)
+	RestoreFunc                         func(ctx context.Context, id string) (*entity.Order, error)
+	PurgeDeletedOrdersFunc              func(ctx context.Context, deletedBefore time.Time) (int64, error)
+	ClaimOrderEventsFunc                func(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entity.OrderEvent, error)
+	MarkOrderEventPublishedFunc         func(ctx context.Context, id int64, publishedAt time.Time) error
+	MarkOrderEventFailedFunc            func(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+	PurgePublishedOrderEventsFunc       func(ctx context.Context, publishedBefore time.Time) (int64, error)
+	ListOrderEventsFunc                 func(ctx context.Context, afterID int64, limit int) ([]entity.OrderEvent, error)
+	SubscribeOrderEventsFunc            func(ctx context.Context) (*OrderEventSubscription, error)
+	CreateWebhookSubscriptionFunc       func(ctx context.Context, subscription *entity.WebhookSubscription) error
+	ListWebhookSubscriptionsFunc        func(ctx context.Context) ([]*entity.WebhookSubscription, error)
+	DeleteWebhookSubscriptionFunc       func(ctx context.Context, id string) error
+	EnqueueWebhookDeliveriesFunc        func(ctx context.Context, event entity.OrderEvent, now time.Time) (int64, error)
+	ClaimWebhookDeliveriesFunc          func(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]ClaimedWebhookDelivery, error)
+	MarkWebhookDeliveredFunc            func(ctx context.Context, id int64, deliveredAt time.Time) error
+	MarkWebhookDeliveryFailedFunc       func(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+	MarkWebhookDeliveryDeadFunc         func(ctx context.Context, id int64, lastError string) error
+	ListWebhookDeliveriesFunc           func(ctx context.Context, query WebhookDeliveryQuery) (*WebhookDeliveryPage, error)
+	ReplayWebhookDeliveryFunc           func(ctx context.Context, id int64, now time.Time) (*entity.WebhookDelivery, error)
+	PingFunc                            func(ctx context.Context) error
+	CloseFunc                           func() error
 }
 
 func (m *MockOrderStore) Create(ctx context.Context, order *entity.Order) error {
@@ -21,6 +48,35 @@ func (m *MockOrderStore) Create(ctx context.Context, order *entity.Order) error
 	return nil
 }
 
+func (m *MockOrderStore) CreateWithIdempotencyKey(
+	ctx context.Context,
+	order *entity.Order,
+	record IdempotencyRecord,
+) error {
+	if m.CreateWithIdempotencyKeyFunc != nil {
+		return m.CreateWithIdempotencyKeyFunc(ctx, order, record)
+	}
+	return nil
+}
+
+func (m *MockOrderStore) GetIdempotencyRecord(
+	ctx context.Context,
+	userID, key string,
+	now time.Time,
+) (*IdempotencyRecord, error) {
+	if m.GetIdempotencyRecordFunc != nil {
+		return m.GetIdempotencyRecordFunc(ctx, userID, key, now)
+	}
+	return nil, ErrIdempotencyKeyNotFound
+}
+
+func (m *MockOrderStore) DeleteExpiredIdempotencyRecords(ctx context.Context, now time.Time) (int64, error) {
+	if m.DeleteExpiredIdempotencyRecordsFunc != nil {
+		return m.DeleteExpiredIdempotencyRecordsFunc(ctx, now)
+	}
+	return 0, nil
+}
+
 func (m *MockOrderStore) Get(ctx context.Context, id string) (*entity.Order, error) {
 	if m.GetFunc != nil {
 		return m.GetFunc(ctx, id)
@@ -28,13 +84,203 @@ func (m *MockOrderStore) Get(ctx context.Context, id string) (*entity.Order, err
 	return nil, nil
 }
 
-func (m *MockOrderStore) List(ctx context.Context) ([]*entity.Order, error) {
+func (m *MockOrderStore) GetIncludingDeleted(ctx context.Context, id string) (*entity.Order, error) {
+	if m.GetIncludingDeletedFunc != nil {
+		return m.GetIncludingDeletedFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockOrderStore) List(ctx context.Context, query ListQuery) (*ListPage, error) {
 	if m.ListFunc != nil {
-		return m.ListFunc(ctx)
+		return m.ListFunc(ctx, query)
+	}
+	return nil, nil
+}
+
+func (m *MockOrderStore) UpdateStatus(
+	ctx context.Context,
+	id string,
+	from, to entity.OrderStatus,
+) (*entity.Order, error) {
+	if m.UpdateStatusFunc != nil {
+		return m.UpdateStatusFunc(ctx, id, from, to)
+	}
+	return nil, nil
+}
+
+func (m *MockOrderStore) Cancel(
+	ctx context.Context,
+	id string,
+	from entity.OrderStatus,
+	cancellation entity.OrderCancellation,
+) (*entity.Order, error) {
+	if m.CancelFunc != nil {
+		return m.CancelFunc(ctx, id, from, cancellation)
+	}
+	return nil, nil
+}
+
+func (m *MockOrderStore) Delete(ctx context.Context, id string, deletedAt time.Time) (*entity.Order, error) {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id, deletedAt)
+	}
+	return nil, nil
+}
+
+func (m *MockOrderStore) Restore(ctx context.Context, id string) (*entity.Order, error) {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockOrderStore) PurgeDeletedOrders(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	if m.PurgeDeletedOrdersFunc != nil {
+		return m.PurgeDeletedOrdersFunc(ctx, deletedBefore)
+	}
+	return 0, nil
+}
+
+func (m *MockOrderStore) ClaimOrderEvents(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]entity.OrderEvent, error) {
+	if m.ClaimOrderEventsFunc != nil {
+		return m.ClaimOrderEventsFunc(ctx, now, lease, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockOrderStore) MarkOrderEventPublished(ctx context.Context, id int64, publishedAt time.Time) error {
+	if m.MarkOrderEventPublishedFunc != nil {
+		return m.MarkOrderEventPublishedFunc(ctx, id, publishedAt)
+	}
+	return nil
+}
+
+func (m *MockOrderStore) MarkOrderEventFailed(
+	ctx context.Context,
+	id int64,
+	nextAttemptAt time.Time,
+	lastError string,
+) error {
+	if m.MarkOrderEventFailedFunc != nil {
+		return m.MarkOrderEventFailedFunc(ctx, id, nextAttemptAt, lastError)
+	}
+	return nil
+}
+
+func (m *MockOrderStore) PurgePublishedOrderEvents(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	if m.PurgePublishedOrderEventsFunc != nil {
+		return m.PurgePublishedOrderEventsFunc(ctx, publishedBefore)
+	}
+	return 0, nil
+}
+
+func (m *MockOrderStore) ListOrderEvents(ctx context.Context, afterID int64, limit int) ([]entity.OrderEvent, error) {
+	if m.ListOrderEventsFunc != nil {
+		return m.ListOrderEventsFunc(ctx, afterID, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockOrderStore) SubscribeOrderEvents(ctx context.Context) (*OrderEventSubscription, error) {
+	if m.SubscribeOrderEventsFunc != nil {
+		return m.SubscribeOrderEventsFunc(ctx)
+	}
+	return NewOrderEventHub().Subscribe(), nil
+}
+
+func (m *MockOrderStore) CreateWebhookSubscription(ctx context.Context, subscription *entity.WebhookSubscription) error {
+	if m.CreateWebhookSubscriptionFunc != nil {
+		return m.CreateWebhookSubscriptionFunc(ctx, subscription)
+	}
+	return nil
+}
+
+func (m *MockOrderStore) ListWebhookSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error) {
+	if m.ListWebhookSubscriptionsFunc != nil {
+		return m.ListWebhookSubscriptionsFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockOrderStore) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	if m.DeleteWebhookSubscriptionFunc != nil {
+		return m.DeleteWebhookSubscriptionFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockOrderStore) EnqueueWebhookDeliveries(ctx context.Context, event entity.OrderEvent, now time.Time) (int64, error) {
+	if m.EnqueueWebhookDeliveriesFunc != nil {
+		return m.EnqueueWebhookDeliveriesFunc(ctx, event, now)
+	}
+	return 0, nil
+}
+
+func (m *MockOrderStore) ClaimWebhookDeliveries(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]ClaimedWebhookDelivery, error) {
+	if m.ClaimWebhookDeliveriesFunc != nil {
+		return m.ClaimWebhookDeliveriesFunc(ctx, now, lease, limit)
 	}
 	return nil, nil
 }
 
+func (m *MockOrderStore) MarkWebhookDelivered(ctx context.Context, id int64, deliveredAt time.Time) error {
+	if m.MarkWebhookDeliveredFunc != nil {
+		return m.MarkWebhookDeliveredFunc(ctx, id, deliveredAt)
+	}
+	return nil
+}
+
+func (m *MockOrderStore) MarkWebhookDeliveryFailed(
+	ctx context.Context,
+	id int64,
+	nextAttemptAt time.Time,
+	lastError string,
+) error {
+	if m.MarkWebhookDeliveryFailedFunc != nil {
+		return m.MarkWebhookDeliveryFailedFunc(ctx, id, nextAttemptAt, lastError)
+	}
+	return nil
+}
+
+func (m *MockOrderStore) MarkWebhookDeliveryDead(ctx context.Context, id int64, lastError string) error {
+	if m.MarkWebhookDeliveryDeadFunc != nil {
+		return m.MarkWebhookDeliveryDeadFunc(ctx, id, lastError)
+	}
+	return nil
+}
+
+func (m *MockOrderStore) ListWebhookDeliveries(ctx context.Context, query WebhookDeliveryQuery) (*WebhookDeliveryPage, error) {
+	if m.ListWebhookDeliveriesFunc != nil {
+		return m.ListWebhookDeliveriesFunc(ctx, query)
+	}
+	return nil, nil
+}
+
+func (m *MockOrderStore) ReplayWebhookDelivery(ctx context.Context, id int64, now time.Time) (*entity.WebhookDelivery, error) {
+	if m.ReplayWebhookDeliveryFunc != nil {
+		return m.ReplayWebhookDeliveryFunc(ctx, id, now)
+	}
+	return nil, nil
+}
+
+func (m *MockOrderStore) Ping(ctx context.Context) error {
+	if m.PingFunc != nil {
+		return m.PingFunc(ctx)
+	}
+	return nil
+}
+
 func (m *MockOrderStore) Close() error {
 	if m.CloseFunc != nil {
 		return m.CloseFunc()
@@ -4,88 +4,563 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store/migrations"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
-var ErrOrderNotFound = errors.New("order not found")
+// pqUniqueViolation is the Postgres error code for a unique constraint violation.
+const pqUniqueViolation = "23505"
+
+var (
+	ErrOrderNotFound       = errors.New("order not found")
+	ErrOrderAlreadyExists  = errors.New("order already exists")
+	ErrOrderStatusConflict = errors.New("order status has been changed concurrently")
+	ErrOrderEventNotFound  = errors.New("order event not found")
+	ErrOrderNotDeleted     = errors.New("order is not deleted")
+)
 
 type OrderStore interface {
 	Create(ctx context.Context, order *entity.Order) error
+	// CreateWithIdempotencyKey creates the order and stores the idempotency record in one transaction.
+	// It returns ErrIdempotencyKeyExists if an unexpired record with the same user and key exists.
+	CreateWithIdempotencyKey(ctx context.Context, order *entity.Order, record IdempotencyRecord) error
+	// GetIdempotencyRecord returns the unexpired record of the user for key or ErrIdempotencyKeyNotFound.
+	GetIdempotencyRecord(ctx context.Context, userID, key string, now time.Time) (*IdempotencyRecord, error)
+	// DeleteExpiredIdempotencyRecords removes records that expired before now.
+	DeleteExpiredIdempotencyRecords(ctx context.Context, now time.Time) (int64, error)
+	// Get returns the order unless it is soft-deleted, see GetIncludingDeleted.
 	Get(ctx context.Context, id string) (*entity.Order, error)
-	List(ctx context.Context) ([]*entity.Order, error)
+	GetIncludingDeleted(ctx context.Context, id string) (*entity.Order, error)
+	List(ctx context.Context, query ListQuery) (*ListPage, error)
+	// UpdateStatus atomically moves the order from status `from` to status `to`.
+	// It returns ErrOrderStatusConflict if the order is no longer in status `from`.
+	UpdateStatus(ctx context.Context, id string, from, to entity.OrderStatus) (*entity.Order, error)
+	// Cancel atomically moves the order from status `from` to CANCELLED and records the cancellation.
+	// It returns ErrOrderStatusConflict if the order is no longer in status `from`.
+	Cancel(ctx context.Context, id string, from entity.OrderStatus, cancellation entity.OrderCancellation) (*entity.Order, error)
+	// Delete soft-deletes the order at deletedAt. It returns ErrOrderNotFound if the order is already deleted.
+	// Soft-deleted orders are left out by the methods above and can no longer change.
+	Delete(ctx context.Context, id string, deletedAt time.Time) (*entity.Order, error)
+	// Restore undoes the soft deletion of the order. It returns ErrOrderNotDeleted if the order is not deleted.
+	Restore(ctx context.Context, id string) (*entity.Order, error)
+	// PurgeDeletedOrders removes the orders soft-deleted before deletedBefore for good,
+	// together with their items and idempotency records, and returns their number.
+	PurgeDeletedOrders(ctx context.Context, deletedBefore time.Time) (int64, error)
+
+	// Every order change above records an entity.OrderEvent in the same transaction.
+
+	// ClaimOrderEvents returns up to limit unpublished events that are due at now, ordered by ID,
+	// and postpones them by lease so that concurrent relays do not pick them up.
+	// Only the oldest unpublished event of each order is returned.
+	ClaimOrderEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entity.OrderEvent, error)
+	MarkOrderEventPublished(ctx context.Context, id int64, publishedAt time.Time) error
+	// MarkOrderEventFailed counts a failed delivery and schedules the next attempt.
+	MarkOrderEventFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+	// PurgePublishedOrderEvents removes the events published before publishedBefore, together
+	// with their webhook deliveries, and returns their number. Events with pending deliveries are kept.
+	PurgePublishedOrderEvents(ctx context.Context, publishedBefore time.Time) (int64, error)
+	// ListOrderEvents returns up to limit events with an ID greater than afterID, ordered by ID,
+	// whether they are published or not.
+	ListOrderEvents(ctx context.Context, afterID int64, limit int) ([]entity.OrderEvent, error)
+	// SubscribeOrderEvents returns a subscription to the events recorded from now on. Events
+	// arrive in commit order, which may differ from ID order for events of different orders.
+	SubscribeOrderEvents(ctx context.Context) (*OrderEventSubscription, error)
+
+	CreateWebhookSubscription(ctx context.Context, subscription *entity.WebhookSubscription) error
+	ListWebhookSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error)
+	// DeleteWebhookSubscription removes the subscription together with its deliveries.
+	DeleteWebhookSubscription(ctx context.Context, id string) error
+	// EnqueueWebhookDeliveries creates a pending delivery of the event for every subscription
+	// that matches its type and returns the number of deliveries created. Enqueueing the
+	// same event again creates no new deliveries.
+	EnqueueWebhookDeliveries(ctx context.Context, event entity.OrderEvent, now time.Time) (int64, error)
+	// ClaimWebhookDeliveries returns up to limit pending deliveries that are due at now
+	// and postpones them by lease so that concurrent workers do not pick them up.
+	ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]ClaimedWebhookDelivery, error)
+	MarkWebhookDelivered(ctx context.Context, id int64, deliveredAt time.Time) error
+	// MarkWebhookDeliveryFailed counts a failed attempt and schedules the next one.
+	MarkWebhookDeliveryFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+	// MarkWebhookDeliveryDead counts a failed attempt and stops retrying the delivery.
+	MarkWebhookDeliveryDead(ctx context.Context, id int64, lastError string) error
+	ListWebhookDeliveries(ctx context.Context, query WebhookDeliveryQuery) (*WebhookDeliveryPage, error)
+	// ReplayWebhookDelivery makes a dead delivery pending again with no attempts, due at now.
+	// It returns ErrWebhookDeliveryNotDead if the delivery is not dead.
+	ReplayWebhookDelivery(ctx context.Context, id int64, now time.Time) (*entity.WebhookDelivery, error)
+
+	// Ping checks that the store can serve requests.
+	Ping(ctx context.Context) error
 	Close() error
 }
 
+// orderColumns selects the currency before the amount, since entity.Money
+// needs to know the currency to scan the amount.
+const orderColumns = `id, user_id, currency AS "amount.currency", amount, status, created_at,
+	deleted_at, cancel_reason, cancel_comment, cancelled_by, cancelled_at`
+
 type PostgresStore struct {
-	db *sqlx.DB
+	db       *sqlx.DB
+	connStr  string
+	migrator *migrations.Migrator
+
+	// hub fans out the events announced on orderEventsChannel. The listener
+	// is only started by the first SubscribeOrderEvents call.
+	hub      *OrderEventHub
+	listenMu sync.Mutex
+	listener *pq.Listener
+	closed   bool
 }
 
-func NewPostgresStore(connStr string) (*PostgresStore, error) {
+// PostgresPool configures the connection pool of PostgresStore, zero values keep the database/sql defaults.
+type PostgresPool struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+func NewPostgresStore(connStr string, pool PostgresPool) (*PostgresStore, error) {
 	db, err := sqlx.Open("postgres", connStr)
 	if err != nil {
 		return nil, err
 	}
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	if pool.MaxIdleConns > 0 {
+		// Zero would disable idle connections rather than keep the default.
+		db.SetMaxIdleConns(pool.MaxIdleConns)
+	}
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
 
 	if err := db.Ping(); err != nil {
 		return nil, err
 	}
 
-	if err := createSchema(db); err != nil {
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrator.Up(context.Background()); err != nil {
 		return nil, err
 	}
 
-	return &PostgresStore{db: db}, nil
+	return &PostgresStore{db: db, connStr: connStr, migrator: migrator, hub: NewOrderEventHub()}, nil
+}
+
+// DB returns the connection pool, e.g. to monitor its statistics.
+func (s *PostgresStore) DB() *sql.DB {
+	return s.db.DB
 }
 
-func createSchema(db *sqlx.DB) error {
-	query := `
-		CREATE TABLE IF NOT EXISTS orders (
-			id VARCHAR(36) PRIMARY KEY,
-			user_id VARCHAR(255) NOT NULL,
-			item VARCHAR(255) NOT NULL,
-			amount DECIMAL(10, 2) NOT NULL,
-			status VARCHAR(50) NOT NULL,
-			created_at TIMESTAMP NOT NULL
-		)
-	`
-	_, err := db.Exec(query)
-	return err
+func (s *PostgresStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// CheckMigrations returns an error if a migration known to this binary is not
+// applied, e.g. because the schema was rolled back after the store was created.
+func (s *PostgresStore) CheckMigrations(ctx context.Context) error {
+	pending, err := s.migrator.Pending(ctx)
+	if err != nil {
+		return fmt.Errorf("check migrations: %w", err)
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%d migrations are not applied, the first is %d_%s",
+			len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
 }
 
 func (s *PostgresStore) Create(ctx context.Context, order *entity.Order) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertOrder(ctx, tx, order); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *PostgresStore) CreateWithIdempotencyKey(
+	ctx context.Context,
+	order *entity.Order,
+	record IdempotencyRecord,
+) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertOrder(ctx, tx, order); err != nil {
+		return err
+	}
+
+	// An expired record with the same key is taken over, an unexpired one is kept.
 	const query = `
-		INSERT INTO orders (id, user_id, item, amount, status, created_at)
-		VALUES (:id, :user_id, :item, :amount, :status, :created_at)`
-	_, err := s.db.NamedExecContext(ctx, query, order)
-	return err
+		INSERT INTO idempotency_keys (user_id, key, fingerprint, order_id, created_at, expires_at)
+		VALUES (:user_id, :key, :fingerprint, :order_id, :created_at, :expires_at)
+		ON CONFLICT (user_id, key) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint,
+			order_id = EXCLUDED.order_id,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at`
+	res, err := namedExecContext(ctx, tx, "idempotency_keys.upsert", query, record)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrIdempotencyKeyExists
+	}
+
+	return tx.Commit()
+}
+
+// insertOrder inserts the order with its items and records the OrderCreated event.
+func insertOrder(ctx context.Context, tx *sqlx.Tx, order *entity.Order) error {
+	const query = `
+		INSERT INTO orders (id, user_id, amount, currency, status, created_at)
+		VALUES (:id, :user_id, :amount, :amount.currency, :status, :created_at)`
+	_, err := namedExecContext(ctx, tx, "orders.insert", query, order)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation {
+		return ErrOrderAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+	if err := insertOrderItems(ctx, tx, order); err != nil {
+		return err
+	}
+
+	event, err := entity.NewOrderEvent(entity.OrderEventCreated, order, "", time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	return insertOrderEvent(ctx, tx, event)
+}
+
+func (s *PostgresStore) GetIdempotencyRecord(
+	ctx context.Context,
+	userID, key string,
+	now time.Time,
+) (*IdempotencyRecord, error) {
+	const query = `
+		SELECT user_id, key, fingerprint, order_id, created_at, expires_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND key = $2 AND expires_at > $3`
+	var record IdempotencyRecord
+	err := getContext(ctx, s.db, "idempotency_keys.get", &record, query, userID, key, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrIdempotencyKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (s *PostgresStore) DeleteExpiredIdempotencyRecords(ctx context.Context, now time.Time) (int64, error) {
+	const query = `DELETE FROM idempotency_keys WHERE expires_at <= $1`
+	res, err := execContext(ctx, s.db, "idempotency_keys.delete_expired", query, now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
 }
 
 func (s *PostgresStore) Get(ctx context.Context, id string) (*entity.Order, error) {
-	const query = `SELECT id, user_id, item, amount, status, created_at FROM orders WHERE id = $1`
+	const query = `SELECT ` + orderColumns + ` FROM orders WHERE id = $1 AND deleted_at IS NULL`
+	return s.get(ctx, "orders.get", query, id)
+}
+
+func (s *PostgresStore) GetIncludingDeleted(ctx context.Context, id string) (*entity.Order, error) {
+	const query = `SELECT ` + orderColumns + ` FROM orders WHERE id = $1`
+	return s.get(ctx, "orders.get_including_deleted", query, id)
+}
+
+// get returns the order selected by query. name is the name of the statement in traces.
+func (s *PostgresStore) get(ctx context.Context, name, query, id string) (*entity.Order, error) {
 	var order entity.Order
-	err := s.db.GetContext(ctx, &order, query, id)
+	err := getContext(ctx, s.db, name, &order, query, id)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrOrderNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
+	if err := loadOrderItems(ctx, s.db, &order); err != nil {
+		return nil, err
+	}
 	return &order, nil
 }
 
-func (s *PostgresStore) List(ctx context.Context) ([]*entity.Order, error) {
-	const query = `SELECT id, user_id, item, amount, status, created_at FROM orders ORDER BY created_at DESC`
+func (s *PostgresStore) List(ctx context.Context, query ListQuery) (*ListPage, error) {
+	cursor, err := decodePageToken(query.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		conds []string
+		args  []any
+	)
+	where := func(cond string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, v := range values {
+			args = append(args, v)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conds = append(conds, fmt.Sprintf(cond, placeholders...))
+	}
+
+	if !query.IncludeDeleted {
+		where("deleted_at IS NULL")
+	}
+	if cursor != nil {
+		where("(created_at, id) < (%s, %s)", cursor.CreatedAt, cursor.ID)
+	}
+	if query.UserID != "" {
+		where("user_id = %s", query.UserID)
+	}
+	if query.Status != "" {
+		where("status = %s", query.Status)
+	}
+	if query.Currency != "" {
+		where("currency = %s", query.Currency)
+	}
+	if query.MinAmount != nil {
+		where("amount >= %s", *query.MinAmount)
+	}
+	if query.MaxAmount != nil {
+		where("amount <= %s", *query.MaxAmount)
+	}
+	if query.CreatedAfter != nil {
+		// created_at is a TIMESTAMP in UTC, Postgres would drop any other offset.
+		where("created_at >= %s", query.CreatedAfter.UTC())
+	}
+	if query.CreatedBefore != nil {
+		where("created_at < %s", query.CreatedBefore.UTC())
+	}
+
+	sqlQuery := `SELECT ` + orderColumns + ` FROM orders`
+	if len(conds) > 0 {
+		sqlQuery += ` WHERE ` + strings.Join(conds, ` AND `)
+	}
+	// One extra row tells whether there is a next page.
+	args = append(args, query.PageSize+1)
+	sqlQuery += fmt.Sprintf(` ORDER BY created_at DESC, id DESC LIMIT $%d`, len(args))
+
 	var orders []*entity.Order
-	err := s.db.SelectContext(ctx, &orders, query)
+	if err := selectContext(ctx, s.db, "orders.list", &orders, sqlQuery, args...); err != nil {
+		return nil, err
+	}
+	page := newListPage(orders, query.PageSize)
+	if err := loadOrderItems(ctx, s.db, page.Orders...); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (s *PostgresStore) UpdateStatus(
+	ctx context.Context,
+	id string,
+	from, to entity.OrderStatus,
+) (*entity.Order, error) {
+	const query = `
+		UPDATE orders SET status = $3
+		WHERE id = $1 AND status = $2 AND deleted_at IS NULL
+		RETURNING ` + orderColumns
+	return s.update(ctx, id, from, entity.OrderEventStatusChanged, "orders.update_status", query, id, from, to)
+}
+
+func (s *PostgresStore) Cancel(
+	ctx context.Context,
+	id string,
+	from entity.OrderStatus,
+	cancellation entity.OrderCancellation,
+) (*entity.Order, error) {
+	const query = `
+		UPDATE orders
+		SET status = $3, cancel_reason = $4, cancel_comment = $5, cancelled_by = $6, cancelled_at = $7
+		WHERE id = $1 AND status = $2 AND deleted_at IS NULL
+		RETURNING ` + orderColumns
+	return s.update(ctx, id, from, entity.OrderEventCancelled, "orders.cancel", query,
+		id,
+		from,
+		entity.OrderStatusCancelled,
+		cancellation.CancelReason,
+		cancellation.CancelComment,
+		cancellation.CancelledBy,
+		cancellation.CancelledAt,
+	)
+}
+
+// update runs a compare-and-set UPDATE of the order in status `from` that
+// returns orderColumns, and records the event of the given type with it.
+// name is the name of the statement in traces.
+func (s *PostgresStore) update(
+	ctx context.Context,
+	id string,
+	from entity.OrderStatus,
+	eventType entity.OrderEventType,
+	name string,
+	query string,
+	args ...any,
+) (*entity.Order, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var order entity.Order
+	err = getContext(ctx, tx, name, &order, query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, s.updateStatusMissError(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := loadOrderItems(ctx, tx, &order); err != nil {
+		return nil, err
+	}
+
+	event, err := entity.NewOrderEvent(eventType, &order, from, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if err := insertOrderEvent(ctx, tx, event); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// updateStatusMissError tells apart a missing order from an order whose
+// status no longer matches the expected one.
+func (s *PostgresStore) updateStatusMissError(ctx context.Context, id string) error {
+	const query = `SELECT EXISTS (SELECT 1 FROM orders WHERE id = $1 AND deleted_at IS NULL)`
+	var exists bool
+	if err := getContext(ctx, s.db, "orders.exists", &exists, query, id); err != nil {
+		return err
+	}
+	if !exists {
+		return ErrOrderNotFound
+	}
+	return ErrOrderStatusConflict
+}
+
+func (s *PostgresStore) Delete(ctx context.Context, id string, deletedAt time.Time) (*entity.Order, error) {
+	const query = `
+		UPDATE orders SET deleted_at = $2
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING ` + orderColumns
+	order, err := s.updateDeletion(ctx, entity.OrderEventDeleted, "orders.delete", query, id, deletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrOrderNotFound
+	}
+	return order, err
+}
+
+func (s *PostgresStore) Restore(ctx context.Context, id string) (*entity.Order, error) {
+	const query = `
+		UPDATE orders SET deleted_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING ` + orderColumns
+	order, err := s.updateDeletion(ctx, entity.OrderEventRestored, "orders.restore", query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, s.restoreMissError(ctx, id)
+	}
+	return order, err
+}
+
+// updateDeletion runs an UPDATE of deleted_at that returns orderColumns, and
+// records the event of the given type with it. It returns sql.ErrNoRows if no
+// order was updated. name is the name of the statement in traces.
+func (s *PostgresStore) updateDeletion(
+	ctx context.Context,
+	eventType entity.OrderEventType,
+	name string,
+	query string,
+	args ...any,
+) (*entity.Order, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var order entity.Order
+	if err := getContext(ctx, tx, name, &order, query, args...); err != nil {
+		return nil, err
+	}
+	if err := loadOrderItems(ctx, tx, &order); err != nil {
+		return nil, err
+	}
+
+	event, err := entity.NewOrderEvent(eventType, &order, "", time.Now().UTC())
 	if err != nil {
 		return nil, err
 	}
-	return orders, nil
+	if err := insertOrderEvent(ctx, tx, event); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// restoreMissError tells apart a missing order from an order that is not deleted.
+func (s *PostgresStore) restoreMissError(ctx context.Context, id string) error {
+	const query = `SELECT EXISTS (SELECT 1 FROM orders WHERE id = $1)`
+	var exists bool
+	if err := getContext(ctx, s.db, "orders.exists", &exists, query, id); err != nil {
+		return err
+	}
+	if !exists {
+		return ErrOrderNotFound
+	}
+	return ErrOrderNotDeleted
+}
+
+// PurgeDeletedOrders relies on the items and idempotency records being deleted by cascade.
+// The order events are kept, since they may still have to be delivered.
+func (s *PostgresStore) PurgeDeletedOrders(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	const query = `DELETE FROM orders WHERE deleted_at < $1`
+	res, err := execContext(ctx, s.db, "orders.purge_deleted", query, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
 }
 
 func (s *PostgresStore) Close() error {
+	s.listenMu.Lock()
+	s.closed = true
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	s.listenMu.Unlock()
+
+	s.hub.Close()
 	return s.db.Close()
 }
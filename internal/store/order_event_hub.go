@@ -0,0 +1,108 @@
+package store
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/demo/order/internal/entity"
+)
+
+// ErrOrderEventSubscriptionLagged is reported by a subscription that fell too
+// far behind and was dropped. The subscriber is expected to catch up with
+// ListOrderEvents and subscribe again.
+var ErrOrderEventSubscriptionLagged = errors.New("order event subscription fell behind")
+
+// orderEventBufferSize is the number of events a subscriber may fall behind before it is dropped.
+const orderEventBufferSize = 256
+
+// OrderEventHub fans out recorded order events to in-process subscribers. A
+// slow subscriber never blocks the publisher: it is dropped instead.
+type OrderEventHub struct {
+	mu          sync.Mutex
+	subscribers map[*OrderEventSubscription]struct{}
+	closed      bool
+}
+
+func NewOrderEventHub() *OrderEventHub {
+	return &OrderEventHub{subscribers: make(map[*OrderEventSubscription]struct{})}
+}
+
+// Subscribe returns a subscription to the events published from now on.
+func (h *OrderEventHub) Subscribe() *OrderEventSubscription {
+	sub := &OrderEventSubscription{
+		hub:    h,
+		events: make(chan entity.OrderEvent, orderEventBufferSize),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(sub.events)
+		return sub
+	}
+	h.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Publish hands the event to every subscriber.
+func (h *OrderEventHub) Publish(event entity.OrderEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers {
+		select {
+		case sub.events <- copyOrderEvent(event):
+		default:
+			sub.err = ErrOrderEventSubscriptionLagged
+			h.remove(sub)
+		}
+	}
+}
+
+// Close ends all subscriptions.
+func (h *OrderEventHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subscribers {
+		h.remove(sub)
+	}
+}
+
+// remove closes the subscription channel. h.mu must be held.
+func (h *OrderEventHub) remove(sub *OrderEventSubscription) {
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// OrderEventSubscription receives the events published to an OrderEventHub.
+type OrderEventSubscription struct {
+	hub    *OrderEventHub
+	events chan entity.OrderEvent
+	// err is set under hub.mu before events is closed.
+	err error
+}
+
+// Events is closed when the subscription ends; Err tells why.
+func (s *OrderEventSubscription) Events() <-chan entity.OrderEvent {
+	return s.events
+}
+
+// Err returns ErrOrderEventSubscriptionLagged if the subscriber was dropped for
+// falling behind, and nil if the subscription was closed.
+func (s *OrderEventSubscription) Err() error {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.err
+}
+
+// Close ends the subscription. It is safe to call more than once.
+func (s *OrderEventSubscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
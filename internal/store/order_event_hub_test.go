@@ -0,0 +1,63 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/demo/order/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderEventHub_FansOut(t *testing.T) {
+	hub := NewOrderEventHub()
+	first, second := hub.Subscribe(), hub.Subscribe()
+
+	hub.Publish(entity.OrderEvent{ID: 1, Payload: []byte(`{}`)})
+
+	for _, sub := range []*OrderEventSubscription{first, second} {
+		event := <-sub.Events()
+		assert.Equal(t, int64(1), event.ID)
+	}
+
+	// A closed subscription no longer receives events.
+	first.Close()
+	first.Close()
+	hub.Publish(entity.OrderEvent{ID: 2})
+	_, ok := <-first.Events()
+	assert.False(t, ok)
+	assert.NoError(t, first.Err())
+	assert.Equal(t, int64(2), (<-second.Events()).ID)
+}
+
+func TestOrderEventHub_DropsLaggingSubscriber(t *testing.T) {
+	hub := NewOrderEventHub()
+	slow, fast := hub.Subscribe(), hub.Subscribe()
+
+	for i := 1; i <= orderEventBufferSize+1; i++ {
+		hub.Publish(entity.OrderEvent{ID: int64(i)})
+		<-fast.Events()
+	}
+
+	var received int
+	for range slow.Events() {
+		received++
+	}
+	assert.Equal(t, orderEventBufferSize, received, "Buffered events should still be received")
+	assert.ErrorIs(t, slow.Err(), ErrOrderEventSubscriptionLagged)
+	assert.NoError(t, fast.Err())
+}
+
+func TestOrderEventHub_Close(t *testing.T) {
+	hub := NewOrderEventHub()
+	sub := hub.Subscribe()
+
+	hub.Close()
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	assert.NoError(t, sub.Err())
+
+	late := hub.Subscribe()
+	_, ok = <-late.Events()
+	require.False(t, ok, "Subscription to a closed hub should end immediately")
+	hub.Publish(entity.OrderEvent{ID: 1})
+}
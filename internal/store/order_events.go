@@ -0,0 +1,220 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/demo/order/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// orderEventColumns are the columns of entity.OrderEvent in the order_events table.
+const orderEventColumns = `id, order_id, type, payload, created_at, attempts`
+
+// orderEventsChannel is the LISTEN/NOTIFY channel that announces the IDs of recorded events.
+const orderEventsChannel = "order_events"
+
+// insertOrderEvent records the event in the outbox and announces it on
+// orderEventsChannel. It must run in the transaction that changes the order,
+// so that the notification is only sent once the change is committed.
+func insertOrderEvent(ctx context.Context, tx *sqlx.Tx, event entity.OrderEvent) error {
+	const query = `
+		WITH inserted AS (
+			INSERT INTO order_events (order_id, type, payload, created_at, next_attempt_at)
+			VALUES ($1, $2, $3, $4, $4)
+			RETURNING id
+		)
+		SELECT pg_notify($5, id::TEXT) FROM inserted`
+	// The payload is passed as a string, lib/pq would send []byte as bytea.
+	_, err := execContext(ctx, tx, "order_events.insert", query,
+		event.OrderID,
+		event.Type,
+		string(event.Payload),
+		event.CreatedAt,
+		orderEventsChannel,
+	)
+	return err
+}
+
+func (s *PostgresStore) ListOrderEvents(ctx context.Context, afterID int64, limit int) ([]entity.OrderEvent, error) {
+	const query = `SELECT ` + orderEventColumns + ` FROM order_events WHERE id > $1 ORDER BY id LIMIT $2`
+	var events []entity.OrderEvent
+	if err := selectContext(ctx, s.db, "order_events.list", &events, query, afterID, limit); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (s *PostgresStore) SubscribeOrderEvents(ctx context.Context) (*OrderEventSubscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.listenMu.Lock()
+	defer s.listenMu.Unlock()
+
+	// A closed store hands out subscriptions that end immediately.
+	if s.listener == nil && !s.closed {
+		listener, err := s.listen(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.listener = listener
+	}
+	return s.hub.Subscribe(), nil
+}
+
+// listen starts forwarding the events announced on orderEventsChannel to the hub.
+func (s *PostgresStore) listen(ctx context.Context) (*pq.Listener, error) {
+	var lastID int64
+	if err := getContext(ctx, s.db, "order_events.last_id", &lastID, `SELECT COALESCE(MAX(id), 0) FROM order_events`); err != nil {
+		return nil, err
+	}
+
+	listener := pq.NewListener(s.connStr, time.Second, time.Minute, func(_ pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Warn("Order event listener failed", "error", err)
+		}
+	})
+	if err := listener.Listen(orderEventsChannel); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	go s.forwardOrderEvents(listener, lastID)
+	return listener, nil
+}
+
+// forwardOrderEvents publishes announced events until the listener is closed.
+// lastID is the highest event ID seen, used to catch up after a reconnect.
+func (s *PostgresStore) forwardOrderEvents(listener *pq.Listener, lastID int64) {
+	for notification := range listener.Notify {
+		if notification == nil {
+			// The connection was re-established and notifications sent meanwhile are lost.
+			lastID = s.publishOrderEventsAfter(lastID)
+			continue
+		}
+
+		id, err := strconv.ParseInt(notification.Extra, 10, 64)
+		if err != nil {
+			slog.Warn("Unexpected order event notification", "payload", notification.Extra)
+			continue
+		}
+		// Events are fetched by ID rather than after lastID, since concurrent
+		// transactions may commit events out of ID order.
+		var event entity.OrderEvent
+		const query = `SELECT ` + orderEventColumns + ` FROM order_events WHERE id = $1`
+		if err := s.db.Get(&event, query, id); err != nil {
+			slog.Error("Failed to load order event", "event_id", id, "error", err)
+			continue
+		}
+		s.hub.Publish(event)
+		lastID = max(lastID, id)
+	}
+}
+
+// publishOrderEventsAfter publishes all events after lastID and returns the new highest ID.
+func (s *PostgresStore) publishOrderEventsAfter(lastID int64) int64 {
+	const batchSize = 500
+	for {
+		events, err := s.ListOrderEvents(context.Background(), lastID, batchSize)
+		if err != nil {
+			slog.Error("Failed to catch up on order events", "error", err)
+			return lastID
+		}
+		for _, event := range events {
+			s.hub.Publish(event)
+			lastID = event.ID
+		}
+		if len(events) < batchSize {
+			return lastID
+		}
+	}
+}
+
+func (s *PostgresStore) ClaimOrderEvents(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]entity.OrderEvent, error) {
+	// Only the oldest unpublished event of an order is due, so events of an
+	// order are delivered in order. SKIP LOCKED lets relays run concurrently.
+	const query = `
+		WITH due AS (
+			SELECT e.id FROM order_events e
+			WHERE e.published_at IS NULL AND e.next_attempt_at <= $1
+				AND NOT EXISTS (
+					SELECT 1 FROM order_events p
+					WHERE p.order_id = e.order_id AND p.published_at IS NULL AND p.id < e.id
+				)
+			ORDER BY e.id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE order_events SET next_attempt_at = $2
+		FROM due
+		WHERE order_events.id = due.id
+		RETURNING order_events.` + orderEventColumns
+	var events []entity.OrderEvent
+	if err := selectContext(ctx, s.db, "order_events.claim", &events, query, now, now.Add(lease), limit); err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events, nil
+}
+
+func (s *PostgresStore) MarkOrderEventPublished(ctx context.Context, id int64, publishedAt time.Time) error {
+	const query = `UPDATE order_events SET published_at = $2, last_error = '' WHERE id = $1`
+	res, err := execContext(ctx, s.db, "order_events.mark_published", query, id, publishedAt)
+	if err != nil {
+		return err
+	}
+	return orderEventAffected(res)
+}
+
+func (s *PostgresStore) MarkOrderEventFailed(
+	ctx context.Context,
+	id int64,
+	nextAttemptAt time.Time,
+	lastError string,
+) error {
+	const query = `
+		UPDATE order_events SET attempts = attempts + 1, next_attempt_at = $2, last_error = $3
+		WHERE id = $1 AND published_at IS NULL`
+	res, err := execContext(ctx, s.db, "order_events.mark_failed", query, id, nextAttemptAt, lastError)
+	if err != nil {
+		return err
+	}
+	return orderEventAffected(res)
+}
+
+// PurgePublishedOrderEvents relies on the webhook deliveries being deleted by cascade.
+func (s *PostgresStore) PurgePublishedOrderEvents(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	const query = `
+		DELETE FROM order_events e
+		WHERE e.published_at < $1
+			AND NOT EXISTS (
+				SELECT 1 FROM webhook_deliveries d WHERE d.event_id = e.id AND d.status = $2
+			)`
+	res, err := execContext(ctx, s.db, "order_events.purge_published", query, publishedBefore, entity.WebhookDeliveryPending)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func orderEventAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrOrderEventNotFound
+	}
+	return nil
+}
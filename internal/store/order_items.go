@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+
+	"github.com/demo/order/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// orderItemRow is an entity.OrderItem as stored in the order_items table.
+type orderItemRow struct {
+	OrderID  string `db:"order_id"`
+	Position int    `db:"position"`
+	entity.OrderItem
+}
+
+func insertOrderItems(ctx context.Context, db sqlx.ExtContext, order *entity.Order) error {
+	if len(order.Items) == 0 {
+		return nil
+	}
+
+	rows := make([]orderItemRow, len(order.Items))
+	for i, item := range order.Items {
+		rows[i] = orderItemRow{OrderID: order.ID, Position: i, OrderItem: item}
+	}
+
+	const query = `
+		INSERT INTO order_items (order_id, position, sku, name, quantity, unit_price)
+		VALUES (:order_id, :position, :sku, :name, :quantity, :unit_price)`
+	_, err := namedExecContext(ctx, db, "order_items.insert", query, rows)
+	return err
+}
+
+// loadOrderItems fills in the items of all orders with a single query.
+func loadOrderItems(ctx context.Context, db sqlx.QueryerContext, orders ...*entity.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*entity.Order, len(orders))
+	ids := make([]string, len(orders))
+	for i, order := range orders {
+		byID[order.ID] = order
+		ids[i] = order.ID
+		order.Items = nil
+	}
+
+	// The currency is selected before the unit price, see orderColumns.
+	const query = `
+		SELECT i.order_id, i.position, i.sku, i.name, i.quantity,
+			o.currency AS "unit_price.currency", i.unit_price
+		FROM order_items i
+		JOIN orders o ON o.id = i.order_id
+		WHERE i.order_id = ANY($1)
+		ORDER BY i.order_id, i.position`
+	var rows []orderItemRow
+	if err := selectContext(ctx, db, "order_items.list", &rows, query, pq.Array(ids)); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		order := byID[row.OrderID]
+		order.Items = append(order.Items, row.OrderItem)
+	}
+	return nil
+}
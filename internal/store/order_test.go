@@ -0,0 +1,23 @@
+package store_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/demo/order/internal/store"
+	"github.com/demo/order/internal/store/storetest"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestPostgresStore(t *testing.T) {
+	connStr := os.Getenv("TEST_DATABASE_URL")
+	if connStr == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	suite.Run(t, &storetest.OrderStoreSuite{
+		NewStore: func() (store.OrderStore, error) {
+			return store.NewPostgresStore(connStr, store.PostgresPool{})
+		},
+	})
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/demo/order/internal/store")
+
+// The statements of PostgresStore run through the functions below. They wrap
+// their sqlx counterparts and trace every statement in a span named after it,
+// e.g. "orders.get". Statements outside of a trace are not traced.
+
+func getContext(ctx context.Context, q sqlx.QueryerContext, name string, dest any, query string, args ...any) error {
+	ctx, span := startStatement(ctx, name, query)
+	err := sqlx.GetContext(ctx, q, dest, query, args...)
+	endStatement(span, err)
+	return err
+}
+
+func selectContext(ctx context.Context, q sqlx.QueryerContext, name string, dest any, query string, args ...any) error {
+	ctx, span := startStatement(ctx, name, query)
+	err := sqlx.SelectContext(ctx, q, dest, query, args...)
+	endStatement(span, err)
+	return err
+}
+
+func execContext(ctx context.Context, e sqlx.ExecerContext, name string, query string, args ...any) (sql.Result, error) {
+	ctx, span := startStatement(ctx, name, query)
+	res, err := e.ExecContext(ctx, query, args...)
+	endStatement(span, err)
+	return res, err
+}
+
+func namedExecContext(ctx context.Context, e sqlx.ExtContext, name string, query string, arg any) (sql.Result, error) {
+	ctx, span := startStatement(ctx, name, query)
+	res, err := sqlx.NamedExecContext(ctx, e, query, arg)
+	endStatement(span, err)
+	return res, err
+}
+
+func startStatement(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBStatement(query)),
+	)
+}
+
+// endStatement ends the span of a statement. No rows is an outcome rather than a failure.
+func endStatement(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,1121 @@
+// Package storetest provides a conformance suite that every store.OrderStore
+// implementation must pass.
+package storetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// OrderStoreSuite checks the behavior shared by all OrderStore implementations.
+// Tests only look at orders of users they generate themselves, so the suite can
+// run against a store that already holds data.
+//
+// Usage:
+//
+//	suite.Run(t, &storetest.OrderStoreSuite{NewStore: ...})
+type OrderStoreSuite struct {
+	suite.Suite
+
+	// NewStore creates the store under test. It is called before every test.
+	NewStore func() (store.OrderStore, error)
+
+	store store.OrderStore
+}
+
+func (s *OrderStoreSuite) SetupTest() {
+	orderStore, err := s.NewStore()
+	s.Require().NoError(err, "Failed to create store")
+	s.store = orderStore
+}
+
+func (s *OrderStoreSuite) TearDownTest() {
+	s.Require().NoError(s.store.Close())
+}
+
+func (s *OrderStoreSuite) TestCreateGet_RoundTrip() {
+	ctx := context.Background()
+	order := s.newOrder(s.newUserID(), time.Date(2024, 1, 15, 10, 30, 0, 123456000, time.UTC))
+
+	s.Require().NoError(s.store.Create(ctx, order))
+
+	got, err := s.store.Get(ctx, order.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(order.ID, got.ID)
+	s.Require().Equal(order.UserID, got.UserID)
+	s.Require().Equal(order.Items, got.Items)
+	s.Require().Equal(order.Amount, got.Amount)
+	s.Require().Equal(order.Status, got.Status)
+	s.Require().True(order.CreatedAt.Equal(got.CreatedAt), "CreatedAt %v should equal %v", got.CreatedAt, order.CreatedAt)
+	s.Require().Equal(entity.OrderCancellation{}, got.OrderCancellation)
+}
+
+func (s *OrderStoreSuite) TestGet_NotFound() {
+	_, err := s.store.Get(context.Background(), uuid.New().String())
+
+	s.Require().ErrorIs(err, store.ErrOrderNotFound)
+}
+
+func (s *OrderStoreSuite) TestCreate_DuplicateID() {
+	ctx := context.Background()
+	order := s.newOrder(s.newUserID(), time.Now())
+	s.Require().NoError(s.store.Create(ctx, order))
+
+	duplicate := s.newOrder(s.newUserID(), time.Now())
+	duplicate.ID = order.ID
+	err := s.store.Create(ctx, duplicate)
+
+	s.Require().ErrorIs(err, store.ErrOrderAlreadyExists)
+}
+
+func (s *OrderStoreSuite) TestCreate_AmountPrecision() {
+	ctx := context.Background()
+	userID := s.newUserID()
+
+	amounts := []entity.Money{
+		entity.NewMoney(1, "EUR"),
+		entity.NewMoney(10005, "EUR"),
+		entity.MaxMoney("EUR"),
+		entity.NewMoney(1234, "JPY"),
+		entity.MaxMoney("JPY"),
+		entity.NewMoney(10005, "KWD"),
+		entity.MaxMoney("KWD"),
+		entity.MaxMoney("CLF"),
+	}
+
+	for _, amount := range amounts {
+		s.Run(strings.ReplaceAll(amount.String(), " ", "_"), func() {
+			order := s.newOrder(userID, time.Now())
+			order.Amount = amount
+			s.Require().NoError(s.store.Create(ctx, order))
+
+			got, err := s.store.Get(ctx, order.ID)
+			s.Require().NoError(err)
+			s.Require().Equal(order.Amount, got.Amount)
+		})
+	}
+
+	s.Run("overflow", func() {
+		order := s.newOrder(userID, time.Now())
+		order.Amount = entity.MaxMoney("EUR")
+		order.Amount.MinorUnits++
+		s.Require().Error(s.store.Create(ctx, order))
+	})
+
+	s.Run("unknown_currency", func() {
+		order := s.newOrder(userID, time.Now())
+		order.Amount.Currency = "XXX"
+		s.Require().Error(s.store.Create(ctx, order))
+	})
+}
+
+func (s *OrderStoreSuite) TestCreate_Items() {
+	ctx := context.Background()
+	userID := s.newUserID()
+
+	order := s.newOrder(userID, time.Now())
+	order.Items = []entity.OrderItem{
+		{SKU: "SKU-B", Name: "Second", Quantity: 3, UnitPrice: entity.NewMoney(1005, "KWD")},
+		{SKU: "SKU-A", Name: "First", Quantity: 1, UnitPrice: entity.NewMoney(1, "KWD")},
+		{SKU: "SKU-B", Name: "Second again", Quantity: 2, UnitPrice: entity.NewMoney(1005, "KWD")},
+	}
+	order.Amount = entity.NewMoney(5026, "KWD")
+	other := s.newOrder(userID, time.Now().Add(-time.Hour))
+	s.Require().NoError(s.store.Create(ctx, order))
+	s.Require().NoError(s.store.Create(ctx, other))
+
+	got, err := s.store.Get(ctx, order.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(order.Items, got.Items, "Items should keep their order and currency")
+
+	page, err := s.store.List(ctx, store.ListQuery{PageSize: 10, UserID: userID})
+	s.Require().NoError(err)
+	s.Require().Len(page.Orders, 2)
+	s.Require().Equal(order.Items, page.Orders[0].Items)
+	s.Require().Equal(other.Items, page.Orders[1].Items)
+
+	updated, err := s.store.UpdateStatus(ctx, order.ID, entity.OrderStatusNew, entity.OrderStatusInProgress)
+	s.Require().NoError(err)
+	s.Require().Equal(order.Items, updated.Items)
+}
+
+func (s *OrderStoreSuite) TestList_Ordering() {
+	ctx := context.Background()
+	userID := s.newUserID()
+	base := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	oldest := s.newOrder(userID, base)
+	newest := s.newOrder(userID, base.Add(2*time.Hour))
+	// Orders created at the same time are ordered by ID descending.
+	tieLow := s.newOrder(userID, base.Add(time.Hour))
+	tieLow.ID = "00000000-0000-0000-0000-" + userID[24:]
+	tieHigh := s.newOrder(userID, base.Add(time.Hour))
+	tieHigh.ID = "ffffffff-ffff-ffff-ffff-" + userID[24:]
+
+	for _, o := range []*entity.Order{tieLow, oldest, newest, tieHigh} {
+		s.Require().NoError(s.store.Create(ctx, o))
+	}
+
+	page, err := s.store.List(ctx, store.ListQuery{PageSize: 10, UserID: userID})
+	s.Require().NoError(err)
+	s.Require().Empty(page.NextPageToken)
+	s.Require().Equal([]string{newest.ID, tieHigh.ID, tieLow.ID, oldest.ID}, orderIDs(page.Orders))
+}
+
+func (s *OrderStoreSuite) TestList_Pagination() {
+	ctx := context.Background()
+	userID := s.newUserID()
+	base := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	var want []string
+	for i := 4; i >= 0; i-- {
+		order := s.newOrder(userID, base.Add(time.Duration(i)*time.Minute))
+		s.Require().NoError(s.store.Create(ctx, order))
+		want = append(want, order.ID)
+	}
+
+	var (
+		got       []string
+		pageToken string
+		pages     int
+	)
+	for {
+		page, err := s.store.List(ctx, store.ListQuery{PageSize: 2, PageToken: pageToken, UserID: userID})
+		s.Require().NoError(err)
+		s.Require().LessOrEqual(len(page.Orders), 2)
+		got = append(got, orderIDs(page.Orders)...)
+		pages++
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+
+	s.Require().Equal(3, pages)
+	s.Require().Equal(want, got)
+}
+
+func (s *OrderStoreSuite) TestList_Filters() {
+	ctx := context.Background()
+	userID := s.newUserID()
+	base := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	cheap := s.newOrder(userID, base)
+	cheap.Amount = entity.NewMoney(500, entity.DefaultCurrency)
+	middle := s.newOrder(userID, base.Add(time.Hour))
+	middle.Amount = entity.NewMoney(5000, entity.DefaultCurrency)
+	middle.Status = entity.OrderStatusInProgress
+	expensive := s.newOrder(userID, base.Add(2*time.Hour))
+	expensive.Amount = entity.NewMoney(50000, entity.DefaultCurrency)
+	// 5 000 yen is 5000 in major units and compares as such.
+	yen := s.newOrder(userID, base.Add(3*time.Hour))
+	yen.Amount = entity.NewMoney(5000, "JPY")
+	for _, o := range []*entity.Order{cheap, middle, expensive, yen} {
+		s.Require().NoError(s.store.Create(ctx, o))
+	}
+
+	minAmount, maxAmount := cheap.Amount, middle.Amount
+	createdAfter, createdBefore := base.Add(time.Hour), base.Add(2*time.Hour)
+	// The same bounds three hours east of UTC.
+	eastern := time.FixedZone("UTC+3", 3*60*60)
+	createdAfterEastern, createdBeforeEastern := createdAfter.In(eastern), createdBefore.In(eastern)
+
+	testCases := []struct {
+		name  string
+		query store.ListQuery
+		want  []string
+	}{
+		{
+			name:  "status",
+			query: store.ListQuery{Status: entity.OrderStatusInProgress},
+			want:  []string{middle.ID},
+		},
+		{
+			name:  "amount_range_inclusive",
+			query: store.ListQuery{MinAmount: &minAmount, MaxAmount: &maxAmount},
+			want:  []string{middle.ID, cheap.ID},
+		},
+		{
+			name:  "currency",
+			query: store.ListQuery{Currency: "JPY"},
+			want:  []string{yen.ID},
+		},
+		{
+			name:  "currency_and_amount",
+			query: store.ListQuery{Currency: "EUR", MinAmount: &middle.Amount},
+			want:  []string{expensive.ID, middle.ID},
+		},
+		{
+			name:  "created_range_after_inclusive_before_exclusive",
+			query: store.ListQuery{CreatedAfter: &createdAfter, CreatedBefore: &createdBefore},
+			want:  []string{middle.ID},
+		},
+		{
+			name:  "created_range_non_utc",
+			query: store.ListQuery{CreatedAfter: &createdAfterEastern, CreatedBefore: &createdBeforeEastern},
+			want:  []string{middle.ID},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			tc.query.PageSize = 10
+			tc.query.UserID = userID
+			page, err := s.store.List(ctx, tc.query)
+			s.Require().NoError(err)
+			s.Require().Equal(tc.want, orderIDs(page.Orders))
+		})
+	}
+}
+
+func (s *OrderStoreSuite) TestList_InvalidPageToken() {
+	_, err := s.store.List(context.Background(), store.ListQuery{PageSize: 10, PageToken: "not-a-token"})
+
+	s.Require().ErrorIs(err, store.ErrInvalidPageToken)
+}
+
+func (s *OrderStoreSuite) TestUpdateStatus() {
+	ctx := context.Background()
+	order := s.newOrder(s.newUserID(), time.Now())
+	s.Require().NoError(s.store.Create(ctx, order))
+
+	updated, err := s.store.UpdateStatus(ctx, order.ID, entity.OrderStatusNew, entity.OrderStatusInProgress)
+	s.Require().NoError(err)
+	s.Require().Equal(entity.OrderStatusInProgress, updated.Status)
+
+	_, err = s.store.UpdateStatus(ctx, order.ID, entity.OrderStatusNew, entity.OrderStatusInProgress)
+	s.Require().ErrorIs(err, store.ErrOrderStatusConflict)
+
+	_, err = s.store.UpdateStatus(ctx, uuid.New().String(), entity.OrderStatusNew, entity.OrderStatusInProgress)
+	s.Require().ErrorIs(err, store.ErrOrderNotFound)
+}
+
+func (s *OrderStoreSuite) TestCancel() {
+	ctx := context.Background()
+	order := s.newOrder(s.newUserID(), time.Now())
+	s.Require().NoError(s.store.Create(ctx, order))
+
+	cancelledAt := time.Date(2024, 1, 16, 8, 0, 0, 0, time.UTC)
+	cancellation := entity.OrderCancellation{
+		CancelReason:  entity.CancelReasonCustomerRequest,
+		CancelComment: "Changed my mind",
+		CancelledBy:   "support-agent",
+		CancelledAt:   &cancelledAt,
+	}
+	cancelled, err := s.store.Cancel(ctx, order.ID, entity.OrderStatusNew, cancellation)
+	s.Require().NoError(err)
+	s.Require().Equal(entity.OrderStatusCancelled, cancelled.Status)
+
+	got, err := s.store.Get(ctx, order.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(entity.OrderStatusCancelled, got.Status)
+	s.Require().Equal(cancellation.CancelReason, got.CancelReason)
+	s.Require().Equal(cancellation.CancelComment, got.CancelComment)
+	s.Require().Equal(cancellation.CancelledBy, got.CancelledBy)
+	s.Require().NotNil(got.CancelledAt)
+	s.Require().True(cancelledAt.Equal(*got.CancelledAt))
+
+	_, err = s.store.Cancel(ctx, order.ID, entity.OrderStatusNew, cancellation)
+	s.Require().ErrorIs(err, store.ErrOrderStatusConflict)
+}
+
+func (s *OrderStoreSuite) TestDeleteRestore() {
+	ctx := context.Background()
+	order := s.newOrder(s.newUserID(), time.Now())
+	s.Require().NoError(s.store.Create(ctx, order))
+
+	deletedAt := time.Date(2024, 1, 17, 9, 0, 0, 0, time.UTC)
+	deleted, err := s.store.Delete(ctx, order.ID, deletedAt)
+	s.Require().NoError(err)
+	s.Require().NotNil(deleted.DeletedAt)
+	s.Require().True(deletedAt.Equal(*deleted.DeletedAt))
+	s.Require().Equal(order.Items, deleted.Items)
+
+	// A deleted order is only returned by GetIncludingDeleted and can no longer change.
+	_, err = s.store.Get(ctx, order.ID)
+	s.Require().ErrorIs(err, store.ErrOrderNotFound)
+	got, err := s.store.GetIncludingDeleted(ctx, order.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(got.DeletedAt)
+	s.Require().True(deletedAt.Equal(*got.DeletedAt))
+	_, err = s.store.Delete(ctx, order.ID, deletedAt)
+	s.Require().ErrorIs(err, store.ErrOrderNotFound)
+	_, err = s.store.UpdateStatus(ctx, order.ID, entity.OrderStatusNew, entity.OrderStatusInProgress)
+	s.Require().ErrorIs(err, store.ErrOrderNotFound)
+	_, err = s.store.Cancel(ctx, order.ID, entity.OrderStatusNew, entity.OrderCancellation{
+		CancelReason: entity.CancelReasonDuplicate,
+		CancelledBy:  "support-agent",
+	})
+	s.Require().ErrorIs(err, store.ErrOrderNotFound)
+
+	restored, err := s.store.Restore(ctx, order.ID)
+	s.Require().NoError(err)
+	s.Require().Nil(restored.DeletedAt)
+	got, err = s.store.Get(ctx, order.ID)
+	s.Require().NoError(err)
+	s.Require().Nil(got.DeletedAt)
+
+	_, err = s.store.Restore(ctx, order.ID)
+	s.Require().ErrorIs(err, store.ErrOrderNotDeleted)
+	_, err = s.store.Restore(ctx, uuid.New().String())
+	s.Require().ErrorIs(err, store.ErrOrderNotFound)
+	_, err = s.store.Delete(ctx, uuid.New().String(), deletedAt)
+	s.Require().ErrorIs(err, store.ErrOrderNotFound)
+
+	// Deleting and restoring are recorded as order events.
+	now := time.Now().UTC()
+	var types []entity.OrderEventType
+	for i := 0; i < 3; i++ {
+		events := s.claimOrderEvents(order.ID, now, time.Minute)
+		s.Require().Len(events, 1)
+		types = append(types, events[0].Type)
+		s.Require().NoError(s.store.MarkOrderEventPublished(ctx, events[0].ID, now))
+	}
+	s.Require().Equal([]entity.OrderEventType{
+		entity.OrderEventCreated, entity.OrderEventDeleted, entity.OrderEventRestored,
+	}, types)
+}
+
+func (s *OrderStoreSuite) TestList_IncludeDeleted() {
+	ctx := context.Background()
+	userID := s.newUserID()
+	base := time.Now().UTC().Truncate(time.Microsecond)
+	kept := s.newOrder(userID, base)
+	deleted := s.newOrder(userID, base.Add(-time.Minute))
+	s.Require().NoError(s.store.Create(ctx, kept))
+	s.Require().NoError(s.store.Create(ctx, deleted))
+	_, err := s.store.Delete(ctx, deleted.ID, base)
+	s.Require().NoError(err)
+
+	page, err := s.store.List(ctx, store.ListQuery{PageSize: 10, UserID: userID})
+	s.Require().NoError(err)
+	s.Require().Len(page.Orders, 1)
+	s.Require().Equal(kept.ID, page.Orders[0].ID)
+
+	page, err = s.store.List(ctx, store.ListQuery{PageSize: 10, UserID: userID, IncludeDeleted: true})
+	s.Require().NoError(err)
+	s.Require().Len(page.Orders, 2)
+	s.Require().Equal(kept.ID, page.Orders[0].ID)
+	s.Require().Nil(page.Orders[0].DeletedAt)
+	s.Require().Equal(deleted.ID, page.Orders[1].ID)
+	s.Require().NotNil(page.Orders[1].DeletedAt)
+}
+
+func (s *OrderStoreSuite) TestPurgeDeletedOrders() {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	userID := s.newUserID()
+
+	expired := s.newOrder(userID, now.Add(-72*time.Hour))
+	s.Require().NoError(s.store.CreateWithIdempotencyKey(ctx, expired, store.IdempotencyRecord{
+		UserID:    expired.UserID,
+		Key:       uuid.New().String(),
+		OrderID:   expired.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}))
+	_, err := s.store.Delete(ctx, expired.ID, now.Add(-48*time.Hour))
+	s.Require().NoError(err)
+	recent := s.newOrder(userID, now.Add(-72*time.Hour))
+	s.Require().NoError(s.store.Create(ctx, recent))
+	_, err = s.store.Delete(ctx, recent.ID, now.Add(-time.Hour))
+	s.Require().NoError(err)
+	live := s.newOrder(userID, now.Add(-72*time.Hour))
+	s.Require().NoError(s.store.Create(ctx, live))
+
+	purged, err := s.store.PurgeDeletedOrders(ctx, now.Add(-24*time.Hour))
+	s.Require().NoError(err)
+	s.Require().GreaterOrEqual(purged, int64(1))
+
+	_, err = s.store.GetIncludingDeleted(ctx, expired.ID)
+	s.Require().ErrorIs(err, store.ErrOrderNotFound)
+	page, err := s.store.List(ctx, store.ListQuery{PageSize: 10, UserID: userID, IncludeDeleted: true})
+	s.Require().NoError(err)
+	s.Require().Len(page.Orders, 2)
+	_, err = s.store.GetIncludingDeleted(ctx, recent.ID)
+	s.Require().NoError(err)
+	_, err = s.store.Get(ctx, live.ID)
+	s.Require().NoError(err)
+}
+
+func (s *OrderStoreSuite) TestCreateWithIdempotencyKey() {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	key := uuid.New().String()
+	order := s.newOrder(s.newUserID(), now)
+	record := store.IdempotencyRecord{
+		UserID:      order.UserID,
+		Key:         key,
+		Fingerprint: "fingerprint",
+		OrderID:     order.ID,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Hour),
+	}
+
+	s.Require().NoError(s.store.CreateWithIdempotencyKey(ctx, order, record))
+
+	got, err := s.store.GetIdempotencyRecord(ctx, order.UserID, key, now)
+	s.Require().NoError(err)
+	s.Require().Equal(order.UserID, got.UserID)
+	s.Require().Equal(key, got.Key)
+	s.Require().Equal("fingerprint", got.Fingerprint)
+	s.Require().Equal(order.ID, got.OrderID)
+	_, err = s.store.Get(ctx, order.ID)
+	s.Require().NoError(err)
+
+	// The key is taken until it expires, and the second order is not created.
+	second := s.newOrder(order.UserID, now)
+	err = s.store.CreateWithIdempotencyKey(ctx, second, store.IdempotencyRecord{
+		UserID:    second.UserID,
+		Key:       key,
+		OrderID:   second.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	})
+	s.Require().ErrorIs(err, store.ErrIdempotencyKeyExists)
+	_, err = s.store.Get(ctx, second.ID)
+	s.Require().ErrorIs(err, store.ErrOrderNotFound)
+
+	_, err = s.store.GetIdempotencyRecord(ctx, order.UserID, key, now.Add(time.Hour))
+	s.Require().ErrorIs(err, store.ErrIdempotencyKeyNotFound, "Expired key should not be found")
+}
+
+func (s *OrderStoreSuite) TestCreateWithIdempotencyKey_ExpiredKeyIsReused() {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	key := uuid.New().String()
+	first := s.newOrder(s.newUserID(), now.Add(-48*time.Hour))
+	s.Require().NoError(s.store.CreateWithIdempotencyKey(ctx, first, store.IdempotencyRecord{
+		UserID:    first.UserID,
+		Key:       key,
+		OrderID:   first.ID,
+		CreatedAt: now.Add(-48 * time.Hour),
+		ExpiresAt: now.Add(-24 * time.Hour),
+	}))
+
+	second := s.newOrder(first.UserID, now)
+	s.Require().NoError(s.store.CreateWithIdempotencyKey(ctx, second, store.IdempotencyRecord{
+		UserID:    second.UserID,
+		Key:       key,
+		OrderID:   second.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}))
+
+	got, err := s.store.GetIdempotencyRecord(ctx, first.UserID, key, now)
+	s.Require().NoError(err)
+	s.Require().Equal(second.ID, got.OrderID)
+}
+
+func (s *OrderStoreSuite) TestCreateWithIdempotencyKey_KeysArePerUser() {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	key := uuid.New().String()
+	first := s.newOrder(s.newUserID(), now)
+	second := s.newOrder(s.newUserID(), now)
+
+	for _, order := range []*entity.Order{first, second} {
+		s.Require().NoError(s.store.CreateWithIdempotencyKey(ctx, order, store.IdempotencyRecord{
+			UserID:    order.UserID,
+			Key:       key,
+			OrderID:   order.ID,
+			CreatedAt: now,
+			ExpiresAt: now.Add(time.Hour),
+		}), "Users should not collide on the same key")
+	}
+
+	for _, order := range []*entity.Order{first, second} {
+		got, err := s.store.GetIdempotencyRecord(ctx, order.UserID, key, now)
+		s.Require().NoError(err)
+		s.Require().Equal(order.ID, got.OrderID)
+	}
+	_, err := s.store.GetIdempotencyRecord(ctx, s.newUserID(), key, now)
+	s.Require().ErrorIs(err, store.ErrIdempotencyKeyNotFound)
+}
+
+func (s *OrderStoreSuite) TestDeleteExpiredIdempotencyRecords() {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	expiredKey := uuid.New().String()
+	liveKey := uuid.New().String()
+
+	expired := s.newOrder(s.newUserID(), now.Add(-48*time.Hour))
+	s.Require().NoError(s.store.CreateWithIdempotencyKey(ctx, expired, store.IdempotencyRecord{
+		UserID:    expired.UserID,
+		Key:       expiredKey,
+		OrderID:   expired.ID,
+		CreatedAt: now.Add(-48 * time.Hour),
+		ExpiresAt: now.Add(-24 * time.Hour),
+	}))
+	live := s.newOrder(expired.UserID, now)
+	s.Require().NoError(s.store.CreateWithIdempotencyKey(ctx, live, store.IdempotencyRecord{
+		UserID:    live.UserID,
+		Key:       liveKey,
+		OrderID:   live.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}))
+
+	deleted, err := s.store.DeleteExpiredIdempotencyRecords(ctx, now)
+	s.Require().NoError(err)
+	s.Require().GreaterOrEqual(deleted, int64(1))
+
+	_, err = s.store.GetIdempotencyRecord(ctx, live.UserID, liveKey, now)
+	s.Require().NoError(err)
+	// Deleting the key keeps the order it created.
+	_, err = s.store.Get(ctx, expired.ID)
+	s.Require().NoError(err)
+}
+
+func (s *OrderStoreSuite) TestOrderEvents_RecordedWithChanges() {
+	ctx := context.Background()
+	order := s.newOrder(s.newUserID(), time.Now())
+	s.Require().NoError(s.store.Create(ctx, order))
+	_, err := s.store.UpdateStatus(ctx, order.ID, entity.OrderStatusNew, entity.OrderStatusInProgress)
+	s.Require().NoError(err)
+	_, err = s.store.Cancel(ctx, order.ID, entity.OrderStatusInProgress, entity.OrderCancellation{
+		CancelReason: entity.CancelReasonOutOfStock,
+		CancelledBy:  "support-agent",
+	})
+	s.Require().NoError(err)
+
+	// Events of an order are claimed one at a time, oldest first.
+	now := time.Now().UTC()
+	var got []entity.OrderEvent
+	for i := 0; i < 3; i++ {
+		events := s.claimOrderEvents(order.ID, now, time.Minute)
+		s.Require().Len(events, 1)
+		got = append(got, events[0])
+		s.Require().NoError(s.store.MarkOrderEventPublished(ctx, events[0].ID, now))
+	}
+	s.Require().Empty(s.claimOrderEvents(order.ID, now, time.Minute))
+
+	s.Require().Equal(entity.OrderEventCreated, got[0].Type)
+	s.Require().Equal(entity.OrderEventStatusChanged, got[1].Type)
+	s.Require().Equal(entity.OrderEventCancelled, got[2].Type)
+	s.Require().Less(got[0].ID, got[1].ID)
+	s.Require().Less(got[1].ID, got[2].ID)
+
+	var payload entity.OrderEventPayload
+	s.Require().NoError(json.Unmarshal(got[2].Payload, &payload))
+	s.Require().Equal(order.ID, payload.OrderID)
+	s.Require().Equal(order.UserID, payload.UserID)
+	s.Require().Equal(entity.OrderStatusCancelled, payload.Status)
+	s.Require().Equal(entity.OrderStatusInProgress, payload.PreviousStatus)
+	s.Require().Equal("42.50", payload.Amount)
+	s.Require().Equal(entity.DefaultCurrency, payload.Currency)
+	s.Require().Len(payload.Items, 1)
+	s.Require().NotNil(payload.Cancellation)
+	s.Require().Equal(entity.CancelReasonOutOfStock, payload.Cancellation.CancelReason)
+}
+
+func (s *OrderStoreSuite) TestOrderEvents_NotRecordedForFailedChanges() {
+	ctx := context.Background()
+	order := s.newOrder(s.newUserID(), time.Now())
+	s.Require().NoError(s.store.Create(ctx, order))
+	s.Require().ErrorIs(s.store.Create(ctx, order), store.ErrOrderAlreadyExists)
+	_, err := s.store.UpdateStatus(ctx, order.ID, entity.OrderStatusInProgress, entity.OrderStatusFinished)
+	s.Require().ErrorIs(err, store.ErrOrderStatusConflict)
+
+	now := time.Now().UTC()
+	events := s.claimOrderEvents(order.ID, now, time.Minute)
+	s.Require().Len(events, 1)
+	s.Require().NoError(s.store.MarkOrderEventPublished(ctx, events[0].ID, now))
+	s.Require().Empty(s.claimOrderEvents(order.ID, now, time.Minute))
+}
+
+func (s *OrderStoreSuite) TestClaimOrderEvents_Lease() {
+	order := s.newOrder(s.newUserID(), time.Now())
+	s.Require().NoError(s.store.Create(context.Background(), order))
+
+	now := time.Now().UTC()
+	claimed := s.claimOrderEvents(order.ID, now, time.Minute)
+	s.Require().Len(claimed, 1)
+
+	s.Require().Empty(s.claimOrderEvents(order.ID, now.Add(30*time.Second), time.Minute),
+		"Claimed event should be hidden while leased")
+
+	// The relay that claimed the event never reported back, so it is claimed again.
+	reclaimed := s.claimOrderEvents(order.ID, now.Add(2*time.Minute), time.Minute)
+	s.Require().Len(reclaimed, 1)
+	s.Require().Equal(claimed[0].ID, reclaimed[0].ID)
+}
+
+func (s *OrderStoreSuite) TestMarkOrderEventFailed() {
+	ctx := context.Background()
+	order := s.newOrder(s.newUserID(), time.Now())
+	s.Require().NoError(s.store.Create(ctx, order))
+	_, err := s.store.UpdateStatus(ctx, order.ID, entity.OrderStatusNew, entity.OrderStatusInProgress)
+	s.Require().NoError(err)
+
+	now := time.Now().UTC()
+	claimed := s.claimOrderEvents(order.ID, now, time.Minute)
+	s.Require().Len(claimed, 1)
+	s.Require().NoError(s.store.MarkOrderEventFailed(ctx, claimed[0].ID, now.Add(time.Hour), "broker unavailable"))
+
+	// The failed event waits for its next attempt and holds back the later event.
+	s.Require().Empty(s.claimOrderEvents(order.ID, now.Add(30*time.Minute), time.Minute))
+
+	retried := s.claimOrderEvents(order.ID, now.Add(time.Hour), time.Minute)
+	s.Require().Len(retried, 1)
+	s.Require().Equal(claimed[0].ID, retried[0].ID)
+	s.Require().Equal(1, retried[0].Attempts)
+
+	s.Require().NoError(s.store.MarkOrderEventPublished(ctx, retried[0].ID, now))
+	s.Require().ErrorIs(s.store.MarkOrderEventFailed(ctx, retried[0].ID, now, "late failure"), store.ErrOrderEventNotFound,
+		"Published event should not be marked as failed")
+	s.Require().ErrorIs(s.store.MarkOrderEventPublished(ctx, -1, now), store.ErrOrderEventNotFound)
+}
+
+func (s *OrderStoreSuite) TestSubscribeOrderEvents() {
+	ctx := context.Background()
+	sub, err := s.store.SubscribeOrderEvents(ctx)
+	s.Require().NoError(err)
+
+	order := s.newOrder(s.newUserID(), time.Now())
+	s.Require().NoError(s.store.Create(ctx, order))
+	_, err = s.store.UpdateStatus(ctx, order.ID, entity.OrderStatusNew, entity.OrderStatusInProgress)
+	s.Require().NoError(err)
+
+	events := s.receiveOrderEvents(sub, order.ID, 2)
+	s.Require().Equal(entity.OrderEventCreated, events[0].Type)
+	s.Require().Equal(entity.OrderEventStatusChanged, events[1].Type)
+	s.Require().Less(events[0].ID, events[1].ID)
+
+	var payload entity.OrderEventPayload
+	s.Require().NoError(json.Unmarshal(events[1].Payload, &payload))
+	s.Require().Equal(entity.OrderStatusInProgress, payload.Status)
+
+	sub.Close()
+	for range sub.Events() {
+	}
+	s.Require().NoError(sub.Err(), "Closed subscription should not report an error")
+}
+
+func (s *OrderStoreSuite) TestListOrderEvents() {
+	ctx := context.Background()
+	sub, err := s.store.SubscribeOrderEvents(ctx)
+	s.Require().NoError(err)
+	defer sub.Close()
+
+	order := s.newOrder(s.newUserID(), time.Now())
+	s.Require().NoError(s.store.Create(ctx, order))
+	_, err = s.store.UpdateStatus(ctx, order.ID, entity.OrderStatusNew, entity.OrderStatusInProgress)
+	s.Require().NoError(err)
+	received := s.receiveOrderEvents(sub, order.ID, 2)
+
+	// Events are listed in ID order starting after the given ID.
+	events, err := s.store.ListOrderEvents(ctx, received[0].ID-1, 100)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(events)
+	s.Require().Equal(received[0].ID, events[0].ID)
+	s.Require().Equal(received[0].Payload, events[0].Payload)
+	for i := 1; i < len(events); i++ {
+		s.Require().Less(events[i-1].ID, events[i].ID)
+	}
+
+	events, err = s.store.ListOrderEvents(ctx, received[0].ID, 1)
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+	s.Require().Greater(events[0].ID, received[0].ID)
+	s.Require().LessOrEqual(events[0].ID, received[1].ID)
+}
+
+func (s *OrderStoreSuite) TestPurgePublishedOrderEvents() {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	expired := s.newOrderEvent()
+	s.Require().NoError(s.store.MarkOrderEventPublished(ctx, expired.ID, now.Add(-48*time.Hour)))
+	recent := s.newOrderEvent()
+	s.Require().NoError(s.store.MarkOrderEventPublished(ctx, recent.ID, now.Add(-time.Hour)))
+	unpublished := s.newOrderEvent()
+
+	// An event is kept while a webhook delivery of it is pending.
+	subscription := s.newWebhookSubscription()
+	defer s.deleteWebhookSubscriptions(subscription)
+	undelivered := s.newOrderEvent()
+	_, err := s.store.EnqueueWebhookDeliveries(ctx, undelivered, now)
+	s.Require().NoError(err)
+	s.Require().NoError(s.store.MarkOrderEventPublished(ctx, undelivered.ID, now.Add(-48*time.Hour)))
+
+	purged, err := s.store.PurgePublishedOrderEvents(ctx, now.Add(-24*time.Hour))
+	s.Require().NoError(err)
+	s.Require().GreaterOrEqual(purged, int64(1))
+
+	s.Require().False(s.orderEventExists(expired.ID), "Event published before the cutoff should be purged")
+	s.Require().True(s.orderEventExists(recent.ID))
+	s.Require().True(s.orderEventExists(unpublished.ID))
+	s.Require().True(s.orderEventExists(undelivered.ID))
+	s.Require().Len(s.listWebhookDeliveries(store.WebhookDeliveryQuery{SubscriptionID: subscription.ID}), 1)
+}
+
+func (s *OrderStoreSuite) TestWebhookSubscriptions() {
+	ctx := context.Background()
+	subscription := s.newWebhookSubscription(entity.OrderEventCreated, entity.OrderEventCancelled)
+
+	subscriptions, err := s.store.ListWebhookSubscriptions(ctx)
+	s.Require().NoError(err)
+	got := findWebhookSubscription(subscriptions, subscription.ID)
+	s.Require().NotNil(got, "Created subscription should be listed")
+	s.Require().Equal(subscription.URL, got.URL)
+	s.Require().Equal(subscription.Secret, got.Secret)
+	s.Require().Equal(subscription.EventTypes, got.EventTypes)
+	s.Require().True(subscription.CreatedAt.Equal(got.CreatedAt))
+
+	s.Require().NoError(s.store.DeleteWebhookSubscription(ctx, subscription.ID))
+	subscriptions, err = s.store.ListWebhookSubscriptions(ctx)
+	s.Require().NoError(err)
+	s.Require().Nil(findWebhookSubscription(subscriptions, subscription.ID))
+
+	s.Require().ErrorIs(s.store.DeleteWebhookSubscription(ctx, subscription.ID), store.ErrWebhookSubscriptionNotFound)
+}
+
+func (s *OrderStoreSuite) TestEnqueueWebhookDeliveries() {
+	ctx := context.Background()
+	all := s.newWebhookSubscription()
+	cancelledOnly := s.newWebhookSubscription(entity.OrderEventCancelled)
+	defer s.deleteWebhookSubscriptions(all, cancelledOnly)
+
+	event := s.newOrderEvent()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	_, err := s.store.EnqueueWebhookDeliveries(ctx, event, now)
+	s.Require().NoError(err)
+	// The outbox delivers at least once, enqueueing the event again must not duplicate deliveries.
+	_, err = s.store.EnqueueWebhookDeliveries(ctx, event, now)
+	s.Require().NoError(err)
+
+	deliveries := s.listWebhookDeliveries(store.WebhookDeliveryQuery{SubscriptionID: all.ID})
+	s.Require().Len(deliveries, 1)
+	delivery := deliveries[0]
+	s.Require().Equal(all.ID, delivery.SubscriptionID)
+	s.Require().Equal(event.ID, delivery.EventID)
+	s.Require().Equal(event.Type, delivery.EventType)
+	s.Require().Equal(event.OrderID, delivery.OrderID)
+	s.Require().JSONEq(string(event.Payload), string(delivery.Payload))
+	s.Require().Equal(entity.WebhookDeliveryPending, delivery.Status)
+	s.Require().Zero(delivery.Attempts)
+	s.Require().True(now.Equal(delivery.NextAttemptAt))
+	s.Require().Nil(delivery.DeliveredAt)
+
+	s.Require().Empty(s.listWebhookDeliveries(store.WebhookDeliveryQuery{SubscriptionID: cancelledOnly.ID}),
+		"Subscription should only get the event types it asked for")
+}
+
+func (s *OrderStoreSuite) TestWebhookDeliveryLifecycle() {
+	ctx := context.Background()
+	subscription := s.newWebhookSubscription()
+	defer s.deleteWebhookSubscriptions(subscription)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	_, err := s.store.EnqueueWebhookDeliveries(ctx, s.newOrderEvent(), now)
+	s.Require().NoError(err)
+
+	claimed := s.claimWebhookDeliveries(subscription.ID, now, time.Minute)
+	s.Require().Len(claimed, 1)
+	s.Require().Equal(subscription.URL, claimed[0].URL)
+	s.Require().Equal(subscription.Secret, claimed[0].Secret)
+	id := claimed[0].ID
+	s.Require().Empty(s.claimWebhookDeliveries(subscription.ID, now, time.Minute), "Claimed delivery should be leased")
+
+	s.Require().NoError(s.store.MarkWebhookDeliveryFailed(ctx, id, now.Add(time.Hour), "endpoint responded with status 500"))
+	s.Require().Empty(s.claimWebhookDeliveries(subscription.ID, now.Add(30*time.Minute), time.Minute))
+	claimed = s.claimWebhookDeliveries(subscription.ID, now.Add(time.Hour), time.Minute)
+	s.Require().Len(claimed, 1)
+	s.Require().Equal(1, claimed[0].Attempts)
+	s.Require().Equal("endpoint responded with status 500", claimed[0].LastError)
+
+	s.Require().NoError(s.store.MarkWebhookDeliveryDead(ctx, id, "endpoint responded with status 500"))
+	s.Require().Empty(s.claimWebhookDeliveries(subscription.ID, now.Add(48*time.Hour), time.Minute),
+		"Dead delivery should not be claimed")
+	dead := s.listWebhookDeliveries(store.WebhookDeliveryQuery{SubscriptionID: subscription.ID, Status: entity.WebhookDeliveryDead})
+	s.Require().Len(dead, 1)
+	s.Require().Equal(2, dead[0].Attempts)
+	s.Require().ErrorIs(s.store.MarkWebhookDeliveryFailed(ctx, id, now, "late failure"), store.ErrWebhookDeliveryNotFound)
+
+	replayed, err := s.store.ReplayWebhookDelivery(ctx, id, now.Add(2*time.Hour))
+	s.Require().NoError(err)
+	s.Require().Equal(entity.WebhookDeliveryPending, replayed.Status)
+	s.Require().Zero(replayed.Attempts)
+	s.Require().Empty(replayed.LastError)
+	claimed = s.claimWebhookDeliveries(subscription.ID, now.Add(2*time.Hour), time.Minute)
+	s.Require().Len(claimed, 1)
+
+	deliveredAt := now.Add(2 * time.Hour)
+	s.Require().NoError(s.store.MarkWebhookDelivered(ctx, id, deliveredAt))
+	delivered := s.listWebhookDeliveries(store.WebhookDeliveryQuery{SubscriptionID: subscription.ID})
+	s.Require().Len(delivered, 1)
+	s.Require().Equal(entity.WebhookDeliveryDelivered, delivered[0].Status)
+	s.Require().NotNil(delivered[0].DeliveredAt)
+	s.Require().True(deliveredAt.Equal(*delivered[0].DeliveredAt))
+
+	_, err = s.store.ReplayWebhookDelivery(ctx, id, now)
+	s.Require().ErrorIs(err, store.ErrWebhookDeliveryNotDead)
+	_, err = s.store.ReplayWebhookDelivery(ctx, -1, now)
+	s.Require().ErrorIs(err, store.ErrWebhookDeliveryNotFound)
+}
+
+func (s *OrderStoreSuite) TestListWebhookDeliveries_Pagination() {
+	ctx := context.Background()
+	subscription := s.newWebhookSubscription()
+	defer s.deleteWebhookSubscriptions(subscription)
+
+	var orderIDs []string
+	for i := 0; i < 3; i++ {
+		event := s.newOrderEvent()
+		orderIDs = append(orderIDs, event.OrderID)
+		_, err := s.store.EnqueueWebhookDeliveries(ctx, event, time.Now().UTC())
+		s.Require().NoError(err)
+	}
+
+	var got []string
+	query := store.WebhookDeliveryQuery{PageSize: 2, SubscriptionID: subscription.ID}
+	for pages := 1; ; pages++ {
+		page, err := s.store.ListWebhookDeliveries(ctx, query)
+		s.Require().NoError(err)
+		for _, delivery := range page.Deliveries {
+			got = append(got, delivery.OrderID)
+		}
+		if page.NextPageToken == "" {
+			s.Require().Equal(2, pages)
+			break
+		}
+		query.PageToken = page.NextPageToken
+	}
+	s.Require().Equal([]string{orderIDs[2], orderIDs[1], orderIDs[0]}, got, "Deliveries should be listed newest first")
+
+	byOrder := s.listWebhookDeliveries(store.WebhookDeliveryQuery{SubscriptionID: subscription.ID, OrderID: orderIDs[1]})
+	s.Require().Len(byOrder, 1)
+	s.Require().Equal(orderIDs[1], byOrder[0].OrderID)
+
+	_, err := s.store.ListWebhookDeliveries(ctx, store.WebhookDeliveryQuery{PageSize: 2, PageToken: "not-a-token"})
+	s.Require().ErrorIs(err, store.ErrInvalidPageToken)
+}
+
+func (s *OrderStoreSuite) TestContextCancellation() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	order := s.newOrder(s.newUserID(), time.Now())
+
+	s.Require().ErrorIs(s.store.Create(ctx, order), context.Canceled)
+
+	_, err := s.store.Get(ctx, order.ID)
+	s.Require().ErrorIs(err, context.Canceled)
+
+	_, err = s.store.List(ctx, store.ListQuery{PageSize: 10})
+	s.Require().ErrorIs(err, context.Canceled)
+
+	_, err = s.store.UpdateStatus(ctx, order.ID, entity.OrderStatusNew, entity.OrderStatusInProgress)
+	s.Require().ErrorIs(err, context.Canceled)
+}
+
+func (s *OrderStoreSuite) TestPing() {
+	s.Require().NoError(s.store.Ping(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.Require().ErrorIs(s.store.Ping(ctx), context.Canceled)
+}
+
+func (s *OrderStoreSuite) TestConcurrentWrites() {
+	const writers = 20
+	ctx := context.Background()
+	userID := s.newUserID()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- s.store.Create(ctx, s.newOrder(userID, time.Now()))
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		s.Require().NoError(err)
+	}
+
+	page, err := s.store.List(ctx, store.ListQuery{PageSize: writers + 1, UserID: userID})
+	s.Require().NoError(err)
+	s.Require().Len(page.Orders, writers)
+}
+
+func (s *OrderStoreSuite) TestConcurrentStatusUpdates() {
+	const updaters = 10
+	ctx := context.Background()
+	order := s.newOrder(s.newUserID(), time.Now())
+	s.Require().NoError(s.store.Create(ctx, order))
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+		conflicts int
+	)
+	for i := 0; i < updaters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.store.UpdateStatus(ctx, order.ID, entity.OrderStatusNew, entity.OrderStatusInProgress)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, store.ErrOrderStatusConflict):
+				conflicts++
+			}
+		}()
+	}
+	wg.Wait()
+
+	s.Require().Equal(1, succeeded, "Exactly one transition should win")
+	s.Require().Equal(updaters-1, conflicts)
+}
+
+func (s *OrderStoreSuite) newUserID() string {
+	return uuid.New().String()
+}
+
+func (s *OrderStoreSuite) newOrder(userID string, createdAt time.Time) *entity.Order {
+	return &entity.Order{
+		ID:     uuid.New().String(),
+		UserID: userID,
+		Items: []entity.OrderItem{{
+			SKU:       "SKU-1",
+			Name:      "Conformance Item",
+			Quantity:  1,
+			UnitPrice: entity.NewMoney(4250, entity.DefaultCurrency),
+		}},
+		Amount:    entity.NewMoney(4250, entity.DefaultCurrency),
+		Status:    entity.OrderStatusNew,
+		CreatedAt: createdAt.UTC().Truncate(time.Microsecond),
+	}
+}
+
+// claimOrderEvents claims all events due at now and returns those of the order.
+// Events of other orders may already be in the store, so it claims until none are left.
+func (s *OrderStoreSuite) claimOrderEvents(orderID string, now time.Time, lease time.Duration) []entity.OrderEvent {
+	var events []entity.OrderEvent
+	for {
+		claimed, err := s.store.ClaimOrderEvents(context.Background(), now, lease, 100)
+		s.Require().NoError(err)
+		if len(claimed) == 0 {
+			return events
+		}
+		for _, event := range claimed {
+			if event.OrderID == orderID {
+				events = append(events, event)
+			}
+		}
+	}
+}
+
+// receiveOrderEvents waits for count events of the order, skipping events of other orders.
+func (s *OrderStoreSuite) receiveOrderEvents(sub *store.OrderEventSubscription, orderID string, count int) []entity.OrderEvent {
+	var events []entity.OrderEvent
+	timeout := time.After(5 * time.Second)
+	for len(events) < count {
+		select {
+		case event, ok := <-sub.Events():
+			s.Require().True(ok, "Subscription ended: %v", sub.Err())
+			if event.OrderID == orderID {
+				events = append(events, event)
+			}
+		case <-timeout:
+			s.FailNow("Timed out waiting for order events", "got %d of %d", len(events), count)
+		}
+	}
+	return events
+}
+
+// orderEventExists reports whether the event with the ID is still in the outbox.
+func (s *OrderStoreSuite) orderEventExists(id int64) bool {
+	events, err := s.store.ListOrderEvents(context.Background(), id-1, 1)
+	s.Require().NoError(err)
+	return len(events) == 1 && events[0].ID == id
+}
+
+func (s *OrderStoreSuite) newWebhookSubscription(eventTypes ...entity.OrderEventType) *entity.WebhookSubscription {
+	subscription := &entity.WebhookSubscription{
+		ID:         uuid.New().String(),
+		URL:        "https://partner.example.com/hooks/" + uuid.New().String(),
+		Secret:     "conformance-secret",
+		EventTypes: eventTypes,
+		CreatedAt:  time.Now().UTC().Truncate(time.Microsecond),
+	}
+	s.Require().NoError(s.store.CreateWebhookSubscription(context.Background(), subscription))
+	return subscription
+}
+
+// deleteWebhookSubscriptions keeps subscriptions of finished tests from collecting deliveries of later ones.
+func (s *OrderStoreSuite) deleteWebhookSubscriptions(subscriptions ...*entity.WebhookSubscription) {
+	for _, subscription := range subscriptions {
+		s.Require().NoError(s.store.DeleteWebhookSubscription(context.Background(), subscription.ID))
+	}
+}
+
+// newOrderEvent creates an order and returns its ORDER_CREATED event.
+func (s *OrderStoreSuite) newOrderEvent() entity.OrderEvent {
+	order := s.newOrder(s.newUserID(), time.Now())
+	s.Require().NoError(s.store.Create(context.Background(), order))
+	events := s.claimOrderEvents(order.ID, time.Now().UTC(), time.Minute)
+	s.Require().Len(events, 1)
+	return events[0]
+}
+
+// claimWebhookDeliveries claims all deliveries due at now and returns those of the subscription.
+func (s *OrderStoreSuite) claimWebhookDeliveries(
+	subscriptionID string,
+	now time.Time,
+	lease time.Duration,
+) []store.ClaimedWebhookDelivery {
+	var deliveries []store.ClaimedWebhookDelivery
+	for {
+		claimed, err := s.store.ClaimWebhookDeliveries(context.Background(), now, lease, 100)
+		s.Require().NoError(err)
+		if len(claimed) == 0 {
+			return deliveries
+		}
+		for _, delivery := range claimed {
+			if delivery.SubscriptionID == subscriptionID {
+				deliveries = append(deliveries, delivery)
+			}
+		}
+	}
+}
+
+func (s *OrderStoreSuite) listWebhookDeliveries(query store.WebhookDeliveryQuery) []*entity.WebhookDelivery {
+	query.PageSize = 100
+	page, err := s.store.ListWebhookDeliveries(context.Background(), query)
+	s.Require().NoError(err)
+	return page.Deliveries
+}
+
+func findWebhookSubscription(subscriptions []*entity.WebhookSubscription, id string) *entity.WebhookSubscription {
+	for _, subscription := range subscriptions {
+		if subscription.ID == id {
+			return subscription
+		}
+	}
+	return nil
+}
+
+func orderIDs(orders []*entity.Order) []string {
+	ids := make([]string, len(orders))
+	for i, o := range orders {
+		ids[i] = o.ID
+	}
+	return ids
+}
@@ -0,0 +1,324 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/demo/order/internal/entity"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrWebhookDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrWebhookDeliveryNotDead      = errors.New("webhook delivery is not dead")
+)
+
+// WebhookDeliveryQuery describes a single page of deliveries requested from
+// OrderStore.ListWebhookDeliveries. Deliveries are returned newest first;
+// zero-valued filters are not applied.
+type WebhookDeliveryQuery struct {
+	// PageSize is the maximum number of deliveries to return, must be positive.
+	PageSize int
+	// PageToken is the NextPageToken of the previous page, empty for the first page.
+	PageToken string
+
+	SubscriptionID string
+	Status         entity.WebhookDeliveryStatus
+	OrderID        string
+}
+
+// WebhookDeliveryPage is a page of deliveries returned by OrderStore.ListWebhookDeliveries.
+type WebhookDeliveryPage struct {
+	Deliveries []*entity.WebhookDelivery
+	// NextPageToken is empty when there are no more deliveries.
+	NextPageToken string
+}
+
+// ClaimedWebhookDelivery is a due delivery together with the endpoint it is sent to.
+type ClaimedWebhookDelivery struct {
+	entity.WebhookDelivery
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
+
+// newWebhookDeliveryPage trims deliveries fetched with one extra row down to
+// pageSize and derives the next page token from the last delivery kept.
+func newWebhookDeliveryPage(deliveries []*entity.WebhookDelivery, pageSize int) *WebhookDeliveryPage {
+	if len(deliveries) <= pageSize {
+		return &WebhookDeliveryPage{Deliveries: deliveries}
+	}
+	deliveries = deliveries[:pageSize]
+	return &WebhookDeliveryPage{
+		Deliveries:    deliveries,
+		NextPageToken: encodeDeliveryPageToken(deliveries[len(deliveries)-1].ID),
+	}
+}
+
+// Delivery IDs only grow, so the ID of the last delivery on a page is enough to continue.
+func encodeDeliveryPageToken(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+// decodeDeliveryPageToken returns 0 for the first page.
+func decodeDeliveryPageToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidPageToken
+	}
+	id, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidPageToken
+	}
+	return id, nil
+}
+
+// webhookSubscriptionRow is an entity.WebhookSubscription as stored in the webhook_subscriptions table.
+type webhookSubscriptionRow struct {
+	entity.WebhookSubscription
+	EventTypes pq.StringArray `db:"event_types"`
+}
+
+func (r *webhookSubscriptionRow) subscription() *entity.WebhookSubscription {
+	subscription := r.WebhookSubscription
+	subscription.EventTypes = nil
+	for _, eventType := range r.EventTypes {
+		subscription.EventTypes = append(subscription.EventTypes, entity.OrderEventType(eventType))
+	}
+	return &subscription
+}
+
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, order_id, payload, status,
+	attempts, last_error, next_attempt_at, created_at, delivered_at`
+
+func (s *PostgresStore) CreateWebhookSubscription(ctx context.Context, subscription *entity.WebhookSubscription) error {
+	eventTypes := make(pq.StringArray, len(subscription.EventTypes))
+	for i, eventType := range subscription.EventTypes {
+		eventTypes[i] = string(eventType)
+	}
+
+	const query = `
+		INSERT INTO webhook_subscriptions (id, url, secret, event_types, created_at)
+		VALUES ($1, $2, $3, $4, $5)`
+	_, err := execContext(ctx, s.db, "webhook_subscriptions.insert", query,
+		subscription.ID,
+		subscription.URL,
+		subscription.Secret,
+		eventTypes,
+		subscription.CreatedAt,
+	)
+	return err
+}
+
+func (s *PostgresStore) ListWebhookSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error) {
+	const query = `
+		SELECT id, url, secret, event_types, created_at FROM webhook_subscriptions
+		ORDER BY created_at, id`
+	var rows []webhookSubscriptionRow
+	if err := selectContext(ctx, s.db, "webhook_subscriptions.list", &rows, query); err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]*entity.WebhookSubscription, len(rows))
+	for i := range rows {
+		subscriptions[i] = rows[i].subscription()
+	}
+	return subscriptions, nil
+}
+
+func (s *PostgresStore) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	res, err := execContext(ctx, s.db, "webhook_subscriptions.delete", `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrWebhookSubscriptionNotFound
+	}
+	return nil
+}
+
+func (s *PostgresStore) EnqueueWebhookDeliveries(ctx context.Context, event entity.OrderEvent, now time.Time) (int64, error) {
+	// The parameters are cast since INSERT ... SELECT does not infer their types from the columns.
+	// Deliveries of an event already enqueued are kept, so the outbox may publish an event twice.
+	const query = `
+		INSERT INTO webhook_deliveries
+			(subscription_id, event_id, event_type, order_id, payload, status, next_attempt_at, created_at)
+		SELECT id, $1::BIGINT, $2::TEXT, $3::TEXT, $4::JSONB, $5::TEXT, $6::TIMESTAMP, $6::TIMESTAMP
+		FROM webhook_subscriptions
+		WHERE cardinality(event_types) = 0 OR $2::TEXT = ANY(event_types)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`
+	// The payload is passed as a string, lib/pq would send []byte as bytea.
+	res, err := execContext(ctx, s.db, "webhook_deliveries.enqueue", query,
+		event.ID,
+		event.Type,
+		event.OrderID,
+		string(event.Payload),
+		entity.WebhookDeliveryPending,
+		now,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (s *PostgresStore) ClaimWebhookDeliveries(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]ClaimedWebhookDelivery, error) {
+	const query = `
+		WITH due AS (
+			SELECT id FROM webhook_deliveries
+			WHERE status = $3 AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, id
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d SET next_attempt_at = $2
+		FROM due, webhook_subscriptions s
+		WHERE d.id = due.id AND s.id = d.subscription_id
+		RETURNING d.id, d.subscription_id, d.event_id, d.event_type, d.order_id, d.payload, d.status,
+			d.attempts, d.last_error, d.next_attempt_at, d.created_at, d.delivered_at, s.url, s.secret`
+	var deliveries []ClaimedWebhookDelivery
+	err := selectContext(ctx, s.db, "webhook_deliveries.claim", &deliveries, query,
+		now, now.Add(lease), entity.WebhookDeliveryPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (s *PostgresStore) MarkWebhookDelivered(ctx context.Context, id int64, deliveredAt time.Time) error {
+	const query = `
+		UPDATE webhook_deliveries SET status = $3, delivered_at = $2, last_error = ''
+		WHERE id = $1 AND status = $4`
+	res, err := execContext(ctx, s.db, "webhook_deliveries.mark_delivered", query,
+		id, deliveredAt, entity.WebhookDeliveryDelivered, entity.WebhookDeliveryPending)
+	if err != nil {
+		return err
+	}
+	return webhookDeliveryAffected(res)
+}
+
+func (s *PostgresStore) MarkWebhookDeliveryFailed(
+	ctx context.Context,
+	id int64,
+	nextAttemptAt time.Time,
+	lastError string,
+) error {
+	const query = `
+		UPDATE webhook_deliveries SET attempts = attempts + 1, next_attempt_at = $2, last_error = $3
+		WHERE id = $1 AND status = $4`
+	res, err := execContext(ctx, s.db, "webhook_deliveries.mark_failed", query,
+		id, nextAttemptAt, lastError, entity.WebhookDeliveryPending)
+	if err != nil {
+		return err
+	}
+	return webhookDeliveryAffected(res)
+}
+
+func (s *PostgresStore) MarkWebhookDeliveryDead(ctx context.Context, id int64, lastError string) error {
+	const query = `
+		UPDATE webhook_deliveries SET attempts = attempts + 1, status = $3, last_error = $2
+		WHERE id = $1 AND status = $4`
+	res, err := execContext(ctx, s.db, "webhook_deliveries.mark_dead", query,
+		id, lastError, entity.WebhookDeliveryDead, entity.WebhookDeliveryPending)
+	if err != nil {
+		return err
+	}
+	return webhookDeliveryAffected(res)
+}
+
+func (s *PostgresStore) ListWebhookDeliveries(ctx context.Context, query WebhookDeliveryQuery) (*WebhookDeliveryPage, error) {
+	afterID, err := decodeDeliveryPageToken(query.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		conds []string
+		args  []any
+	)
+	where := func(cond string, value any) {
+		args = append(args, value)
+		conds = append(conds, fmt.Sprintf(cond, fmt.Sprintf("$%d", len(args))))
+	}
+
+	if afterID > 0 {
+		where("id < %s", afterID)
+	}
+	if query.SubscriptionID != "" {
+		where("subscription_id = %s", query.SubscriptionID)
+	}
+	if query.Status != "" {
+		where("status = %s", query.Status)
+	}
+	if query.OrderID != "" {
+		where("order_id = %s", query.OrderID)
+	}
+
+	sqlQuery := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries`
+	if len(conds) > 0 {
+		sqlQuery += ` WHERE ` + strings.Join(conds, ` AND `)
+	}
+	// One extra row tells whether there is a next page.
+	args = append(args, query.PageSize+1)
+	sqlQuery += fmt.Sprintf(` ORDER BY id DESC LIMIT $%d`, len(args))
+
+	var deliveries []*entity.WebhookDelivery
+	if err := selectContext(ctx, s.db, "webhook_deliveries.list", &deliveries, sqlQuery, args...); err != nil {
+		return nil, err
+	}
+	return newWebhookDeliveryPage(deliveries, query.PageSize), nil
+}
+
+func (s *PostgresStore) ReplayWebhookDelivery(ctx context.Context, id int64, now time.Time) (*entity.WebhookDelivery, error) {
+	const query = `
+		UPDATE webhook_deliveries SET status = $3, attempts = 0, next_attempt_at = $2, last_error = ''
+		WHERE id = $1 AND status = $4
+		RETURNING ` + webhookDeliveryColumns
+	var delivery entity.WebhookDelivery
+	err := getContext(ctx, s.db, "webhook_deliveries.replay", &delivery, query,
+		id, now, entity.WebhookDeliveryPending, entity.WebhookDeliveryDead)
+	if errors.Is(err, sql.ErrNoRows) {
+		var exists bool
+		const existsQuery = `SELECT EXISTS (SELECT 1 FROM webhook_deliveries WHERE id = $1)`
+		if err := getContext(ctx, s.db, "webhook_deliveries.exists", &exists, existsQuery, id); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, ErrWebhookDeliveryNotDead
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func webhookDeliveryAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrWebhookDeliveryNotFound
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"connectrpc.com/connect"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Interceptor traces RPCs. Handlers continue the trace of the caller given in
+// the traceparent header, clients send the trace context in that header.
+type Interceptor struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+var _ connect.Interceptor = (*Interceptor)(nil)
+
+// NewInterceptor creates an interceptor using the global tracer provider and propagator, see Setup.
+func NewInterceptor() *Interceptor {
+	return &Interceptor{
+		tracer:     otel.Tracer(instrumentationName),
+		propagator: otel.GetTextMapPropagator(),
+	}
+}
+
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		ctx, span := i.start(ctx, req.Spec(), req.Header())
+		resp, err := next(ctx, req)
+		endRPC(span, err)
+		return resp, err
+	}
+}
+
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		ctx, span := i.tracer.Start(ctx, spanName(spec), trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(rpcAttributes(spec)...))
+		conn := next(ctx, spec)
+		i.propagator.Inject(ctx, propagation.HeaderCarrier(conn.RequestHeader()))
+		return &tracedClientConn{StreamingClientConn: conn, span: span}
+	}
+}
+
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, span := i.start(ctx, conn.Spec(), conn.RequestHeader())
+		err := next(ctx, conn)
+		endRPC(span, err)
+		return err
+	}
+}
+
+// start starts the span of an RPC. On the handler side it continues the trace
+// in header, on the client side it writes the trace context to header.
+func (i *Interceptor) start(ctx context.Context, spec connect.Spec, header http.Header) (context.Context, trace.Span) {
+	kind := trace.SpanKindServer
+	if spec.IsClient {
+		kind = trace.SpanKindClient
+	} else {
+		ctx = i.propagator.Extract(ctx, propagation.HeaderCarrier(header))
+	}
+	ctx, span := i.tracer.Start(ctx, spanName(spec), trace.WithSpanKind(kind), trace.WithAttributes(rpcAttributes(spec)...))
+	if spec.IsClient {
+		i.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+	}
+	return ctx, span
+}
+
+// tracedClientConn ends the span of a client stream once its response is closed.
+type tracedClientConn struct {
+	connect.StreamingClientConn
+	span trace.Span
+	once sync.Once
+}
+
+func (c *tracedClientConn) CloseResponse() error {
+	err := c.StreamingClientConn.CloseResponse()
+	c.once.Do(func() { endRPC(c.span, err) })
+	return err
+}
+
+// spanName is the procedure without the leading slash, e.g. "order.v1.OrderService/CreateOrder".
+func spanName(spec connect.Spec) string {
+	return strings.TrimPrefix(spec.Procedure, "/")
+}
+
+func rpcAttributes(spec connect.Spec) []attribute.KeyValue {
+	service, method, _ := strings.Cut(spanName(spec), "/")
+	return []attribute.KeyValue{
+		semconv.RPCSystemConnectRPC,
+		semconv.RPCService(service),
+		semconv.RPCMethod(method),
+	}
+}
+
+func endRPC(span trace.Span, err error) {
+	if err != nil {
+		span.SetAttributes(semconv.RPCConnectRPCErrorCodeKey.String(connect.CodeOf(err).String()))
+	}
+	end(span, err)
+}
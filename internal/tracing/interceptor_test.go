@@ -0,0 +1,162 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/contracts/gen/go/order/v1/orderv1connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// record installs a global tracer provider that records the ended spans.
+func record(t *testing.T) *tracetest.SpanRecorder {
+	_, err := Setup(context.Background(), "order", Config{Exporter: ExporterNone})
+	require.NoError(t, err)
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	return recorder
+}
+
+// spans indexes the ended spans by name and kind.
+func spans(recorder *tracetest.SpanRecorder) map[string]sdktrace.ReadOnlySpan {
+	result := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		result[span.SpanKind().String()+" "+span.Name()] = span
+	}
+	return result
+}
+
+// orderService answers GetOrder for "found" and fails otherwise, and streams a
+// single update on WatchOrders.
+type orderService struct {
+	orderv1connect.UnimplementedOrderServiceHandler
+}
+
+func (orderService) GetOrder(
+	_ context.Context,
+	req *connect.Request[orderv1.GetOrderRequest],
+) (*connect.Response[orderv1.GetOrderResponse], error) {
+	if req.Msg.Id != "found" {
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("order not found"))
+	}
+	return connect.NewResponse(&orderv1.GetOrderResponse{Order: &orderv1.Order{Id: req.Msg.Id}}), nil
+}
+
+func (orderService) WatchOrders(
+	_ context.Context,
+	_ *connect.Request[orderv1.WatchOrdersRequest],
+	stream *connect.ServerStream[orderv1.WatchOrdersResponse],
+) error {
+	return stream.Send(&orderv1.WatchOrdersResponse{})
+}
+
+func newClient(t *testing.T, clientOptions ...connect.ClientOption) orderv1connect.OrderServiceClient {
+	path, handler := orderv1connect.NewOrderServiceHandler(orderService{},
+		connect.WithInterceptors(NewInterceptor()))
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return orderv1connect.NewOrderServiceClient(server.Client(), server.URL, clientOptions...)
+}
+
+func TestInterceptor_Unary(t *testing.T) {
+	recorder := record(t)
+	client := newClient(t, connect.WithInterceptors(NewInterceptor()))
+
+	_, err := client.GetOrder(context.Background(), connect.NewRequest(&orderv1.GetOrderRequest{Id: "found"}))
+	require.NoError(t, err)
+
+	ended := spans(recorder)
+	require.Len(t, ended, 2)
+	clientSpan := ended["client order.v1.OrderService/GetOrder"]
+	serverSpan := ended["server order.v1.OrderService/GetOrder"]
+	require.NotNil(t, clientSpan)
+	require.NotNil(t, serverSpan)
+	assert.Equal(t, clientSpan.SpanContext().TraceID(), serverSpan.SpanContext().TraceID(),
+		"The handler should continue the trace of the client")
+	assert.Equal(t, clientSpan.SpanContext().SpanID(), serverSpan.Parent().SpanID())
+	assert.True(t, serverSpan.Parent().IsRemote())
+	assert.Equal(t, codes.Unset, serverSpan.Status().Code)
+	assert.Contains(t, serverSpan.Attributes(), semconv.RPCSystemConnectRPC)
+	assert.Contains(t, serverSpan.Attributes(), semconv.RPCService("order.v1.OrderService"))
+	assert.Contains(t, serverSpan.Attributes(), semconv.RPCMethod("GetOrder"))
+}
+
+func TestInterceptor_Error(t *testing.T) {
+	recorder := record(t)
+	client := newClient(t)
+
+	_, err := client.GetOrder(context.Background(), connect.NewRequest(&orderv1.GetOrderRequest{Id: "missing"}))
+	require.Error(t, err)
+
+	span := spans(recorder)["server order.v1.OrderService/GetOrder"]
+	require.NotNil(t, span)
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Contains(t, span.Attributes(), semconv.RPCConnectRPCErrorCodeKey.String("not_found"))
+}
+
+func TestInterceptor_Traceparent(t *testing.T) {
+	recorder := record(t)
+	client := newClient(t)
+
+	req := connect.NewRequest(&orderv1.GetOrderRequest{Id: "found"})
+	req.Header().Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, err := client.GetOrder(context.Background(), req)
+	require.NoError(t, err)
+
+	span := spans(recorder)["server order.v1.OrderService/GetOrder"]
+	require.NotNil(t, span)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+}
+
+func TestInterceptor_Streaming(t *testing.T) {
+	recorder := record(t)
+	client := newClient(t, connect.WithInterceptors(NewInterceptor()))
+
+	stream, err := client.WatchOrders(context.Background(), connect.NewRequest(&orderv1.WatchOrdersRequest{}))
+	require.NoError(t, err)
+	for stream.Receive() {
+	}
+	require.NoError(t, stream.Close())
+
+	ended := spans(recorder)
+	clientSpan := ended["client order.v1.OrderService/WatchOrders"]
+	serverSpan := ended["server order.v1.OrderService/WatchOrders"]
+	require.NotNil(t, clientSpan, "The client span should end when the stream is closed")
+	require.NotNil(t, serverSpan)
+	assert.Equal(t, clientSpan.SpanContext().SpanID(), serverSpan.Parent().SpanID())
+}
+
+func TestRun(t *testing.T) {
+	recorder := record(t)
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+
+	require.NoError(t, Run(ctx, "GetOrder.validate", func() error { return nil }))
+	err := Run(ctx, "CreateOrder.validate", func() error { return errors.New("amount must be positive") })
+	require.EqualError(t, err, "amount must be positive")
+	parent.End()
+
+	ended := spans(recorder)
+	ok := ended[trace.SpanKindInternal.String()+" GetOrder.validate"]
+	failed := ended[trace.SpanKindInternal.String()+" CreateOrder.validate"]
+	require.NotNil(t, ok)
+	require.NotNil(t, failed)
+	assert.Equal(t, parent.SpanContext().SpanID(), ok.Parent().SpanID())
+	assert.Equal(t, codes.Unset, ok.Status().Code)
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.Equal(t, "amount must be positive", failed.Status().Description)
+}
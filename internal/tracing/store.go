@@ -0,0 +1,252 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Store traces every call to the wrapped store in a span named after the
+// method, e.g. "OrderStore.Get". The SQL statements run by PostgresStore are
+// traced in child spans. Calls outside of a trace, such as the polls of the
+// background workers and the health checks, are not traced.
+type Store struct {
+	next   store.OrderStore
+	tracer trace.Tracer
+}
+
+var _ store.OrderStore = (*Store)(nil)
+
+// NewStore wraps next using the global tracer provider, see Setup.
+func NewStore(next store.OrderStore) *Store {
+	return &Store{next: next, tracer: otel.Tracer(instrumentationName)}
+}
+
+func (s *Store) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return s.tracer.Start(ctx, "OrderStore."+method)
+}
+
+func (s *Store) Create(ctx context.Context, order *entity.Order) error {
+	ctx, span := s.start(ctx, "Create")
+	err := s.next.Create(ctx, order)
+	end(span, err)
+	return err
+}
+
+func (s *Store) CreateWithIdempotencyKey(ctx context.Context, order *entity.Order, record store.IdempotencyRecord) error {
+	ctx, span := s.start(ctx, "CreateWithIdempotencyKey")
+	err := s.next.CreateWithIdempotencyKey(ctx, order, record)
+	end(span, err)
+	return err
+}
+
+func (s *Store) GetIdempotencyRecord(ctx context.Context, userID, key string, now time.Time) (*store.IdempotencyRecord, error) {
+	ctx, span := s.start(ctx, "GetIdempotencyRecord")
+	record, err := s.next.GetIdempotencyRecord(ctx, userID, key, now)
+	end(span, err)
+	return record, err
+}
+
+func (s *Store) DeleteExpiredIdempotencyRecords(ctx context.Context, now time.Time) (int64, error) {
+	ctx, span := s.start(ctx, "DeleteExpiredIdempotencyRecords")
+	deleted, err := s.next.DeleteExpiredIdempotencyRecords(ctx, now)
+	end(span, err)
+	return deleted, err
+}
+
+func (s *Store) Get(ctx context.Context, id string) (*entity.Order, error) {
+	ctx, span := s.start(ctx, "Get")
+	order, err := s.next.Get(ctx, id)
+	end(span, err)
+	return order, err
+}
+
+func (s *Store) GetIncludingDeleted(ctx context.Context, id string) (*entity.Order, error) {
+	ctx, span := s.start(ctx, "GetIncludingDeleted")
+	order, err := s.next.GetIncludingDeleted(ctx, id)
+	end(span, err)
+	return order, err
+}
+
+func (s *Store) List(ctx context.Context, query store.ListQuery) (*store.ListPage, error) {
+	ctx, span := s.start(ctx, "List")
+	page, err := s.next.List(ctx, query)
+	end(span, err)
+	return page, err
+}
+
+func (s *Store) UpdateStatus(ctx context.Context, id string, from, to entity.OrderStatus) (*entity.Order, error) {
+	ctx, span := s.start(ctx, "UpdateStatus")
+	order, err := s.next.UpdateStatus(ctx, id, from, to)
+	end(span, err)
+	return order, err
+}
+
+func (s *Store) Cancel(
+	ctx context.Context,
+	id string,
+	from entity.OrderStatus,
+	cancellation entity.OrderCancellation,
+) (*entity.Order, error) {
+	ctx, span := s.start(ctx, "Cancel")
+	order, err := s.next.Cancel(ctx, id, from, cancellation)
+	end(span, err)
+	return order, err
+}
+
+func (s *Store) Delete(ctx context.Context, id string, deletedAt time.Time) (*entity.Order, error) {
+	ctx, span := s.start(ctx, "Delete")
+	order, err := s.next.Delete(ctx, id, deletedAt)
+	end(span, err)
+	return order, err
+}
+
+func (s *Store) Restore(ctx context.Context, id string) (*entity.Order, error) {
+	ctx, span := s.start(ctx, "Restore")
+	order, err := s.next.Restore(ctx, id)
+	end(span, err)
+	return order, err
+}
+
+func (s *Store) PurgeDeletedOrders(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, span := s.start(ctx, "PurgeDeletedOrders")
+	purged, err := s.next.PurgeDeletedOrders(ctx, deletedBefore)
+	end(span, err)
+	return purged, err
+}
+
+func (s *Store) ClaimOrderEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entity.OrderEvent, error) {
+	ctx, span := s.start(ctx, "ClaimOrderEvents")
+	events, err := s.next.ClaimOrderEvents(ctx, now, lease, limit)
+	end(span, err)
+	return events, err
+}
+
+func (s *Store) MarkOrderEventPublished(ctx context.Context, id int64, publishedAt time.Time) error {
+	ctx, span := s.start(ctx, "MarkOrderEventPublished")
+	err := s.next.MarkOrderEventPublished(ctx, id, publishedAt)
+	end(span, err)
+	return err
+}
+
+func (s *Store) MarkOrderEventFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	ctx, span := s.start(ctx, "MarkOrderEventFailed")
+	err := s.next.MarkOrderEventFailed(ctx, id, nextAttemptAt, lastError)
+	end(span, err)
+	return err
+}
+
+func (s *Store) PurgePublishedOrderEvents(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	ctx, span := s.start(ctx, "PurgePublishedOrderEvents")
+	purged, err := s.next.PurgePublishedOrderEvents(ctx, publishedBefore)
+	end(span, err)
+	return purged, err
+}
+
+func (s *Store) ListOrderEvents(ctx context.Context, afterID int64, limit int) ([]entity.OrderEvent, error) {
+	ctx, span := s.start(ctx, "ListOrderEvents")
+	events, err := s.next.ListOrderEvents(ctx, afterID, limit)
+	end(span, err)
+	return events, err
+}
+
+func (s *Store) SubscribeOrderEvents(ctx context.Context) (*store.OrderEventSubscription, error) {
+	// The subscription outlives the call, so it is not given the span context.
+	_, span := s.start(ctx, "SubscribeOrderEvents")
+	subscription, err := s.next.SubscribeOrderEvents(ctx)
+	end(span, err)
+	return subscription, err
+}
+
+func (s *Store) CreateWebhookSubscription(ctx context.Context, subscription *entity.WebhookSubscription) error {
+	ctx, span := s.start(ctx, "CreateWebhookSubscription")
+	err := s.next.CreateWebhookSubscription(ctx, subscription)
+	end(span, err)
+	return err
+}
+
+func (s *Store) ListWebhookSubscriptions(ctx context.Context) ([]*entity.WebhookSubscription, error) {
+	ctx, span := s.start(ctx, "ListWebhookSubscriptions")
+	subscriptions, err := s.next.ListWebhookSubscriptions(ctx)
+	end(span, err)
+	return subscriptions, err
+}
+
+func (s *Store) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	ctx, span := s.start(ctx, "DeleteWebhookSubscription")
+	err := s.next.DeleteWebhookSubscription(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (s *Store) EnqueueWebhookDeliveries(ctx context.Context, event entity.OrderEvent, now time.Time) (int64, error) {
+	ctx, span := s.start(ctx, "EnqueueWebhookDeliveries")
+	enqueued, err := s.next.EnqueueWebhookDeliveries(ctx, event, now)
+	end(span, err)
+	return enqueued, err
+}
+
+func (s *Store) ClaimWebhookDeliveries(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]store.ClaimedWebhookDelivery, error) {
+	ctx, span := s.start(ctx, "ClaimWebhookDeliveries")
+	deliveries, err := s.next.ClaimWebhookDeliveries(ctx, now, lease, limit)
+	end(span, err)
+	return deliveries, err
+}
+
+func (s *Store) MarkWebhookDelivered(ctx context.Context, id int64, deliveredAt time.Time) error {
+	ctx, span := s.start(ctx, "MarkWebhookDelivered")
+	err := s.next.MarkWebhookDelivered(ctx, id, deliveredAt)
+	end(span, err)
+	return err
+}
+
+func (s *Store) MarkWebhookDeliveryFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	ctx, span := s.start(ctx, "MarkWebhookDeliveryFailed")
+	err := s.next.MarkWebhookDeliveryFailed(ctx, id, nextAttemptAt, lastError)
+	end(span, err)
+	return err
+}
+
+func (s *Store) MarkWebhookDeliveryDead(ctx context.Context, id int64, lastError string) error {
+	ctx, span := s.start(ctx, "MarkWebhookDeliveryDead")
+	err := s.next.MarkWebhookDeliveryDead(ctx, id, lastError)
+	end(span, err)
+	return err
+}
+
+func (s *Store) ListWebhookDeliveries(ctx context.Context, query store.WebhookDeliveryQuery) (*store.WebhookDeliveryPage, error) {
+	ctx, span := s.start(ctx, "ListWebhookDeliveries")
+	page, err := s.next.ListWebhookDeliveries(ctx, query)
+	end(span, err)
+	return page, err
+}
+
+func (s *Store) ReplayWebhookDelivery(ctx context.Context, id int64, now time.Time) (*entity.WebhookDelivery, error) {
+	ctx, span := s.start(ctx, "ReplayWebhookDelivery")
+	delivery, err := s.next.ReplayWebhookDelivery(ctx, id, now)
+	end(span, err)
+	return delivery, err
+}
+
+func (s *Store) Ping(ctx context.Context) error {
+	ctx, span := s.start(ctx, "Ping")
+	err := s.next.Ping(ctx)
+	end(span, err)
+	return err
+}
+
+func (s *Store) Close() error {
+	return s.next.Close()
+}
@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestStore(t *testing.T) {
+	recorder := record(t)
+	var parent trace.SpanContext
+	s := NewStore(&store.MockOrderStore{
+		GetFunc: func(ctx context.Context, id string) (*entity.Order, error) {
+			parent = trace.SpanContextFromContext(ctx)
+			if id == "missing" {
+				return nil, store.ErrOrderNotFound
+			}
+			return &entity.Order{ID: id}, nil
+		},
+		PingFunc: func(ctx context.Context) error {
+			return nil
+		},
+	})
+	ctx, span := otel.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+
+	order, err := s.Get(ctx, "found")
+	require.NoError(t, err)
+	assert.Equal(t, "found", order.ID)
+	_, err = s.Get(ctx, "missing")
+	require.ErrorIs(t, err, store.ErrOrderNotFound, "Errors should be passed through")
+	require.NoError(t, s.Ping(ctx))
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 3)
+	assert.Equal(t, "OrderStore.Get", ended[0].Name())
+	assert.Equal(t, codes.Unset, ended[0].Status().Code)
+	assert.Equal(t, "OrderStore.Get", ended[1].Name())
+	assert.Equal(t, codes.Error, ended[1].Status().Code)
+	assert.Equal(t, ended[1].SpanContext(), parent, "The store should be called with the span context")
+	assert.Equal(t, "OrderStore.Ping", ended[2].Name())
+	assert.Equal(t, span.SpanContext().SpanID(), ended[2].Parent().SpanID())
+}
+
+func TestStore_OutsideOfTrace(t *testing.T) {
+	recorder := record(t)
+	s := NewStore(&store.MockOrderStore{
+		PingFunc: func(ctx context.Context) error {
+			return nil
+		},
+	})
+
+	require.NoError(t, s.Ping(context.Background()))
+	assert.Empty(t, recorder.Ended(), "Calls outside of a trace should not start one")
+}
@@ -0,0 +1,105 @@
+// Package tracing traces RPCs, handlers and store calls with OpenTelemetry and
+// propagates the trace context in W3C traceparent headers.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer of the spans started by this package.
+const instrumentationName = "github.com/demo/order/internal/tracing"
+
+// Exporters of Config.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+type Config struct {
+	// Exporter is ExporterNone, ExporterStdout or ExporterOTLP.
+	Exporter string
+	// OTLPEndpoint is the URL of the OTLP/HTTP collector, e.g.
+	// http://localhost:4318. When empty, the OTEL_EXPORTER_OTLP_* environment
+	// variables and the exporter defaults apply.
+	OTLPEndpoint string
+	// SampleRatio is the share of traces started by the service that are
+	// recorded. Traces started by callers follow their sampling decision.
+	SampleRatio float64
+}
+
+// Setup installs the global tracer provider and the W3C trace context and
+// baggage propagators. It returns a function that flushes the pending spans
+// and stops the exporter.
+func Setup(ctx context.Context, serviceName string, config Config) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	switch config.Exporter {
+	case ExporterNone:
+		// The default global provider does not record spans, but the trace
+		// context of callers is still propagated.
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		var options []otlptracehttp.Option
+		if config.OTLPEndpoint != "" {
+			options = append(options, otlptracehttp.WithEndpointURL(config.OTLPEndpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, options...)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", config.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create %s trace exporter: %w", config.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Run runs fn in a span named name, e.g. "CreateOrder.validate", and records its error.
+func Run(ctx context.Context, name string, fn func() error) error {
+	_, span := otel.Tracer(instrumentationName).Start(ctx, name)
+	err := fn()
+	end(span, err)
+	return err
+}
+
+// end records err on span, if any, and ends it.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
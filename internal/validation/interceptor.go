@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+)
+
+// Interceptor checks the request messages of the handlers it is added to
+// against their Rules before the handlers run. Invalid requests fail with
+// CodeInvalidArgument and a google.rpc.BadRequest detail, like the violations
+// handlers report themselves.
+type Interceptor struct {
+	rules *Rules
+}
+
+var _ connect.Interceptor = (*Interceptor)(nil)
+
+func NewInterceptor(rules *Rules) *Interceptor {
+	return &Interceptor{rules: rules}
+}
+
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		if err := i.validate(req.Any()); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return next(ctx, &validatingConn{StreamingHandlerConn: conn, interceptor: i})
+	}
+}
+
+func (i *Interceptor) validate(msg any) error {
+	protoMsg, ok := msg.(proto.Message)
+	if !ok {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("request of type %T is not a proto message", msg))
+	}
+	return i.rules.Validate(protoMsg)
+}
+
+// validatingConn checks each message a streaming handler receives.
+type validatingConn struct {
+	connect.StreamingHandlerConn
+	interceptor *Interceptor
+}
+
+func (c *validatingConn) Receive(msg any) error {
+	if err := c.StreamingHandlerConn.Receive(msg); err != nil {
+		return err
+	}
+	return c.interceptor.validate(msg)
+}
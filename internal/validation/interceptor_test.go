@@ -0,0 +1,79 @@
+package validation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/contracts/gen/go/order/v1/orderv1connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderService returns the requested ID, or the requested status of the watched orders.
+type orderService struct {
+	orderv1connect.UnimplementedOrderServiceHandler
+}
+
+func (orderService) GetOrder(
+	_ context.Context,
+	req *connect.Request[orderv1.GetOrderRequest],
+) (*connect.Response[orderv1.GetOrderResponse], error) {
+	return connect.NewResponse(&orderv1.GetOrderResponse{Order: &orderv1.Order{Id: req.Msg.Id}}), nil
+}
+
+func (orderService) WatchOrders(
+	_ context.Context,
+	req *connect.Request[orderv1.WatchOrdersRequest],
+	stream *connect.ServerStream[orderv1.WatchOrdersResponse],
+) error {
+	return stream.Send(&orderv1.WatchOrdersResponse{Order: &orderv1.Order{Status: req.Msg.Status}})
+}
+
+func TestInterceptor(t *testing.T) {
+	rules, err := NewRules(orderv1.File_order_v1_order_proto)
+	require.NoError(t, err)
+	path, handler := orderv1connect.NewOrderServiceHandler(orderService{},
+		connect.WithInterceptors(NewInterceptor(rules)))
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := orderv1connect.NewOrderServiceClient(server.Client(), server.URL)
+
+	t.Run("valid request", func(t *testing.T) {
+		resp, err := client.GetOrder(context.Background(), connect.NewRequest(&orderv1.GetOrderRequest{Id: "order-1"}))
+
+		require.NoError(t, err)
+		assert.Equal(t, "order-1", resp.Msg.Order.Id)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		_, err := client.GetOrder(context.Background(), connect.NewRequest(&orderv1.GetOrderRequest{}))
+
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+		assert.Equal(t, map[string]string{"id": "must be set"}, FieldViolations(err))
+	})
+
+	t.Run("valid streaming request", func(t *testing.T) {
+		stream, err := client.WatchOrders(context.Background(), connect.NewRequest(&orderv1.WatchOrdersRequest{Status: "NEW"}))
+		require.NoError(t, err)
+		defer stream.Close()
+
+		require.True(t, stream.Receive(), "The handler should run: %v", stream.Err())
+		assert.Equal(t, "NEW", stream.Msg().Order.Status)
+	})
+
+	t.Run("invalid streaming request", func(t *testing.T) {
+		stream, err := client.WatchOrders(context.Background(), connect.NewRequest(&orderv1.WatchOrdersRequest{Status: "SHIPPED"}))
+		require.NoError(t, err)
+		defer stream.Close()
+
+		assert.False(t, stream.Receive(), "The handler should not run")
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(stream.Err()))
+		assert.Equal(t, map[string]string{"status": orderStatuses}, FieldViolations(stream.Err()))
+	})
+}
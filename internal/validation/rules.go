@@ -0,0 +1,424 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// supportedRules lists the buf.validate rules Rules enforces by rule message.
+// Any other rule, including CEL expressions, is rejected by NewRules, so that
+// a rule added to the contract is never silently ignored.
+var supportedRules = map[protoreflect.FullName][]protoreflect.Name{
+	"buf.validate.FieldRules":    {"required", "ignore", "string", "int32", "int64", "double", "repeated"},
+	"buf.validate.StringRules":   {"min_len", "max_len", "min_bytes", "max_bytes", "in"},
+	"buf.validate.Int32Rules":    {"gt", "gte", "lt", "lte"},
+	"buf.validate.Int64Rules":    {"gt", "gte", "lt", "lte"},
+	"buf.validate.DoubleRules":   {"gt", "gte", "lt", "lte", "finite"},
+	"buf.validate.RepeatedRules": {"min_items", "max_items", "items"},
+}
+
+// Rules enforces the buf.validate field rules declared on the messages of proto files.
+type Rules struct {
+	messages map[protoreflect.FullName]*messageRules
+}
+
+// NewRules compiles the rules of the messages in files and of the messages they contain.
+// It fails on rules it does not support.
+func NewRules(files ...protoreflect.FileDescriptor) (*Rules, error) {
+	r := &Rules{messages: make(map[protoreflect.FullName]*messageRules)}
+	for _, file := range files {
+		messages := file.Messages()
+		for i := 0; i < messages.Len(); i++ {
+			if _, err := r.compileMessage(messages.Get(i)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return r, nil
+}
+
+// Validate returns nil if msg satisfies its rules, otherwise a CodeInvalidArgument
+// error with the violations, see Violations.Err. Messages of other files are valid.
+func (r *Rules) Validate(msg proto.Message) error {
+	m := msg.ProtoReflect()
+	rules := r.messages[m.Descriptor().FullName()]
+	if rules == nil {
+		return nil
+	}
+	var v Violations
+	rules.check(&v, "", m)
+	return v.Err()
+}
+
+type messageRules struct {
+	fields []*fieldRules
+}
+
+type fieldRules struct {
+	desc       protoreflect.FieldDescriptor
+	required   bool
+	ignoreZero bool
+	// list checks a repeated field as a whole, value a singular field or each item of a repeated one.
+	list  []check
+	value []check
+	// message is set for message fields.
+	message *messageRules
+}
+
+// check returns the description of the violation of a rule by v, or "" if v satisfies it.
+type check func(v protoreflect.Value) string
+
+// compileMessage returns the rules of desc, compiling them on first use.
+func (r *Rules) compileMessage(desc protoreflect.MessageDescriptor) (*messageRules, error) {
+	if rules, ok := r.messages[desc.FullName()]; ok {
+		return rules, nil
+	}
+	if proto.HasExtension(desc.Options(), validate.E_Message) {
+		return nil, fmt.Errorf("%s: message rules are not supported", desc.FullName())
+	}
+	// Registered before the fields are compiled, so that recursive messages terminate.
+	rules := &messageRules{}
+	r.messages[desc.FullName()] = rules
+
+	oneofs := desc.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		if proto.HasExtension(oneofs.Get(i).Options(), validate.E_Oneof) {
+			return nil, fmt.Errorf("%s: oneof rules are not supported", oneofs.Get(i).FullName())
+		}
+	}
+	nested := desc.Messages()
+	for i := 0; i < nested.Len(); i++ {
+		if _, err := r.compileMessage(nested.Get(i)); err != nil {
+			return nil, err
+		}
+	}
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field, err := r.compileField(fields.Get(i))
+		if err != nil {
+			return nil, err
+		}
+		if field != nil {
+			rules.fields = append(rules.fields, field)
+		}
+	}
+	return rules, nil
+}
+
+// compileField returns the rules of desc, or nil if it has none.
+func (r *Rules) compileField(desc protoreflect.FieldDescriptor) (*fieldRules, error) {
+	field := &fieldRules{desc: desc}
+	if desc.IsMap() {
+		if proto.HasExtension(desc.Options(), validate.E_Field) {
+			return nil, fmt.Errorf("%s: map rules are not supported", desc.FullName())
+		}
+		return nil, nil
+	}
+	if desc.Message() != nil {
+		// The fields of a message being compiled further up are filled in later.
+		message, err := r.compileMessage(desc.Message())
+		if err != nil {
+			return nil, err
+		}
+		field.message = message
+	}
+
+	if !proto.HasExtension(desc.Options(), validate.E_Field) {
+		if field.message == nil {
+			return nil, nil
+		}
+		return field, nil
+	}
+	rules := proto.GetExtension(desc.Options(), validate.E_Field).(*validate.FieldRules)
+	if err := checkSupported(rules.ProtoReflect()); err != nil {
+		return nil, fmt.Errorf("%s: %w", desc.FullName(), err)
+	}
+
+	switch rules.GetIgnore() {
+	case validate.Ignore_IGNORE_UNSPECIFIED:
+	case validate.Ignore_IGNORE_IF_ZERO_VALUE:
+		field.ignoreZero = true
+	case validate.Ignore_IGNORE_ALWAYS:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("%s: ignore %s is not supported", desc.FullName(), rules.GetIgnore())
+	}
+	field.required = rules.GetRequired()
+
+	var err error
+	if desc.IsList() {
+		if field.list, err = listChecks(rules); err != nil {
+			return nil, fmt.Errorf("%s: %w", desc.FullName(), err)
+		}
+		if items := rules.GetRepeated().GetItems(); items != nil {
+			if err := checkSupported(items.ProtoReflect()); err != nil {
+				return nil, fmt.Errorf("%s items: %w", desc.FullName(), err)
+			}
+			if items.HasRequired() || items.HasIgnore() || items.HasRepeated() {
+				return nil, fmt.Errorf("%s items: only type rules are supported", desc.FullName())
+			}
+			if field.value, err = valueChecks(desc.Kind(), items); err != nil {
+				return nil, fmt.Errorf("%s items: %w", desc.FullName(), err)
+			}
+		}
+	} else if field.value, err = valueChecks(desc.Kind(), rules); err != nil {
+		return nil, fmt.Errorf("%s: %w", desc.FullName(), err)
+	}
+	return field, nil
+}
+
+// checkSupported fails if rules sets a rule that is not in supportedRules.
+func checkSupported(rules protoreflect.Message) error {
+	supported := supportedRules[rules.Descriptor().FullName()]
+	var err error
+	rules.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if !slices.Contains(supported, fd.Name()) {
+			err = fmt.Errorf("rule %s is not supported", fd.FullName())
+			return false
+		}
+		if fd.Message() != nil {
+			err = checkSupported(v.Message())
+		}
+		return err == nil
+	})
+	return err
+}
+
+// listChecks returns the checks of the repeated rules of a repeated field.
+func listChecks(rules *validate.FieldRules) ([]check, error) {
+	if rules.HasString() || rules.HasInt32() || rules.HasInt64() || rules.HasDouble() {
+		return nil, errors.New("type rules of a repeated field belong in repeated.items")
+	}
+	repeated := rules.GetRepeated()
+	if repeated == nil {
+		return nil, nil
+	}
+	var checks []check
+	if repeated.HasMinItems() {
+		minItems := int(repeated.GetMinItems())
+		checks = append(checks, func(v protoreflect.Value) string {
+			if v.List().Len() < minItems {
+				return fmt.Sprintf("must have at least %d items", minItems)
+			}
+			return ""
+		})
+	}
+	if repeated.HasMaxItems() {
+		maxItems := int(repeated.GetMaxItems())
+		checks = append(checks, func(v protoreflect.Value) string {
+			if v.List().Len() > maxItems {
+				return fmt.Sprintf("must have at most %d items", maxItems)
+			}
+			return ""
+		})
+	}
+	return checks, nil
+}
+
+// valueChecks returns the checks of the type rules of a value of kind.
+func valueChecks(kind protoreflect.Kind, rules *validate.FieldRules) ([]check, error) {
+	if rules.HasRepeated() {
+		return nil, errors.New("repeated rules on a singular field")
+	}
+	switch {
+	case rules.HasString():
+		if kind != protoreflect.StringKind {
+			return nil, fmt.Errorf("string rules on a field of type %s", kind)
+		}
+		return stringChecks(rules.GetString()), nil
+	case rules.HasInt32():
+		if kind != protoreflect.Int32Kind {
+			return nil, fmt.Errorf("int32 rules on a field of type %s", kind)
+		}
+		return []check{numberCheck(rules.GetInt32().ProtoReflect(), protoreflect.Value.Int, formatInt)}, nil
+	case rules.HasInt64():
+		if kind != protoreflect.Int64Kind {
+			return nil, fmt.Errorf("int64 rules on a field of type %s", kind)
+		}
+		return []check{numberCheck(rules.GetInt64().ProtoReflect(), protoreflect.Value.Int, formatInt)}, nil
+	case rules.HasDouble():
+		if kind != protoreflect.DoubleKind {
+			return nil, fmt.Errorf("double rules on a field of type %s", kind)
+		}
+		checks := []check{numberCheck(rules.GetDouble().ProtoReflect(), protoreflect.Value.Float, formatFloat)}
+		if rules.GetDouble().GetFinite() {
+			checks = append([]check{func(v protoreflect.Value) string {
+				if math.IsNaN(v.Float()) || math.IsInf(v.Float(), 0) {
+					return "must be a finite number"
+				}
+				return ""
+			}}, checks...)
+		}
+		return checks, nil
+	}
+	return nil, nil
+}
+
+func stringChecks(rules *validate.StringRules) []check {
+	var checks []check
+	if rules.HasMinLen() {
+		minLen := int(rules.GetMinLen())
+		checks = append(checks, func(v protoreflect.Value) string {
+			if utf8.RuneCountInString(v.String()) < minLen {
+				return fmt.Sprintf("must be at least %d characters long", minLen)
+			}
+			return ""
+		})
+	}
+	if rules.HasMaxLen() {
+		maxLen := int(rules.GetMaxLen())
+		checks = append(checks, func(v protoreflect.Value) string {
+			if utf8.RuneCountInString(v.String()) > maxLen {
+				return fmt.Sprintf("must be at most %d characters long", maxLen)
+			}
+			return ""
+		})
+	}
+	if rules.HasMinBytes() {
+		minBytes := int(rules.GetMinBytes())
+		checks = append(checks, func(v protoreflect.Value) string {
+			if len(v.String()) < minBytes {
+				return fmt.Sprintf("must be at least %d bytes long", minBytes)
+			}
+			return ""
+		})
+	}
+	if rules.HasMaxBytes() {
+		maxBytes := int(rules.GetMaxBytes())
+		checks = append(checks, func(v protoreflect.Value) string {
+			if len(v.String()) > maxBytes {
+				return fmt.Sprintf("must be at most %d bytes long", maxBytes)
+			}
+			return ""
+		})
+	}
+	if in := rules.GetIn(); len(in) > 0 {
+		description := "must be one of " + strings.Join(in, ", ")
+		checks = append(checks, func(v protoreflect.Value) string {
+			if slices.Contains(in, v.String()) {
+				return ""
+			}
+			return description
+		})
+	}
+	return checks
+}
+
+// numberCheck returns the check of the gt, gte, lt and lte rules of a numeric type,
+// whose values are read with value. The bounds are described together, e.g.
+// "must be between 1 and 10".
+func numberCheck[T int64 | float64](
+	rules protoreflect.Message,
+	value func(protoreflect.Value) T,
+	format func(T) string,
+) check {
+	fields := rules.Descriptor().Fields()
+	bound := func(name protoreflect.Name) (T, bool) {
+		fd := fields.ByName(name)
+		if !rules.Has(fd) {
+			return 0, false
+		}
+		return value(rules.Get(fd)), true
+	}
+	gt, hasGT := bound("gt")
+	gte, hasGTE := bound("gte")
+	lt, hasLT := bound("lt")
+	lte, hasLTE := bound("lte")
+
+	var description string
+	switch {
+	case hasGTE && hasLTE:
+		description = fmt.Sprintf("must be between %s and %s", format(gte), format(lte))
+	case hasGT && gt == 0 && !hasLT && !hasLTE:
+		description = "must be positive"
+	case hasGTE && gte == 0 && !hasLT && !hasLTE:
+		description = "must not be negative"
+	default:
+		var bounds []string
+		if hasGT {
+			bounds = append(bounds, "greater than "+format(gt))
+		}
+		if hasGTE {
+			bounds = append(bounds, "at least "+format(gte))
+		}
+		if hasLT {
+			bounds = append(bounds, "less than "+format(lt))
+		}
+		if hasLTE {
+			bounds = append(bounds, "at most "+format(lte))
+		}
+		description = "must be " + strings.Join(bounds, " and ")
+	}
+
+	return func(v protoreflect.Value) string {
+		n := value(v)
+		if (hasGT && !(n > gt)) || (hasGTE && !(n >= gte)) || (hasLT && !(n < lt)) || (hasLTE && !(n <= lte)) {
+			return description
+		}
+		return ""
+	}
+}
+
+func formatInt(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func formatFloat(n float64) string {
+	return strconv.FormatFloat(n, 'g', -1, 64)
+}
+
+// check records the violations of m, whose fields are at path prefix.
+func (rules *messageRules) check(v *Violations, prefix string, m protoreflect.Message) {
+	for _, field := range rules.fields {
+		field.check(v, prefix+string(field.desc.Name()), m)
+	}
+}
+
+func (field *fieldRules) check(v *Violations, path string, m protoreflect.Message) {
+	// Has reports whether a field without presence differs from its zero value.
+	if !m.Has(field.desc) {
+		if field.required {
+			v.Add(path, "must be set")
+			return
+		}
+		if field.ignoreZero || field.desc.HasPresence() {
+			return
+		}
+	}
+	value := m.Get(field.desc)
+
+	if !field.desc.IsList() {
+		field.checkValue(v, path, value)
+		return
+	}
+	for _, c := range field.list {
+		if description := c(value); description != "" {
+			v.Add(path, description)
+		}
+	}
+	list := value.List()
+	for i := 0; i < list.Len(); i++ {
+		field.checkValue(v, fmt.Sprintf("%s[%d]", path, i), list.Get(i))
+	}
+}
+
+func (field *fieldRules) checkValue(v *Violations, path string, value protoreflect.Value) {
+	if field.message != nil {
+		field.message.check(v, path+".", value.Message())
+		return
+	}
+	for _, c := range field.value {
+		if description := c(value); description != "" {
+			v.Add(path, description)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package validation
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const orderStatuses = "must be one of NEW, IN_PROGRESS, FINISHED, CANCELLED"
+
+func TestRules_Validate(t *testing.T) {
+	rules, err := NewRules(orderv1.File_order_v1_order_proto)
+	require.NoError(t, err)
+
+	tooManyItems := make([]*orderv1.OrderItem, 101)
+	for i := range tooManyItems {
+		tooManyItems[i] = &orderv1.OrderItem{Sku: "SKU-1", Quantity: 1, UnitPrice: 1}
+	}
+
+	testCases := []struct {
+		name           string
+		msg            proto.Message
+		wantViolations map[string]string
+	}{
+		{
+			name:           "required field is empty",
+			msg:            &orderv1.GetOrderRequest{},
+			wantViolations: map[string]string{"id": "must be set"},
+		},
+		{
+			name: "required field is set",
+			msg:  &orderv1.GetOrderRequest{Id: "order-1"},
+		},
+		{
+			name: "optional filters are empty",
+			msg:  &orderv1.ListOrdersRequest{},
+		},
+		{
+			name: "optional filters are invalid",
+			msg:  &orderv1.ListOrdersRequest{PageSize: -1, Status: "SHIPPED"},
+			wantViolations: map[string]string{
+				"page_size": "must not be negative",
+				"status":    orderStatuses,
+			},
+		},
+		{
+			name:           "value from a list is empty",
+			msg:            &orderv1.UpdateOrderStatusRequest{Id: "order-1"},
+			wantViolations: map[string]string{"status": orderStatuses},
+		},
+		{
+			name: "items are checked by their own rules",
+			msg: &orderv1.CreateOrderRequest{
+				UserId: "user-1",
+				Items: []*orderv1.OrderItem{
+					{Sku: "SKU-1", Quantity: 1, UnitPrice: 1},
+					{Quantity: 0, UnitPrice: -1},
+				},
+			},
+			wantViolations: map[string]string{
+				"items[1].sku":        "must be set",
+				"items[1].quantity":   "must be between 1 and 10000",
+				"items[1].unit_price": "must be positive",
+			},
+		},
+		{
+			name: "too many items",
+			msg: &orderv1.CreateOrderRequest{
+				UserId: "user-1",
+				Items:  tooManyItems,
+			},
+			wantViolations: map[string]string{"items": "must have at most 100 items"},
+		},
+		{
+			name: "length is counted in characters",
+			msg: &orderv1.CancelOrderRequest{
+				Id:      "order-1",
+				Reason:  "OTHER",
+				Comment: strings.Repeat("й", 1000),
+			},
+		},
+		{
+			name: "too many characters",
+			msg: &orderv1.CancelOrderRequest{
+				Id:      "order-1",
+				Reason:  "BORED",
+				Comment: strings.Repeat("й", 1001),
+			},
+			wantViolations: map[string]string{
+				"reason":  "must be one of CUSTOMER_REQUEST, CREATED_BY_MISTAKE, DUPLICATE, OUT_OF_STOCK, FRAUD_SUSPECTED, OTHER",
+				"comment": "must be at most 1000 characters long",
+			},
+		},
+		{
+			name: "repeated values are checked one by one",
+			msg: &orderv1.CreateWebhookSubscriptionRequest{
+				Url:        "https://partner.example.com/hooks",
+				Secret:     "too short",
+				EventTypes: []string{"ORDER_CREATED", "ORDER_SHIPPED"},
+			},
+			wantViolations: map[string]string{
+				"secret":         "must be at least 16 bytes long",
+				"event_types[1]": "must be one of ORDER_CREATED, ORDER_STATUS_CHANGED, ORDER_CANCELLED, ORDER_DELETED, ORDER_RESTORED",
+			},
+		},
+		{
+			name: "message of another file",
+			msg:  &errdetails.BadRequest{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := rules.Validate(tc.msg)
+
+			if tc.wantViolations == nil {
+				require.NoError(t, err)
+				return
+			}
+			assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+			assert.Equal(t, tc.wantViolations, FieldViolations(err))
+		})
+	}
+}
+
+func TestRules_Bounds(t *testing.T) {
+	testCases := []struct {
+		name            string
+		kind            descriptorpb.FieldDescriptorProto_Type
+		rules           *validate.FieldRules
+		value           protoreflect.Value
+		wantDescription string
+	}{
+		{
+			name: "exclusive bounds",
+			kind: descriptorpb.FieldDescriptorProto_TYPE_INT64,
+			rules: validate.FieldRules_builder{
+				Int64: validate.Int64Rules_builder{Gt: proto.Int64(1), Lt: proto.Int64(5)}.Build(),
+			}.Build(),
+			value:           protoreflect.ValueOfInt64(5),
+			wantDescription: "must be greater than 1 and less than 5",
+		},
+		{
+			name: "value within exclusive bounds",
+			kind: descriptorpb.FieldDescriptorProto_TYPE_INT64,
+			rules: validate.FieldRules_builder{
+				Int64: validate.Int64Rules_builder{Gt: proto.Int64(1), Lt: proto.Int64(5)}.Build(),
+			}.Build(),
+			value: protoreflect.ValueOfInt64(4),
+		},
+		{
+			name: "upper bound",
+			kind: descriptorpb.FieldDescriptorProto_TYPE_INT32,
+			rules: validate.FieldRules_builder{
+				Int32: validate.Int32Rules_builder{Lte: proto.Int32(10)}.Build(),
+			}.Build(),
+			value:           protoreflect.ValueOfInt32(11),
+			wantDescription: "must be at most 10",
+		},
+		{
+			name: "fractional bound",
+			kind: descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+			rules: validate.FieldRules_builder{
+				Double: validate.DoubleRules_builder{Gte: proto.Float64(0.5)}.Build(),
+			}.Build(),
+			value:           protoreflect.ValueOfFloat64(0.25),
+			wantDescription: "must be at least 0.5",
+		},
+		{
+			name: "finite number",
+			kind: descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+			rules: validate.FieldRules_builder{
+				Double: validate.DoubleRules_builder{Finite: proto.Bool(true)}.Build(),
+			}.Build(),
+			value:           protoreflect.ValueOfFloat64(math.Inf(1)),
+			wantDescription: "must be a finite number",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file := testFile(t, tc.kind, tc.rules)
+			rules, err := NewRules(file)
+			require.NoError(t, err)
+			msg := dynamicpb.NewMessage(file.Messages().Get(0))
+			msg.Set(file.Messages().Get(0).Fields().Get(0), tc.value)
+
+			err = rules.Validate(msg)
+
+			if tc.wantDescription == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.Equal(t, map[string]string{"value": tc.wantDescription}, FieldViolations(err))
+		})
+	}
+}
+
+func TestNewRules_Unsupported(t *testing.T) {
+	testCases := []struct {
+		name    string
+		kind    descriptorpb.FieldDescriptorProto_Type
+		rules   *validate.FieldRules
+		wantErr string
+	}{
+		{
+			name: "unsupported rule",
+			kind: descriptorpb.FieldDescriptorProto_TYPE_STRING,
+			rules: validate.FieldRules_builder{
+				String: validate.StringRules_builder{Pattern: proto.String("^[a-z]+$")}.Build(),
+			}.Build(),
+			wantErr: "test.v1.Request.value: rule buf.validate.StringRules.pattern is not supported",
+		},
+		{
+			name: "CEL expression",
+			kind: descriptorpb.FieldDescriptorProto_TYPE_STRING,
+			rules: validate.FieldRules_builder{
+				Cel: []*validate.Rule{validate.Rule_builder{Expression: proto.String("this != ''")}.Build()},
+			}.Build(),
+			wantErr: "test.v1.Request.value: rule buf.validate.FieldRules.cel is not supported",
+		},
+		{
+			name: "rules of another type",
+			kind: descriptorpb.FieldDescriptorProto_TYPE_INT32,
+			rules: validate.FieldRules_builder{
+				String: validate.StringRules_builder{MinLen: proto.Uint64(1)}.Build(),
+			}.Build(),
+			wantErr: "test.v1.Request.value: string rules on a field of type int32",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewRules(testFile(t, tc.kind, tc.rules))
+
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
+
+// testFile returns a proto file with a message test.v1.Request whose field
+// value is of kind and has rules.
+func testFile(t *testing.T, kind descriptorpb.FieldDescriptorProto_Type, rules *validate.FieldRules) protoreflect.FileDescriptor {
+	t.Helper()
+	options := &descriptorpb.FieldOptions{}
+	proto.SetExtension(options, validate.E_Field, rules)
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test/v1/test.proto"),
+		Package:    proto.String("test.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"buf/validate/validate.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Request"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("value"),
+				JsonName: proto.String("value"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     kind.Enum(),
+				Options:  options,
+			}},
+		}},
+	}, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+	return file
+}
@@ -0,0 +1,93 @@
+// Package validation collects the field violations of a request and reports
+// them all at once as a Connect error with a google.rpc.BadRequest detail.
+package validation
+
+import (
+	"errors"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// Violations collects the field violations of a request. The zero value has none.
+type Violations struct {
+	violations []*errdetails.BadRequest_FieldViolation
+}
+
+// Add records that field is invalid. The field is the path of the field in
+// the request message, e.g. "items[0].sku".
+func (v *Violations) Add(field, description string) {
+	v.violations = append(v.violations, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: description,
+	})
+}
+
+// Check records that field is invalid unless ok, and returns ok.
+func (v *Violations) Check(ok bool, field, description string) bool {
+	if !ok {
+		v.Add(field, description)
+	}
+	return ok
+}
+
+// Len returns the number of violations recorded.
+func (v *Violations) Len() int {
+	return len(v.violations)
+}
+
+// Err returns nil without violations, otherwise a CodeInvalidArgument error
+// listing them in its message and in a google.rpc.BadRequest detail.
+func (v *Violations) Err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	messages := make([]string, len(v.violations))
+	for i, violation := range v.violations {
+		messages[i] = violation.Field + ": " + violation.Description
+	}
+	err := connect.NewError(connect.CodeInvalidArgument, errors.New(strings.Join(messages, "; ")))
+	if detail, detailErr := connect.NewErrorDetail(&errdetails.BadRequest{FieldViolations: v.violations}); detailErr == nil {
+		err.AddDetail(detail)
+	}
+	return err
+}
+
+// Error returns the error of a single field violation.
+func Error(field, description string) error {
+	var v Violations
+	v.Add(field, description)
+	return v.Err()
+}
+
+// FieldViolations returns the descriptions of the field violations in the
+// google.rpc.BadRequest detail of err by field, or nil if there is none.
+func FieldViolations(err error) map[string]string {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return nil
+	}
+	var result map[string]string
+	for _, detail := range connectErr.Details() {
+		value, err := detail.Value()
+		if err != nil {
+			continue
+		}
+		badRequest, ok := value.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		for _, violation := range badRequest.FieldViolations {
+			if description, ok := result[violation.Field]; ok {
+				result[violation.Field] = description + "; " + violation.Description
+			} else {
+				result[violation.Field] = violation.Description
+			}
+		}
+	}
+	return result
+}
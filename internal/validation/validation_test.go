@@ -0,0 +1,46 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViolations(t *testing.T) {
+	var v Violations
+	require.NoError(t, v.Err(), "No violations should be no error")
+
+	assert.True(t, v.Check(true, "id", "must be set"))
+	assert.False(t, v.Check(false, "user_id", "must be set"))
+	v.Add("items[0].quantity", "must be between 1 and 10")
+	assert.Equal(t, 2, v.Len())
+
+	err := v.Err()
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	assert.EqualError(t, err, "invalid_argument: user_id: must be set; items[0].quantity: must be between 1 and 10")
+	assert.Equal(t, map[string]string{
+		"user_id":           "must be set",
+		"items[0].quantity": "must be between 1 and 10",
+	}, FieldViolations(err))
+}
+
+func TestError(t *testing.T) {
+	err := Error("page_token", "invalid page token")
+
+	assert.EqualError(t, err, "invalid_argument: page_token: invalid page token")
+	assert.Equal(t, map[string]string{"page_token": "invalid page token"}, FieldViolations(err))
+}
+
+func TestFieldViolations(t *testing.T) {
+	var v Violations
+	v.Add("status", "unknown status \"DONE\"")
+	v.Add("status", "orders are cancelled via CancelOrder")
+	assert.Equal(t, map[string]string{"status": "unknown status \"DONE\"; orders are cancelled via CancelOrder"},
+		FieldViolations(v.Err()), "Violations of the same field should be joined")
+
+	assert.Nil(t, FieldViolations(errors.New("plain error")))
+	assert.Nil(t, FieldViolations(connect.NewError(connect.CodeInvalidArgument, errors.New("no details"))))
+}
@@ -0,0 +1,29 @@
+// Package webhook delivers order events to partner endpoints registered as webhook subscriptions.
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/demo/order/internal/entity"
+)
+
+// EnqueueStore is the part of store.OrderStore the dispatcher works with.
+type EnqueueStore interface {
+	EnqueueWebhookDeliveries(ctx context.Context, event entity.OrderEvent, now time.Time) (int64, error)
+}
+
+// Dispatcher is the outbox publisher for webhooks: it turns every order event
+// into a pending delivery for each matching subscription. The Worker sends them.
+type Dispatcher struct {
+	store EnqueueStore
+}
+
+func NewDispatcher(store EnqueueStore) *Dispatcher {
+	return &Dispatcher{store: store}
+}
+
+func (d *Dispatcher) Publish(ctx context.Context, event entity.OrderEvent) error {
+	_, err := d.store.EnqueueWebhookDeliveries(ctx, event, time.Now().UTC())
+	return err
+}
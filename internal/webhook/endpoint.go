@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"syscall"
+)
+
+// ErrForbiddenAddress is returned for endpoints in networks webhooks must not reach.
+var ErrForbiddenAddress = errors.New("address is not public")
+
+// nonPublicPrefixes are the ranges isPublicAddr rejects besides those of the netip.Addr predicates.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+}
+
+// isPublicAddr reports whether webhooks may be sent to addr. Loopback, private,
+// link-local (such as the 169.254.169.254 metadata endpoint of cloud providers),
+// multicast and unspecified addresses are reserved for the network of the service.
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// Resolver looks up the addresses of endpoint hosts, *net.Resolver implements it.
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// EndpointPolicy keeps subscriptions from turning webhooks against the network of
+// the service (SSRF): endpoints must use https and their hosts must resolve to
+// public addresses only.
+type EndpointPolicy struct {
+	// AllowInsecure lifts both restrictions. It is meant for local development and tests.
+	AllowInsecure bool
+	// Resolver is net.DefaultResolver if nil.
+	Resolver Resolver
+}
+
+// CheckURL checks the endpoint of a new subscription. Since the host may resolve
+// differently later, the worker checks the address again when it connects.
+func (p EndpointPolicy) CheckURL(ctx context.Context, u *url.URL) error {
+	if p.AllowInsecure {
+		return nil
+	}
+	if u.Scheme != "https" {
+		return errors.New("url must use https")
+	}
+
+	host := u.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !isPublicAddr(addr) {
+			return fmt.Errorf("url host %s: %w", host, ErrForbiddenAddress)
+		}
+		return nil
+	}
+	resolver := p.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	addrs, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("url host %s cannot be resolved", host)
+	}
+	for _, addr := range addrs {
+		if !isPublicAddr(addr) {
+			return fmt.Errorf("url host %s resolves to %s: %w", host, addr, ErrForbiddenAddress)
+		}
+	}
+	return nil
+}
+
+// control is a net.Dialer.Control refusing connections to addresses that are not public.
+// It runs after DNS resolution, so a host cannot pass CheckURL and resolve to a private address later.
+func (p EndpointPolicy) control(_, address string, _ syscall.RawConn) error {
+	if p.AllowInsecure {
+		return nil
+	}
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !isPublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("connect to %s: %w", address, ErrForbiddenAddress)
+	}
+	return nil
+}
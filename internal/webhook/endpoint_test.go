@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResolver resolves hosts from a map instead of DNS.
+type fakeResolver map[string][]netip.Addr
+
+func (r fakeResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func TestEndpointPolicy_CheckURL(t *testing.T) {
+	policy := EndpointPolicy{Resolver: fakeResolver{
+		"partner.example.com":  {netip.MustParseAddr("203.0.113.10"), netip.MustParseAddr("2001:db8::10")},
+		"internal.example.com": {netip.MustParseAddr("203.0.113.10"), netip.MustParseAddr("10.0.0.5")},
+	}}
+
+	testCases := []struct {
+		name      string
+		url       string
+		policy    EndpointPolicy
+		wantErr   string
+		forbidden bool
+	}{
+		{name: "public host", url: "https://partner.example.com/hooks", policy: policy},
+		{name: "public IP", url: "https://203.0.113.10/hooks", policy: policy},
+		{name: "http", url: "http://partner.example.com/hooks", policy: policy, wantErr: "url must use https"},
+		{name: "loopback", url: "https://127.0.0.1/hooks", policy: policy, forbidden: true},
+		{name: "IPv6 loopback", url: "https://[::1]:8443/hooks", policy: policy, forbidden: true},
+		{name: "private", url: "https://192.168.1.10/hooks", policy: policy, forbidden: true},
+		{name: "metadata endpoint", url: "https://169.254.169.254/latest/meta-data", policy: policy, forbidden: true},
+		{name: "IPv4-mapped loopback", url: "https://[::ffff:127.0.0.1]/hooks", policy: policy, forbidden: true},
+		{name: "unspecified", url: "https://0.0.0.0/hooks", policy: policy, forbidden: true},
+		{name: "host resolving to private address", url: "https://internal.example.com/hooks", policy: policy, forbidden: true},
+		{name: "unknown host", url: "https://unknown.example.com/hooks", policy: policy, wantErr: "url host unknown.example.com cannot be resolved"},
+		{name: "insecure allowed", url: "http://127.0.0.1:8080/hooks", policy: EndpointPolicy{AllowInsecure: true}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.url)
+			require.NoError(t, err)
+
+			err = tc.policy.CheckURL(context.Background(), u)
+			switch {
+			case tc.forbidden:
+				assert.ErrorIs(t, err, ErrForbiddenAddress)
+			case tc.wantErr != "":
+				assert.EqualError(t, err, tc.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEndpointPolicy_Control(t *testing.T) {
+	testCases := []struct {
+		address   string
+		forbidden bool
+	}{
+		{address: "203.0.113.10:443"},
+		{address: "[2001:db8::10]:443"},
+		{address: "127.0.0.1:443", forbidden: true},
+		{address: "10.1.2.3:443", forbidden: true},
+		{address: "172.16.0.1:443", forbidden: true},
+		{address: "100.64.0.1:443", forbidden: true},
+		{address: "169.254.169.254:80", forbidden: true},
+		{address: "[fe80::1]:443", forbidden: true},
+		{address: "[fd00::1]:443", forbidden: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.address, func(t *testing.T) {
+			err := EndpointPolicy{}.control("tcp4", tc.address, nil)
+			if tc.forbidden {
+				assert.ErrorIs(t, err, ErrForbiddenAddress)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, EndpointPolicy{AllowInsecure: true}.control("tcp4", tc.address, nil))
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignVerify(t *testing.T) {
+	const secret = "whsec_test"
+	body := []byte(`{"event_id":"1"}`)
+	sentAt := time.Unix(1700000000, 0)
+	header := Sign(secret, sentAt, body)
+
+	assert.Regexp(t, `^t=1700000000,v1=[0-9a-f]{64}$`, header)
+
+	testCases := []struct {
+		name    string
+		secret  string
+		header  string
+		body    []byte
+		now     time.Time
+		wantErr bool
+	}{
+		{name: "valid", secret: secret, header: header, body: body, now: sentAt.Add(time.Minute)},
+		{name: "wrong secret", secret: "other", header: header, body: body, now: sentAt, wantErr: true},
+		{name: "tampered body", secret: secret, header: header, body: []byte(`{"event_id":"2"}`), now: sentAt, wantErr: true},
+		{name: "stale timestamp", secret: secret, header: header, body: body, now: sentAt.Add(time.Hour), wantErr: true},
+		{name: "timestamp from the future", secret: secret, header: header, body: body, now: sentAt.Add(-time.Hour), wantErr: true},
+		{name: "malformed header", secret: secret, header: "v1=abc", body: body, now: sentAt, wantErr: true},
+		{name: "empty header", secret: secret, header: "", body: body, now: sentAt, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Verify(tc.secret, tc.header, tc.body, tc.now, 5*time.Minute)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidSignature)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
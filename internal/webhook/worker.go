@@ -0,0 +1,228 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+)
+
+// Headers sent with every delivery besides SignatureHeader.
+const (
+	DeliveryIDHeader = "Webhook-Id"
+	EventIDHeader    = "Webhook-Event-Id"
+	EventTypeHeader  = "Webhook-Event"
+)
+
+// DeliveryStore is the part of store.OrderStore the worker works with.
+type DeliveryStore interface {
+	ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]store.ClaimedWebhookDelivery, error)
+	MarkWebhookDelivered(ctx context.Context, id int64, deliveredAt time.Time) error
+	MarkWebhookDeliveryFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+	MarkWebhookDeliveryDead(ctx context.Context, id int64, lastError string) error
+}
+
+type WorkerConfig struct {
+	// BatchSize is the maximum number of deliveries claimed at once.
+	BatchSize int
+	// Concurrency is the number of deliveries of a batch sent in parallel.
+	Concurrency int
+	// PollInterval is the pause between polls when there are no due deliveries.
+	PollInterval time.Duration
+	// Lease is how long claimed deliveries are hidden from other workers. It must
+	// be longer than sending a batch takes, or deliveries are sent twice.
+	Lease time.Duration
+	// Timeout bounds a single request to an endpoint.
+	Timeout time.Duration
+	// MinBackoff and MaxBackoff bound the exponential delay between attempts.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// MaxAttempts is the number of failed attempts after which a delivery is dead.
+	MaxAttempts int
+	// AllowInsecureURLs lets deliveries go to http endpoints and addresses that
+	// are not public, see EndpointPolicy. It is meant for local development and tests.
+	AllowInsecureURLs bool
+}
+
+func DefaultWorkerConfig() WorkerConfig {
+	return WorkerConfig{
+		BatchSize:    50,
+		Concurrency:  10,
+		PollInterval: time.Second,
+		Lease:        2 * time.Minute,
+		Timeout:      10 * time.Second,
+		MinBackoff:   5 * time.Second,
+		MaxBackoff:   time.Hour,
+		MaxAttempts:  15,
+	}
+}
+
+// Worker sends pending deliveries to their endpoints. A delivery succeeds when the
+// endpoint responds with 2xx; any other outcome is retried with exponential backoff
+// until MaxAttempts is reached and the delivery becomes dead.
+type Worker struct {
+	store  DeliveryStore
+	client *http.Client
+	config WorkerConfig
+}
+
+func NewWorker(store DeliveryStore, config WorkerConfig) *Worker {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   EndpointPolicy{AllowInsecure: config.AllowInsecureURLs}.control,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Through a proxy the dialer would check the address of the proxy instead of the endpoint.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   config.Timeout,
+		// A redirect is reported as a failed attempt rather than followed to another host.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return &Worker{store: store, client: client, config: config}
+}
+
+// Run sends deliveries until ctx is done.
+func (w *Worker) Run(ctx context.Context) {
+	for {
+		sent, err := w.DeliverBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("Failed to deliver webhooks", "error", err)
+		}
+		// A full batch means more deliveries are probably due.
+		if err == nil && sent == w.config.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.config.PollInterval):
+		}
+	}
+}
+
+// DeliverBatch claims one batch of due deliveries and sends them. It returns the number of claimed deliveries.
+func (w *Worker) DeliverBatch(ctx context.Context) (int, error) {
+	deliveries, err := w.store.ClaimWebhookDeliveries(ctx, time.Now().UTC(), w.config.Lease, w.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, max(w.config.Concurrency, 1))
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(delivery store.ClaimedWebhookDelivery) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			w.deliver(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+	return len(deliveries), nil
+}
+
+// deliver sends the delivery once and records the outcome.
+func (w *Worker) deliver(ctx context.Context, delivery store.ClaimedWebhookDelivery) {
+	sendErr := w.send(ctx, delivery)
+	if sendErr == nil {
+		// If this fails the delivery is sent again once its lease expires.
+		if err := w.store.MarkWebhookDelivered(ctx, delivery.ID, time.Now().UTC()); err != nil {
+			slog.Error("Failed to mark webhook delivery as delivered", "delivery_id", delivery.ID, "error", err)
+		}
+		return
+	}
+
+	attempt := delivery.Attempts + 1
+	if attempt >= w.config.MaxAttempts {
+		slog.Warn("Webhook delivery is dead",
+			"delivery_id", delivery.ID, "url", delivery.URL, "attempt", attempt, "error", sendErr)
+		if err := w.store.MarkWebhookDeliveryDead(ctx, delivery.ID, sendErr.Error()); err != nil {
+			slog.Error("Failed to mark webhook delivery as dead", "delivery_id", delivery.ID, "error", err)
+		}
+		return
+	}
+
+	slog.Warn("Failed to deliver webhook", "delivery_id", delivery.ID, "url", delivery.URL, "attempt", attempt, "error", sendErr)
+	nextAttemptAt := time.Now().UTC().Add(w.backoff(delivery.Attempts))
+	if err := w.store.MarkWebhookDeliveryFailed(ctx, delivery.ID, nextAttemptAt, sendErr.Error()); err != nil {
+		slog.Error("Failed to record failure of webhook delivery", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// Envelope is the JSON body of a delivery.
+type Envelope struct {
+	// EventID is the same for every delivery of the event, receivers can use it to drop duplicates.
+	EventID   string                `json:"event_id"`
+	EventType entity.OrderEventType `json:"event_type"`
+	// Data is the entity.OrderEventPayload of the event.
+	Data json.RawMessage `json:"data"`
+}
+
+func (w *Worker) send(ctx context.Context, delivery store.ClaimedWebhookDelivery) error {
+	eventID := strconv.FormatInt(delivery.EventID, 10)
+	body, err := json.Marshal(Envelope{
+		EventID:   eventID,
+		EventType: delivery.EventType,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	// Subscriptions created before https was required may still use http.
+	if req.URL.Scheme != "https" && !w.config.AllowInsecureURLs {
+		return errors.New("endpoint url must use https")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, time.Now(), body))
+	req.Header.Set(DeliveryIDHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(EventIDHeader, eventID)
+	req.Header.Set(EventTypeHeader, string(delivery.EventType))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Draining a bounded part of the body lets the connection be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// backoff returns the delay before the next attempt after attempts earlier failures.
+func (w *Worker) backoff(attempts int) time.Duration {
+	delay := w.config.MinBackoff
+	for i := 0; i < attempts && delay < w.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, w.config.MaxBackoff)
+}
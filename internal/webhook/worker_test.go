@@ -0,0 +1,243 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/demo/order/internal/entity"
+	"github.com/demo/order/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "whsec_test"
+
+// endpoint is an httptest.Server standing in for a partner endpoint.
+type endpoint struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newEndpoint(t *testing.T) *endpoint {
+	e := &endpoint{status: http.StatusOK}
+	e.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.requests = append(e.requests, r)
+		e.bodies = append(e.bodies, body)
+		w.WriteHeader(e.status)
+	}))
+	t.Cleanup(e.Close)
+	return e
+}
+
+func (e *endpoint) respondWith(status int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.status = status
+}
+
+// newDelivery subscribes url to all events, creates an order and enqueues its ORDER_CREATED event.
+func newDelivery(t *testing.T, orderStore *store.MemoryStore, url string) {
+	ctx := context.Background()
+	require.NoError(t, orderStore.CreateWebhookSubscription(ctx, &entity.WebhookSubscription{
+		ID:        "subscription-1",
+		URL:       url,
+		Secret:    testSecret,
+		CreatedAt: time.Now(),
+	}))
+
+	amount := entity.NewMoney(1000, entity.DefaultCurrency)
+	require.NoError(t, orderStore.Create(ctx, &entity.Order{
+		ID:        "order-1",
+		UserID:    "user-123",
+		Items:     []entity.OrderItem{{SKU: "SKU-1", Name: "Widget", Quantity: 1, UnitPrice: amount}},
+		Amount:    amount,
+		Status:    entity.OrderStatusNew,
+		CreatedAt: time.Now(),
+	}))
+	events, err := orderStore.ClaimOrderEvents(ctx, time.Now(), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.NoError(t, NewDispatcher(orderStore).Publish(ctx, events[0]))
+}
+
+// testWorkerConfig is DefaultWorkerConfig allowing deliveries to the httptest endpoints on localhost.
+func testWorkerConfig() WorkerConfig {
+	config := DefaultWorkerConfig()
+	config.AllowInsecureURLs = true
+	return config
+}
+
+func listDeliveries(t *testing.T, orderStore *store.MemoryStore) []*entity.WebhookDelivery {
+	page, err := orderStore.ListWebhookDeliveries(context.Background(), store.WebhookDeliveryQuery{PageSize: 10})
+	require.NoError(t, err)
+	return page.Deliveries
+}
+
+func TestWorker_DeliversSignedEvent(t *testing.T) {
+	ctx := context.Background()
+	orderStore := store.NewMemoryStore()
+	server := newEndpoint(t)
+	newDelivery(t, orderStore, server.URL)
+
+	sent, err := NewWorker(orderStore, testWorkerConfig()).DeliverBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	require.Len(t, server.requests, 1)
+	req, body := server.requests[0], server.bodies[0]
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Equal(t, "ORDER_CREATED", req.Header.Get(EventTypeHeader))
+	assert.NotEmpty(t, req.Header.Get(DeliveryIDHeader))
+	assert.NoError(t, Verify(testSecret, req.Header.Get(SignatureHeader), body, time.Now(), time.Minute))
+
+	var envelope Envelope
+	require.NoError(t, json.Unmarshal(body, &envelope))
+	assert.Equal(t, req.Header.Get(EventIDHeader), envelope.EventID)
+	assert.Equal(t, entity.OrderEventCreated, envelope.EventType)
+	var payload entity.OrderEventPayload
+	require.NoError(t, json.Unmarshal(envelope.Data, &payload))
+	assert.Equal(t, "order-1", payload.OrderID)
+	assert.Equal(t, entity.OrderStatusNew, payload.Status)
+
+	deliveries := listDeliveries(t, orderStore)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, entity.WebhookDeliveryDelivered, deliveries[0].Status)
+	assert.NotNil(t, deliveries[0].DeliveredAt)
+}
+
+func TestWorker_RetriesWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	orderStore := store.NewMemoryStore()
+	server := newEndpoint(t)
+	server.respondWith(http.StatusServiceUnavailable)
+	newDelivery(t, orderStore, server.URL)
+
+	config := testWorkerConfig()
+	config.MinBackoff = time.Hour
+	worker := NewWorker(orderStore, config)
+
+	before := time.Now().UTC()
+	_, err := worker.DeliverBatch(ctx)
+	require.NoError(t, err)
+
+	deliveries := listDeliveries(t, orderStore)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, entity.WebhookDeliveryPending, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, "endpoint responded with status 503", deliveries[0].LastError)
+	assert.False(t, deliveries[0].NextAttemptAt.Before(before.Add(time.Hour)), "Next attempt should be backed off")
+
+	// The delivery is not due again until the backoff has passed.
+	sent, err := worker.DeliverBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	assert.Len(t, server.requests, 1)
+}
+
+func TestWorker_DeadLetterAndReplay(t *testing.T) {
+	ctx := context.Background()
+	orderStore := store.NewMemoryStore()
+	server := newEndpoint(t)
+	server.respondWith(http.StatusInternalServerError)
+	newDelivery(t, orderStore, server.URL)
+
+	config := testWorkerConfig()
+	config.MinBackoff = time.Nanosecond
+	config.MaxBackoff = time.Nanosecond
+	config.MaxAttempts = 2
+	worker := NewWorker(orderStore, config)
+
+	for i := 0; i < 3; i++ {
+		_, err := worker.DeliverBatch(ctx)
+		require.NoError(t, err)
+	}
+	assert.Len(t, server.requests, 2, "Dead delivery should not be attempted again")
+
+	deliveries := listDeliveries(t, orderStore)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, entity.WebhookDeliveryDead, deliveries[0].Status)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+
+	// Once the endpoint is fixed, a replayed delivery goes through.
+	server.respondWith(http.StatusNoContent)
+	_, err := orderStore.ReplayWebhookDelivery(ctx, deliveries[0].ID, time.Now())
+	require.NoError(t, err)
+	sent, err := worker.DeliverBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	deliveries = listDeliveries(t, orderStore)
+	assert.Equal(t, entity.WebhookDeliveryDelivered, deliveries[0].Status)
+	assert.Len(t, server.requests, 3)
+}
+
+func TestWorker_DoesNotFollowRedirects(t *testing.T) {
+	ctx := context.Background()
+	orderStore := store.NewMemoryStore()
+	target := newEndpoint(t)
+	redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	t.Cleanup(redirect.Close)
+	newDelivery(t, orderStore, redirect.URL)
+
+	_, err := NewWorker(orderStore, testWorkerConfig()).DeliverBatch(ctx)
+	require.NoError(t, err)
+
+	assert.Empty(t, target.requests)
+	deliveries := listDeliveries(t, orderStore)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, entity.WebhookDeliveryPending, deliveries[0].Status)
+	assert.Equal(t, "endpoint responded with status 307", deliveries[0].LastError)
+}
+
+func TestWorker_RefusesEndpointsThatAreNotPublic(t *testing.T) {
+	testCases := []struct {
+		name      string
+		newServer func(http.Handler) *httptest.Server
+		lastError string
+	}{
+		{name: "https on localhost", newServer: httptest.NewTLSServer, lastError: "address is not public"},
+		{name: "http", newServer: httptest.NewServer, lastError: "endpoint url must use https"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			orderStore := store.NewMemoryStore()
+			var requests int
+			server := tc.newServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { requests++ }))
+			t.Cleanup(server.Close)
+			newDelivery(t, orderStore, server.URL)
+
+			_, err := NewWorker(orderStore, DefaultWorkerConfig()).DeliverBatch(ctx)
+			require.NoError(t, err)
+
+			assert.Zero(t, requests)
+			deliveries := listDeliveries(t, orderStore)
+			require.Len(t, deliveries, 1)
+			assert.Equal(t, entity.WebhookDeliveryPending, deliveries[0].Status)
+			assert.Contains(t, deliveries[0].LastError, tc.lastError)
+		})
+	}
+}
+
+func TestWorker_Backoff(t *testing.T) {
+	worker := NewWorker(nil, WorkerConfig{MinBackoff: time.Second, MaxBackoff: 10 * time.Second})
+
+	assert.Equal(t, time.Second, worker.backoff(0))
+	assert.Equal(t, 4*time.Second, worker.backoff(2))
+	assert.Equal(t, 10*time.Second, worker.backoff(4))
+	assert.Equal(t, 10*time.Second, worker.backoff(100))
+}
@@ -0,0 +1,94 @@
+package isolation
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/contracts/gen/go/order/v1/orderv1connect"
+	"github.com/demo/order/internal/auth/authtest"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuthSuite struct {
+	Suite
+}
+
+func TestAuthSuite(t *testing.T) {
+	suite.Run(t, new(AuthSuite))
+}
+
+// getOrder calls GetOrder with authorization as the Authorization header, if set.
+func (s *AuthSuite) getOrder(authorization string) error {
+	client := orderv1connect.NewOrderServiceClient(http.DefaultClient, s.baseURL)
+	req := connect.NewRequest(&orderv1.GetOrderRequest{Id: "does-not-exist"})
+	if authorization != "" {
+		req.Header().Set("Authorization", authorization)
+	}
+	_, err := client.GetOrder(context.Background(), req)
+	return err
+}
+
+func (s *AuthSuite) TestAuth_Rejected() {
+	s.WithAllure("Auth_Rejected", "Verify OrderService rejects calls without a valid bearer token")
+	s.requireAuth()
+
+	testCases := []struct {
+		name          string
+		authorization string
+	}{
+		{
+			name: "missing token",
+		},
+		{
+			name:          "wrong secret",
+			authorization: "Bearer " + authtest.NewHMACIssuer("not-the-secret-of-the-service-0123").Token(defaultSubject),
+		},
+		{
+			name: "expired token",
+			authorization: "Bearer " + s.issuer.Sign(jwt.MapClaims{
+				"sub": defaultSubject,
+				"exp": time.Now().Add(-time.Hour).Unix(),
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			err := s.getOrder(tc.authorization)
+			s.Require().Equal(connect.CodeUnauthenticated, connect.CodeOf(err))
+		})
+	}
+}
+
+func (s *AuthSuite) TestAuth_WebhookServiceRejected() {
+	s.WithAllure("Auth_WebhookServiceRejected", "Verify WebhookService rejects calls without a bearer token")
+	s.requireAuth()
+
+	client := orderv1connect.NewWebhookServiceClient(http.DefaultClient, s.baseURL)
+	_, err := client.ListWebhookSubscriptions(context.Background(), connect.NewRequest(&orderv1.ListWebhookSubscriptionsRequest{}))
+	s.Require().Equal(connect.CodeUnauthenticated, connect.CodeOf(err))
+}
+
+func (s *AuthSuite) TestAuth_ValidToken() {
+	s.WithAllure("Auth_ValidToken", "Verify OrderService accepts calls with a valid bearer token")
+
+	err := s.getOrder("Bearer " + s.issuer.Token(defaultSubject))
+	s.Require().Equal(connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func (s *AuthSuite) TestAuth_HealthIsPublic() {
+	s.WithAllure("Auth_HealthIsPublic", "Verify the health endpoints do not require a token")
+	s.requireAuth()
+
+	for _, path := range []string{"/livez", "/readyz"} {
+		resp, err := http.Get(s.baseURL + path)
+		s.Require().NoError(err)
+		resp.Body.Close()
+		s.Require().Equal(http.StatusOK, resp.StatusCode, path)
+	}
+}
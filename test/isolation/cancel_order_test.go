@@ -0,0 +1,214 @@
+package isolation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/validation"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type CancelOrderSuite struct {
+	Suite
+}
+
+func TestCancelOrderSuite(t *testing.T) {
+	suite.Run(t, new(CancelOrderSuite))
+}
+
+func (s *CancelOrderSuite) TestCancelOrder_Success() {
+	s.WithAllure("CancelOrder_Success", "Verify NEW order can be cancelled with a reason")
+
+	ctx := context.Background()
+	order := s.CreateOrder(ctx, s.GenerateUserID(), "Headphones", 79.99)
+
+	resp, err := s.orderClient.CancelOrder(ctx, connect.NewRequest(&orderv1.CancelOrderRequest{
+		Id:          order.Id,
+		Reason:      "CREATED_BY_MISTAKE",
+		Comment:     "Duplicate submit",
+		CancelledBy: "support-agent",
+	}))
+	s.Require().NoError(err)
+
+	cancelled := resp.Msg.Order
+	s.Require().Equal("CANCELLED", cancelled.Status)
+	s.Require().Equal("CREATED_BY_MISTAKE", cancelled.CancelReason)
+	s.Require().Equal("Duplicate submit", cancelled.CancelComment)
+	s.Require().Equal(s.cancelledBy("support-agent"), cancelled.CancelledBy)
+	s.Require().NotEmpty(cancelled.CancelledAt)
+
+	// Verify cancellation is persisted
+	getResp, err := s.orderClient.GetOrder(ctx, connect.NewRequest(&orderv1.GetOrderRequest{
+		Id: order.Id,
+	}))
+	s.Require().NoError(err)
+	s.Require().Equal("CANCELLED", getResp.Msg.Order.Status)
+	s.Require().Equal(cancelled.CancelledAt, getResp.Msg.Order.CancelledAt)
+}
+
+func (s *CancelOrderSuite) TestCancelOrder_Idempotent() {
+	s.WithAllure("CancelOrder_Idempotent", "Verify repeated cancellation keeps the original one")
+
+	ctx := context.Background()
+	order := s.CreateOrder(ctx, s.GenerateUserID(), "Charger", 15.00)
+
+	first, err := s.orderClient.CancelOrder(ctx, connect.NewRequest(&orderv1.CancelOrderRequest{
+		Id:          order.Id,
+		Reason:      "CUSTOMER_REQUEST",
+		CancelledBy: "support-agent-1",
+	}))
+	s.Require().NoError(err)
+
+	second, err := s.orderClient.CancelOrder(ctx, connect.NewRequest(&orderv1.CancelOrderRequest{
+		Id:          order.Id,
+		Reason:      "DUPLICATE",
+		CancelledBy: "support-agent-2",
+	}))
+	s.Require().NoError(err)
+
+	s.Require().Equal("CANCELLED", second.Msg.Order.Status)
+	s.Require().Equal("CUSTOMER_REQUEST", second.Msg.Order.CancelReason)
+	s.Require().Equal(s.cancelledBy("support-agent-1"), second.Msg.Order.CancelledBy)
+	s.Require().Equal(first.Msg.Order.CancelledAt, second.Msg.Order.CancelledAt)
+}
+
+func (s *CancelOrderSuite) TestCancelOrder_FinishedOrder() {
+	s.WithAllure("CancelOrder_FinishedOrder", "Verify FailedPrecondition when cancelling a FINISHED order")
+
+	ctx := context.Background()
+	order := s.CreateOrder(ctx, s.GenerateUserID(), "Monitor", 249.00)
+
+	for _, status := range []string{"IN_PROGRESS", "FINISHED"} {
+		_, err := s.orderClient.UpdateOrderStatus(ctx, connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+			Id:     order.Id,
+			Status: status,
+		}))
+		s.Require().NoError(err)
+	}
+
+	_, err := s.orderClient.CancelOrder(ctx, connect.NewRequest(&orderv1.CancelOrderRequest{
+		Id:          order.Id,
+		Reason:      "CUSTOMER_REQUEST",
+		CancelledBy: "support-agent",
+	}))
+
+	s.Require().Error(err)
+	var connectErr *connect.Error
+	s.Require().ErrorAs(err, &connectErr)
+	s.Require().Equal(connect.CodeFailedPrecondition, connectErr.Code())
+}
+
+func (s *CancelOrderSuite) TestCancelOrder_CancelledBy() {
+	s.WithAllure("CancelOrder_CancelledBy", "Verify cancelled_by is the caller with auth and required without it")
+
+	ctx := context.Background()
+	order := s.CreateOrder(ctx, s.GenerateUserID(), "Cable", 9.99)
+
+	resp, err := s.orderClient.CancelOrder(ctx, connect.NewRequest(&orderv1.CancelOrderRequest{
+		Id:     order.Id,
+		Reason: "CUSTOMER_REQUEST",
+	}))
+	if !s.authEnabled() {
+		s.Require().Equal(connect.CodeInvalidArgument, connect.CodeOf(err))
+		s.Require().Equal(map[string]string{"cancelled_by": "must be set"}, validation.FieldViolations(err))
+		return
+	}
+	s.Require().NoError(err)
+	s.Require().Equal(defaultSubject, resp.Msg.Order.CancelledBy)
+}
+
+func (s *CancelOrderSuite) TestCancelOrder_ValidationErrors() {
+	s.WithAllure("CancelOrder_ValidationErrors", "Verify validation errors for invalid input")
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name        string
+		id          string
+		reason      string
+		comment     string
+		cancelledBy string
+		wantErr     connect.Code
+		// wantViolations are the field violations of invalid arguments.
+		wantViolations map[string]string
+	}{
+		{
+			name:        "empty_id",
+			id:          "",
+			reason:      "CUSTOMER_REQUEST",
+			cancelledBy: "support-agent",
+			wantErr:     connect.CodeInvalidArgument,
+			wantViolations: map[string]string{
+				"id": "must be set",
+			},
+		},
+		{
+			name:        "unknown_reason",
+			id:          uuid.New().String(),
+			reason:      "BORED",
+			cancelledBy: "support-agent",
+			wantErr:     connect.CodeInvalidArgument,
+			wantViolations: map[string]string{
+				"reason": "must be one of CUSTOMER_REQUEST, CREATED_BY_MISTAKE, DUPLICATE, OUT_OF_STOCK, FRAUD_SUSPECTED, OTHER",
+			},
+		},
+		{
+			name:        "other_without_comment",
+			id:          uuid.New().String(),
+			reason:      "OTHER",
+			cancelledBy: "support-agent",
+			wantErr:     connect.CodeInvalidArgument,
+			wantViolations: map[string]string{
+				"comment": "must be set for reason OTHER",
+			},
+		},
+		{
+			name:        "every_violation_is_reported",
+			reason:      "OTHER",
+			comment:     strings.Repeat("a", 1001),
+			cancelledBy: "support-agent",
+			wantErr:     connect.CodeInvalidArgument,
+			wantViolations: map[string]string{
+				"id":      "must be set",
+				"comment": "must be at most 1000 characters long",
+			},
+		},
+		{
+			name:        "order_not_found",
+			id:          uuid.New().String(),
+			reason:      "CUSTOMER_REQUEST",
+			cancelledBy: "support-agent",
+			wantErr:     connect.CodeNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			_, err := s.orderClient.CancelOrder(ctx, connect.NewRequest(&orderv1.CancelOrderRequest{
+				Id:          tc.id,
+				Reason:      tc.reason,
+				Comment:     tc.comment,
+				CancelledBy: tc.cancelledBy,
+			}))
+
+			s.Require().Error(err, "Expected error for %s", tc.name)
+			var connectErr *connect.Error
+			s.Require().ErrorAs(err, &connectErr)
+			s.Require().Equal(tc.wantErr, connectErr.Code(), "Expected %v error code", tc.wantErr)
+			s.Require().Equal(tc.wantViolations, validation.FieldViolations(err))
+		})
+	}
+}
+
+// cancelledBy returns the cancelled_by recorded for a request with requested:
+// the authenticated caller if the service requires a token.
+func (s *CancelOrderSuite) cancelledBy(requested string) string {
+	if s.authEnabled() {
+		return defaultSubject
+	}
+	return requested
+}
@@ -6,6 +6,7 @@ import (
 
 	"connectrpc.com/connect"
 	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/validation"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 )
@@ -89,18 +90,26 @@ func (s *CheckOrderOwnerSuite) TestCheckOrderOwner_ValidationErrors() {
 		orderID string
 		userID  string
 		wantErr connect.Code
+		// wantViolations are the field violations of invalid arguments.
+		wantViolations map[string]string
 	}{
 		{
 			name:    "empty_order_id",
 			orderID: "",
 			userID:  s.GenerateUserID(),
 			wantErr: connect.CodeInvalidArgument,
+			wantViolations: map[string]string{
+				"order_id": "must be set",
+			},
 		},
 		{
 			name:    "empty_user_id",
 			orderID: uuid.New().String(),
 			userID:  "",
 			wantErr: connect.CodeInvalidArgument,
+			wantViolations: map[string]string{
+				"user_id": "must be set",
+			},
 		},
 	}
 
@@ -115,6 +124,7 @@ func (s *CheckOrderOwnerSuite) TestCheckOrderOwner_ValidationErrors() {
 			var connectErr *connect.Error
 			s.Require().ErrorAs(err, &connectErr)
 			s.Require().Equal(tc.wantErr, connectErr.Code())
+			s.Require().Equal(tc.wantViolations, validation.FieldViolations(err))
 		})
 	}
 }
@@ -6,6 +6,8 @@ import (
 
 	"connectrpc.com/connect"
 	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/validation"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -52,6 +54,8 @@ func (s *CreateOrderSuite) TestCreateOrder_ValidationErrors() {
 		item    string
 		amount  float64
 		wantErr connect.Code
+		// wantViolations are the field violations of invalid arguments.
+		wantViolations map[string]string
 	}{
 		{
 			name:    "empty_user_id",
@@ -59,6 +63,9 @@ func (s *CreateOrderSuite) TestCreateOrder_ValidationErrors() {
 			item:    "Test Item",
 			amount:  10.00,
 			wantErr: connect.CodeInvalidArgument,
+			wantViolations: map[string]string{
+				"user_id": "must be set",
+			},
 		},
 		{
 			name:    "empty_item",
@@ -66,6 +73,9 @@ func (s *CreateOrderSuite) TestCreateOrder_ValidationErrors() {
 			item:    "",
 			amount:  10.00,
 			wantErr: connect.CodeInvalidArgument,
+			wantViolations: map[string]string{
+				"item": "must be set when there are no items",
+			},
 		},
 		{
 			name:    "zero_amount",
@@ -73,6 +83,9 @@ func (s *CreateOrderSuite) TestCreateOrder_ValidationErrors() {
 			item:    "Test Item",
 			amount:  0,
 			wantErr: connect.CodeInvalidArgument,
+			wantViolations: map[string]string{
+				"amount": "must be positive",
+			},
 		},
 		{
 			name:    "negative_amount",
@@ -80,6 +93,26 @@ func (s *CreateOrderSuite) TestCreateOrder_ValidationErrors() {
 			item:    "Test Item",
 			amount:  -50.00,
 			wantErr: connect.CodeInvalidArgument,
+			wantViolations: map[string]string{
+				"amount": "must be positive",
+			},
+		},
+		{
+			name:    "every_violation_is_reported",
+			userID:  s.GenerateUserID(),
+			wantErr: connect.CodeInvalidArgument,
+			wantViolations: map[string]string{
+				"item":   "must be set when there are no items",
+				"amount": "must be positive",
+			},
+		},
+		{
+			// The handler does not run for requests that break the contract rules.
+			name:    "contract_violations_are_reported_first",
+			wantErr: connect.CodeInvalidArgument,
+			wantViolations: map[string]string{
+				"user_id": "must be set",
+			},
 		},
 	}
 
@@ -95,6 +128,92 @@ func (s *CreateOrderSuite) TestCreateOrder_ValidationErrors() {
 			var connectErr *connect.Error
 			s.Require().ErrorAs(err, &connectErr)
 			s.Require().Equal(tc.wantErr, connectErr.Code(), "Expected %v error code", tc.wantErr)
+			s.Require().Equal(tc.wantViolations, validation.FieldViolations(err))
 		})
 	}
 }
+
+func (s *CreateOrderSuite) TestCreateOrder_IdempotencyKey() {
+	s.WithAllure("CreateOrder_IdempotencyKey", "Verify retries with the same idempotency key return the original order")
+
+	ctx := context.Background()
+	userID := s.GenerateUserID()
+	key := uuid.New().String()
+
+	newRequest := func(item string) *connect.Request[orderv1.CreateOrderRequest] {
+		req := connect.NewRequest(&orderv1.CreateOrderRequest{
+			UserId: userID,
+			Item:   item,
+			Amount: 42.50,
+		})
+		req.Header().Set("Idempotency-Key", key)
+		return req
+	}
+
+	first, err := s.orderClient.CreateOrder(ctx, newRequest("Test Product"))
+	s.Require().NoError(err)
+	s.Require().Empty(first.Header().Get("Idempotent-Replayed"))
+
+	// Retry with the same key and payload
+	retry, err := s.orderClient.CreateOrder(ctx, newRequest("Test Product"))
+	s.Require().NoError(err)
+	s.Require().Equal("true", retry.Header().Get("Idempotent-Replayed"))
+	s.Require().Equal(first.Msg.Order.Id, retry.Msg.Order.Id, "Retry should return the original order")
+
+	// Same key with a different payload
+	_, err = s.orderClient.CreateOrder(ctx, newRequest("Other Product"))
+	s.Require().Error(err)
+	s.Require().Equal(connect.CodeAlreadyExists, connect.CodeOf(err))
+
+	// Only one order was created
+	listResp, err := s.orderClient.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{
+		UserId: userID,
+	}))
+	s.Require().NoError(err)
+	s.Require().Len(listResp.Msg.Orders, 1)
+}
+
+func (s *CreateOrderSuite) TestCreateOrder_Items() {
+	s.WithAllure("CreateOrder_Items", "Verify orders with several line items and server-side totals")
+
+	ctx := context.Background()
+	userID := s.GenerateUserID()
+
+	resp, err := s.orderClient.CreateOrder(ctx, connect.NewRequest(&orderv1.CreateOrderRequest{
+		UserId: userID,
+		Items: []*orderv1.OrderItem{
+			{Sku: "SKU-1", Name: "Widget", Quantity: 3, UnitPrice: 0.1},
+			{Sku: "SKU-2", Name: "Gadget", Quantity: 2, UnitPrice: 19.99},
+		},
+	}))
+	s.Require().NoError(err)
+	s.Require().Equal(40.28, resp.Msg.Order.Amount, "Total should be computed from the items")
+
+	getResp, err := s.orderClient.GetOrder(ctx, connect.NewRequest(&orderv1.GetOrderRequest{
+		Id: resp.Msg.Order.Id,
+	}))
+	s.Require().NoError(err)
+	items := getResp.Msg.Order.Items
+	s.Require().Len(items, 2)
+	s.Require().Equal("SKU-1", items[0].Sku)
+	s.Require().Equal(int64(3), items[0].Quantity)
+	s.Require().Equal(0.1, items[0].UnitPrice)
+	s.Require().Equal("SKU-2", items[1].Sku)
+
+	listResp, err := s.orderClient.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{
+		UserId: userID,
+	}))
+	s.Require().NoError(err)
+	s.Require().Len(listResp.Msg.Orders, 1)
+	s.Require().Len(listResp.Msg.Orders[0].Items, 2)
+
+	// Client total that does not match the items
+	_, err = s.orderClient.CreateOrder(ctx, connect.NewRequest(&orderv1.CreateOrderRequest{
+		UserId: userID,
+		Amount: 1,
+		Items:  []*orderv1.OrderItem{{Sku: "SKU-1", Quantity: 3, UnitPrice: 0.1}},
+	}))
+	s.Require().Equal(connect.CodeInvalidArgument, connect.CodeOf(err))
+	s.Require().Equal(map[string]string{"amount": "1.00 EUR does not match the items total 0.30 EUR"},
+		validation.FieldViolations(err))
+}
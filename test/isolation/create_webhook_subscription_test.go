@@ -0,0 +1,260 @@
+package isolation
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/stretchr/testify/suite"
+)
+
+type CreateWebhookSubscriptionSuite struct {
+	Suite
+}
+
+func TestCreateWebhookSubscriptionSuite(t *testing.T) {
+	suite.Run(t, new(CreateWebhookSubscriptionSuite))
+}
+
+// SetupTest skips the tests when the service runs with auth disabled, since
+// WebhookService is not served then.
+func (s *CreateWebhookSubscriptionSuite) SetupTest() {
+	s.requireAuth()
+}
+
+// webhookReceiver is a partner endpoint that records the webhooks it receives.
+// It listens on localhost, so the service under test must run on the same host.
+type webhookReceiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []receivedWebhook
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func newWebhookReceiver() *webhookReceiver {
+	r := &webhookReceiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.requests = append(r.requests, receivedWebhook{header: req.Header.Clone(), body: body})
+		r.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	return r
+}
+
+// forOrder returns the webhooks received about the order.
+func (r *webhookReceiver) forOrder(orderID string) []receivedWebhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var found []receivedWebhook
+	for _, request := range r.requests {
+		var envelope struct {
+			Data struct {
+				OrderID string `json:"order_id"`
+			} `json:"data"`
+		}
+		if json.Unmarshal(request.body, &envelope) == nil && envelope.Data.OrderID == orderID {
+			found = append(found, request)
+		}
+	}
+	return found
+}
+
+func (s *CreateWebhookSubscriptionSuite) TestCreateWebhookSubscription_DeliversSignedOrderEvents() {
+	s.WithAllure("CreateWebhookSubscription_DeliversSignedOrderEvents",
+		"Verify a subscribed endpoint receives order events signed with its secret")
+
+	ctx := context.Background()
+	receiver := newWebhookReceiver()
+	defer receiver.Close()
+
+	const secret = "isolation-secret-0123456789"
+	subscription := s.createSubscription(ctx, receiver.URL, secret, "ORDER_CREATED")
+	defer s.deleteSubscription(ctx, subscription.Id)
+
+	order := s.CreateOrder(ctx, s.GenerateUserID(), "Laptop", 1299.99)
+
+	var received []receivedWebhook
+	s.Require().Eventually(func() bool {
+		received = receiver.forOrder(order.Id)
+		return len(received) > 0
+	}, 10*time.Second, 100*time.Millisecond, "Webhook should be delivered")
+
+	webhook := received[0]
+	s.Require().Equal("ORDER_CREATED", webhook.header.Get("Webhook-Event"))
+	s.Require().NotEmpty(webhook.header.Get("Webhook-Id"))
+	s.requireValidSignature(secret, webhook)
+
+	var envelope struct {
+		EventID   string          `json:"event_id"`
+		EventType string          `json:"event_type"`
+		Data      json.RawMessage `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(webhook.body, &envelope))
+	s.Require().Equal(webhook.header.Get("Webhook-Event-Id"), envelope.EventID)
+	s.Require().Equal("ORDER_CREATED", envelope.EventType)
+	var payload map[string]any
+	s.Require().NoError(json.Unmarshal(envelope.Data, &payload))
+	s.Require().Equal("NEW", payload["status"])
+	s.Require().Equal("1299.99", payload["amount"])
+
+	s.Require().Eventually(func() bool {
+		resp, err := s.webhookClient.ListWebhookDeliveries(ctx, connect.NewRequest(&orderv1.ListWebhookDeliveriesRequest{
+			SubscriptionId: subscription.Id,
+			OrderId:        order.Id,
+		}))
+		s.Require().NoError(err)
+		return len(resp.Msg.Deliveries) == 1 && resp.Msg.Deliveries[0].Status == "DELIVERED"
+	}, 5*time.Second, 100*time.Millisecond, "Delivery should be recorded as delivered")
+}
+
+func (s *CreateWebhookSubscriptionSuite) TestCreateWebhookSubscription_FiltersEventTypes() {
+	s.WithAllure("CreateWebhookSubscription_FiltersEventTypes",
+		"Verify an endpoint only receives the event types it subscribed to")
+
+	ctx := context.Background()
+	receiver := newWebhookReceiver()
+	defer receiver.Close()
+
+	subscription := s.createSubscription(ctx, receiver.URL, "", "ORDER_CANCELLED")
+	defer s.deleteSubscription(ctx, subscription.Id)
+	s.Require().True(strings.HasPrefix(subscription.Secret, "whsec_"), "Secret should be generated")
+
+	order := s.CreateOrder(ctx, s.GenerateUserID(), "Laptop", 1299.99)
+	_, err := s.orderClient.CancelOrder(ctx, connect.NewRequest(&orderv1.CancelOrderRequest{
+		Id:          order.Id,
+		Reason:      "CUSTOMER_REQUEST",
+		CancelledBy: "isolation-test",
+	}))
+	s.Require().NoError(err)
+
+	var received []receivedWebhook
+	s.Require().Eventually(func() bool {
+		received = receiver.forOrder(order.Id)
+		return len(received) > 0
+	}, 10*time.Second, 100*time.Millisecond, "Cancellation webhook should be delivered")
+	s.requireValidSignature(subscription.Secret, received[0])
+
+	for _, webhook := range received {
+		s.Require().Equal("ORDER_CANCELLED", webhook.header.Get("Webhook-Event"))
+	}
+}
+
+func (s *CreateWebhookSubscriptionSuite) TestCreateWebhookSubscription_ListedWithoutSecret() {
+	s.WithAllure("CreateWebhookSubscription_ListedWithoutSecret",
+		"Verify a created subscription is listed and its secret is not exposed")
+
+	ctx := context.Background()
+	subscription := s.createSubscription(ctx, "https://partner.example.com/hooks", "", "ORDER_CREATED")
+	defer s.deleteSubscription(ctx, subscription.Id)
+
+	resp, err := s.webhookClient.ListWebhookSubscriptions(ctx, connect.NewRequest(&orderv1.ListWebhookSubscriptionsRequest{}))
+	s.Require().NoError(err)
+
+	var found *orderv1.WebhookSubscription
+	for _, listed := range resp.Msg.Subscriptions {
+		if listed.Id == subscription.Id {
+			found = listed
+		}
+	}
+	s.Require().NotNil(found, "Subscription should be listed")
+	s.Require().Equal("https://partner.example.com/hooks", found.Url)
+	s.Require().Equal([]string{"ORDER_CREATED"}, found.EventTypes)
+	s.Require().Empty(found.Secret)
+}
+
+func (s *CreateWebhookSubscriptionSuite) TestCreateWebhookSubscription_InvalidArgument() {
+	s.WithAllure("CreateWebhookSubscription_InvalidArgument", "Verify invalid subscriptions are rejected")
+
+	ctx := context.Background()
+	requests := map[string]*orderv1.CreateWebhookSubscriptionRequest{
+		"empty url":          {},
+		"relative url":       {Url: "/hooks"},
+		"unsupported scheme": {Url: "ftp://partner.example.com/hooks"},
+		"short secret":       {Url: "https://partner.example.com/hooks", Secret: "short"},
+		"unknown event type": {Url: "https://partner.example.com/hooks", EventTypes: []string{"ORDER_SHIPPED"}},
+	}
+
+	for name, req := range requests {
+		_, err := s.webhookClient.CreateWebhookSubscription(ctx, connect.NewRequest(req))
+		s.Require().Error(err, name)
+		s.Require().Equal(connect.CodeInvalidArgument, connect.CodeOf(err), name)
+	}
+}
+
+func (s *CreateWebhookSubscriptionSuite) TestCreateWebhookSubscription_RequiresAdmin() {
+	s.WithAllure("CreateWebhookSubscription_RequiresAdmin", "Verify WebhookService denies callers without the admin role")
+
+	ctx := context.Background()
+	for _, roles := range [][]string{nil, {"support"}} {
+		client := s.webhookClientAs(s.GenerateUserID(), roles...)
+
+		_, err := client.CreateWebhookSubscription(ctx, connect.NewRequest(&orderv1.CreateWebhookSubscriptionRequest{
+			Url: "https://partner.example.com/hooks",
+		}))
+		s.Require().Equal(connect.CodePermissionDenied, connect.CodeOf(err), "roles %v", roles)
+
+		_, err = client.ListWebhookSubscriptions(ctx, connect.NewRequest(&orderv1.ListWebhookSubscriptionsRequest{}))
+		s.Require().Equal(connect.CodePermissionDenied, connect.CodeOf(err), "roles %v", roles)
+	}
+}
+
+func (s *CreateWebhookSubscriptionSuite) createSubscription(
+	ctx context.Context,
+	url, secret string,
+	eventTypes ...string,
+) *orderv1.WebhookSubscription {
+	resp, err := s.webhookClient.CreateWebhookSubscription(ctx, connect.NewRequest(&orderv1.CreateWebhookSubscriptionRequest{
+		Url:        url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+	}))
+	s.Require().NoError(err, "Failed to create webhook subscription")
+	s.Require().NotNil(resp.Msg.Subscription)
+	return resp.Msg.Subscription
+}
+
+func (s *CreateWebhookSubscriptionSuite) deleteSubscription(ctx context.Context, id string) {
+	_, err := s.webhookClient.DeleteWebhookSubscription(ctx, connect.NewRequest(&orderv1.DeleteWebhookSubscriptionRequest{
+		Id: id,
+	}))
+	s.Require().NoError(err)
+}
+
+// requireValidSignature checks the Webhook-Signature header the way a partner would.
+func (s *CreateWebhookSubscriptionSuite) requireValidSignature(secret string, webhook receivedWebhook) {
+	var timestamp, signature string
+	for _, part := range strings.Split(webhook.header.Get("Webhook-Signature"), ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+	s.Require().NotEmpty(timestamp)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(webhook.body)
+	s.Require().Equal(hex.EncodeToString(mac.Sum(nil)), signature, "Signature should match the body")
+}
@@ -0,0 +1,121 @@
+package isolation
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/validation"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type DeleteOrderSuite struct {
+	Suite
+}
+
+func TestDeleteOrderSuite(t *testing.T) {
+	suite.Run(t, new(DeleteOrderSuite))
+}
+
+func (s *DeleteOrderSuite) TestDeleteOrder_Success() {
+	s.WithAllure("DeleteOrder_Success", "Verify a deleted order is hidden unless deleted orders are included")
+
+	ctx := context.Background()
+	userID := s.GenerateUserID()
+	order := s.CreateOrder(ctx, userID, "Notebook", 12.50)
+
+	resp, err := s.orderClient.DeleteOrder(ctx, connect.NewRequest(&orderv1.DeleteOrderRequest{Id: order.Id}))
+	s.Require().NoError(err)
+	s.Require().Equal(order.Id, resp.Msg.Order.Id)
+	s.Require().NotEmpty(resp.Msg.Order.DeletedAt)
+
+	_, err = s.orderClient.GetOrder(ctx, connect.NewRequest(&orderv1.GetOrderRequest{Id: order.Id}))
+	s.Require().Equal(connect.CodeNotFound, connect.CodeOf(err))
+	list, err := s.orderClient.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{UserId: userID}))
+	s.Require().NoError(err)
+	s.Require().Empty(list.Msg.Orders)
+
+	got, err := s.orderClient.GetOrder(ctx, connect.NewRequest(&orderv1.GetOrderRequest{
+		Id:             order.Id,
+		IncludeDeleted: true,
+	}))
+	s.Require().NoError(err)
+	s.Require().Equal(resp.Msg.Order.DeletedAt, got.Msg.Order.DeletedAt)
+	list, err = s.orderClient.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{
+		UserId:         userID,
+		IncludeDeleted: true,
+	}))
+	s.Require().NoError(err)
+	s.Require().Len(list.Msg.Orders, 1)
+	s.Require().Equal(order.Id, list.Msg.Orders[0].Id)
+}
+
+func (s *DeleteOrderSuite) TestDeleteOrder_DeletedOrderCannotChange() {
+	s.WithAllure("DeleteOrder_DeletedOrderCannotChange", "Verify a deleted order can neither be deleted again nor changed")
+
+	ctx := context.Background()
+	order := s.CreateOrder(ctx, s.GenerateUserID(), "Pen", 1.50)
+	_, err := s.orderClient.DeleteOrder(ctx, connect.NewRequest(&orderv1.DeleteOrderRequest{Id: order.Id}))
+	s.Require().NoError(err)
+
+	_, err = s.orderClient.DeleteOrder(ctx, connect.NewRequest(&orderv1.DeleteOrderRequest{Id: order.Id}))
+	s.Require().Equal(connect.CodeNotFound, connect.CodeOf(err))
+	_, err = s.orderClient.UpdateOrderStatus(ctx, connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+		Id:     order.Id,
+		Status: "IN_PROGRESS",
+	}))
+	s.Require().Equal(connect.CodeNotFound, connect.CodeOf(err))
+	_, err = s.orderClient.CancelOrder(ctx, connect.NewRequest(&orderv1.CancelOrderRequest{
+		Id:          order.Id,
+		Reason:      "DUPLICATE",
+		CancelledBy: "support-agent",
+	}))
+	s.Require().Equal(connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func (s *DeleteOrderSuite) TestDeleteOrder_NotFound() {
+	s.WithAllure("DeleteOrder_NotFound", "Verify deleting a non-existent order returns NotFound")
+
+	_, err := s.orderClient.DeleteOrder(context.Background(), connect.NewRequest(&orderv1.DeleteOrderRequest{
+		Id: uuid.New().String(),
+	}))
+	s.Require().Equal(connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func (s *DeleteOrderSuite) TestDeleteOrder_InvalidArgument() {
+	s.WithAllure("DeleteOrder_InvalidArgument", "Verify deleting without an ID returns InvalidArgument")
+
+	_, err := s.orderClient.DeleteOrder(context.Background(), connect.NewRequest(&orderv1.DeleteOrderRequest{}))
+	s.Require().Equal(connect.CodeInvalidArgument, connect.CodeOf(err))
+	s.Require().Equal(map[string]string{"id": "must be set"}, validation.FieldViolations(err))
+}
+
+func (s *DeleteOrderSuite) TestDeleteOrder_Ownership() {
+	s.WithAllure("DeleteOrder_Ownership", "Verify users delete their own orders but cannot see deleted ones")
+	s.requireAuth()
+
+	ctx := context.Background()
+	userID := s.GenerateUserID()
+	client := s.orderClientAs(userID)
+	own := s.CreateOrder(ctx, userID, "Own Item", 10.00)
+	other := s.CreateOrder(ctx, s.GenerateUserID(), "Other Item", 20.00)
+
+	_, err := client.DeleteOrder(ctx, connect.NewRequest(&orderv1.DeleteOrderRequest{Id: other.Id}))
+	s.Require().Equal(connect.CodeNotFound, connect.CodeOf(err))
+
+	_, err = client.DeleteOrder(ctx, connect.NewRequest(&orderv1.DeleteOrderRequest{Id: own.Id}))
+	s.Require().NoError(err)
+
+	// Only admins see deleted orders.
+	_, err = client.GetOrder(ctx, connect.NewRequest(&orderv1.GetOrderRequest{Id: own.Id, IncludeDeleted: true}))
+	s.Require().Equal(connect.CodePermissionDenied, connect.CodeOf(err))
+	_, err = client.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{IncludeDeleted: true}))
+	s.Require().Equal(connect.CodePermissionDenied, connect.CodeOf(err))
+	_, err = s.orderClientAs("support-agent", "support").GetOrder(ctx, connect.NewRequest(&orderv1.GetOrderRequest{
+		Id:             own.Id,
+		IncludeDeleted: true,
+	}))
+	s.Require().Equal(connect.CodePermissionDenied, connect.CodeOf(err))
+}
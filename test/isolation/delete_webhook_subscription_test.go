@@ -0,0 +1,68 @@
+package isolation
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type DeleteWebhookSubscriptionSuite struct {
+	Suite
+}
+
+func TestDeleteWebhookSubscriptionSuite(t *testing.T) {
+	suite.Run(t, new(DeleteWebhookSubscriptionSuite))
+}
+
+// SetupTest skips the tests when the service runs with auth disabled, since
+// WebhookService is not served then.
+func (s *DeleteWebhookSubscriptionSuite) SetupTest() {
+	s.requireAuth()
+}
+
+func (s *DeleteWebhookSubscriptionSuite) TestDeleteWebhookSubscription_RemovesSubscription() {
+	s.WithAllure("DeleteWebhookSubscription_RemovesSubscription", "Verify a deleted subscription is no longer listed")
+
+	ctx := context.Background()
+	created, err := s.webhookClient.CreateWebhookSubscription(ctx, connect.NewRequest(&orderv1.CreateWebhookSubscriptionRequest{
+		Url: "https://partner.example.com/hooks",
+	}))
+	s.Require().NoError(err)
+	id := created.Msg.Subscription.Id
+
+	_, err = s.webhookClient.DeleteWebhookSubscription(ctx, connect.NewRequest(&orderv1.DeleteWebhookSubscriptionRequest{
+		Id: id,
+	}))
+	s.Require().NoError(err)
+
+	resp, err := s.webhookClient.ListWebhookSubscriptions(ctx, connect.NewRequest(&orderv1.ListWebhookSubscriptionsRequest{}))
+	s.Require().NoError(err)
+	for _, subscription := range resp.Msg.Subscriptions {
+		s.Require().NotEqual(id, subscription.Id, "Deleted subscription should not be listed")
+	}
+
+	_, err = s.webhookClient.DeleteWebhookSubscription(ctx, connect.NewRequest(&orderv1.DeleteWebhookSubscriptionRequest{
+		Id: id,
+	}))
+	s.Require().Equal(connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func (s *DeleteWebhookSubscriptionSuite) TestDeleteWebhookSubscription_NotFound() {
+	s.WithAllure("DeleteWebhookSubscription_NotFound", "Verify NotFound error for non-existent subscription")
+
+	_, err := s.webhookClient.DeleteWebhookSubscription(context.Background(), connect.NewRequest(&orderv1.DeleteWebhookSubscriptionRequest{
+		Id: uuid.New().String(),
+	}))
+	s.Require().Equal(connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func (s *DeleteWebhookSubscriptionSuite) TestDeleteWebhookSubscription_EmptyID() {
+	s.WithAllure("DeleteWebhookSubscription_EmptyID", "Verify InvalidArgument error for empty subscription ID")
+
+	_, err := s.webhookClient.DeleteWebhookSubscription(context.Background(), connect.NewRequest(&orderv1.DeleteWebhookSubscriptionRequest{}))
+	s.Require().Equal(connect.CodeInvalidArgument, connect.CodeOf(err))
+}
@@ -6,6 +6,7 @@ import (
 
 	"connectrpc.com/connect"
 	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/validation"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 )
@@ -74,4 +75,5 @@ func (s *GetOrderSuite) TestGetOrder_EmptyID() {
 	var connectErr *connect.Error
 	s.Require().ErrorAs(err, &connectErr)
 	s.Require().Equal(connect.CodeInvalidArgument, connectErr.Code())
+	s.Require().Equal(map[string]string{"id": "must be set"}, validation.FieldViolations(err))
 }
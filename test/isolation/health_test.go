@@ -0,0 +1,73 @@
+package isolation
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthSuite struct {
+	Suite
+}
+
+func TestHealthSuite(t *testing.T) {
+	suite.Run(t, new(HealthSuite))
+}
+
+// do sends a request to the service and returns the status code and body.
+func (s *HealthSuite) do(method, path, contentType, body string) (int, string) {
+	req, err := http.NewRequest(method, s.baseURL+path, strings.NewReader(body))
+	s.Require().NoError(err)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	return resp.StatusCode, string(respBody)
+}
+
+func (s *HealthSuite) TestLivez() {
+	s.WithAllure("Health_Livez", "Verify the liveness probe succeeds")
+
+	code, body := s.do(http.MethodGet, "/livez", "", "")
+
+	s.Require().Equal(http.StatusOK, code)
+	s.Require().Equal("ok\n", body)
+}
+
+func (s *HealthSuite) TestReadyz() {
+	s.WithAllure("Health_Readyz", "Verify the readiness probe succeeds and lists the checks")
+
+	code, body := s.do(http.MethodGet, "/readyz", "", "")
+
+	s.Require().Equal(http.StatusOK, code, body)
+	s.Require().Contains(body, "[+] store: ok")
+	s.Require().Contains(body, "[+] workers: ok")
+	s.Require().True(strings.HasSuffix(body, "ready\n"))
+}
+
+func (s *HealthSuite) TestGRPCHealthCheck() {
+	s.WithAllure("Health_GRPCHealthCheck", "Verify grpc.health.v1 reports the process and OrderService as serving")
+
+	for _, request := range []string{`{}`, `{"service": "order.v1.OrderService"}`} {
+		code, body := s.do(http.MethodPost, "/grpc.health.v1.Health/Check", "application/json", request)
+
+		s.Require().Equal(http.StatusOK, code, body)
+		s.Require().JSONEq(`{"status": "SERVING_STATUS_SERVING"}`, body)
+	}
+}
+
+func (s *HealthSuite) TestGRPCHealthCheck_UnknownService() {
+	s.WithAllure("Health_GRPCHealthCheck_UnknownService", "Verify grpc.health.v1 rejects unknown services")
+
+	code, body := s.do(http.MethodPost, "/grpc.health.v1.Health/Check", "application/json", `{"service": "order.v1.UnknownService"}`)
+
+	s.Require().Equal(http.StatusNotFound, code)
+	s.Require().Contains(body, `"code":"not_found"`)
+}
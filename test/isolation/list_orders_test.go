@@ -6,6 +6,7 @@ import (
 
 	"connectrpc.com/connect"
 	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/validation"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -28,8 +29,10 @@ func (s *ListOrdersSuite) TestListOrders_MultipleOrders() {
 	order2 := s.CreateOrder(ctx, userID, "Product B", 20.00)
 	order3 := s.CreateOrder(ctx, userID, "Product C", 30.00)
 
-	// List all orders
-	resp, err := s.orderClient.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{}))
+	// List orders of the user
+	resp, err := s.orderClient.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{
+		UserId: userID,
+	}))
 
 	s.Require().NoError(err)
 	s.Require().NotNil(resp.Msg.Orders)
@@ -56,7 +59,9 @@ func (s *ListOrdersSuite) TestListOrders_ContainsCreatedOrder() {
 	createdOrder := s.CreateOrder(ctx, userID, uniqueItem, 99.99)
 
 	// List orders
-	resp, err := s.orderClient.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{}))
+	resp, err := s.orderClient.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{
+		UserId: userID,
+	}))
 
 	s.Require().NoError(err)
 
@@ -74,3 +79,140 @@ func (s *ListOrdersSuite) TestListOrders_ContainsCreatedOrder() {
 	s.Require().Equal(uniqueItem, foundOrder.Item)
 	s.Require().Equal(99.99, foundOrder.Amount)
 }
+
+func (s *ListOrdersSuite) TestListOrders_Pagination() {
+	s.WithAllure("ListOrders_Pagination", "Verify orders are paged newest first without gaps or duplicates")
+
+	ctx := context.Background()
+	userID := s.GenerateUserID()
+
+	// Create five orders, oldest first
+	created := make([]*orderv1.Order, 5)
+	for i := range created {
+		created[i] = s.CreateOrder(ctx, userID, "Paged Product", float64(i+1))
+	}
+
+	// Walk through all pages of size 2
+	var (
+		listed    []*orderv1.Order
+		pageToken string
+		pages     int
+	)
+	for {
+		resp, err := s.orderClient.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{
+			UserId:    userID,
+			PageSize:  2,
+			PageToken: pageToken,
+		}))
+		s.Require().NoError(err)
+		s.Require().LessOrEqual(len(resp.Msg.Orders), 2)
+
+		listed = append(listed, resp.Msg.Orders...)
+		pages++
+		pageToken = resp.Msg.NextPageToken
+		if pageToken == "" {
+			break
+		}
+	}
+
+	s.Require().Equal(3, pages)
+	s.Require().Len(listed, len(created))
+	for i, o := range listed {
+		s.Require().Equal(created[len(created)-1-i].Id, o.Id, "Orders should be listed newest first")
+	}
+}
+
+func (s *ListOrdersSuite) TestListOrders_Filters() {
+	s.WithAllure("ListOrders_Filters", "Verify status and amount range filters")
+
+	ctx := context.Background()
+	userID := s.GenerateUserID()
+
+	cheap := s.CreateOrder(ctx, userID, "Cheap Product", 5.00)
+	expensive := s.CreateOrder(ctx, userID, "Expensive Product", 500.00)
+	_, err := s.orderClient.UpdateOrderStatus(ctx, connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+		Id:     expensive.Id,
+		Status: "IN_PROGRESS",
+	}))
+	s.Require().NoError(err)
+
+	// Filter by status
+	resp, err := s.orderClient.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{
+		UserId: userID,
+		Status: "NEW",
+	}))
+	s.Require().NoError(err)
+	s.Require().Len(resp.Msg.Orders, 1)
+	s.Require().Equal(cheap.Id, resp.Msg.Orders[0].Id)
+
+	// Filter by amount range
+	minAmount := 100.0
+	resp, err = s.orderClient.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{
+		UserId:    userID,
+		Currency:  "EUR",
+		MinAmount: &minAmount,
+	}))
+	s.Require().NoError(err)
+	s.Require().Len(resp.Msg.Orders, 1)
+	s.Require().Equal(expensive.Id, resp.Msg.Orders[0].Id)
+
+	// Amount range without currency
+	_, err = s.orderClient.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{
+		UserId:    userID,
+		MinAmount: &minAmount,
+	}))
+	s.Require().Equal(connect.CodeInvalidArgument, connect.CodeOf(err))
+	s.Require().Contains(validation.FieldViolations(err), "currency")
+}
+
+func (s *ListOrdersSuite) TestListOrders_CurrencyFilter() {
+	s.WithAllure("ListOrders_CurrencyFilter", "Verify orders in different currencies and the currency filter")
+
+	ctx := context.Background()
+	userID := s.GenerateUserID()
+
+	s.CreateOrder(ctx, userID, "Euro Product", 15.50)
+	yenResp, err := s.orderClient.CreateOrder(ctx, connect.NewRequest(&orderv1.CreateOrderRequest{
+		UserId:   userID,
+		Item:     "Yen Product",
+		Amount:   1500,
+		Currency: "JPY",
+	}))
+	s.Require().NoError(err)
+	s.Require().Equal("JPY", yenResp.Msg.Order.Currency)
+	s.Require().Equal(int64(1500), yenResp.Msg.Order.AmountMinorUnits)
+
+	resp, err := s.orderClient.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{
+		UserId:   userID,
+		Currency: "JPY",
+	}))
+	s.Require().NoError(err)
+	s.Require().Len(resp.Msg.Orders, 1)
+	s.Require().Equal(yenResp.Msg.Order.Id, resp.Msg.Orders[0].Id)
+
+	// Yen have no minor unit
+	_, err = s.orderClient.CreateOrder(ctx, connect.NewRequest(&orderv1.CreateOrderRequest{
+		UserId:   userID,
+		Item:     "Yen Product",
+		Amount:   10.5,
+		Currency: "JPY",
+	}))
+	s.Require().Equal(connect.CodeInvalidArgument, connect.CodeOf(err))
+	s.Require().Contains(validation.FieldViolations(err), "amount")
+}
+
+func (s *ListOrdersSuite) TestListOrders_InvalidPageToken() {
+	s.WithAllure("ListOrders_InvalidPageToken", "Verify InvalidArgument error for a malformed page token")
+
+	ctx := context.Background()
+
+	_, err := s.orderClient.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{
+		PageToken: "not-a-token",
+	}))
+
+	s.Require().Error(err)
+	var connectErr *connect.Error
+	s.Require().ErrorAs(err, &connectErr)
+	s.Require().Equal(connect.CodeInvalidArgument, connectErr.Code())
+	s.Require().Equal(map[string]string{"page_token": "invalid page token"}, validation.FieldViolations(err))
+}
@@ -0,0 +1,53 @@
+package isolation
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type ListWebhookDeliveriesSuite struct {
+	Suite
+}
+
+func TestListWebhookDeliveriesSuite(t *testing.T) {
+	suite.Run(t, new(ListWebhookDeliveriesSuite))
+}
+
+// SetupTest skips the tests when the service runs with auth disabled, since
+// WebhookService is not served then.
+func (s *ListWebhookDeliveriesSuite) SetupTest() {
+	s.requireAuth()
+}
+
+func (s *ListWebhookDeliveriesSuite) TestListWebhookDeliveries_UnknownSubscription() {
+	s.WithAllure("ListWebhookDeliveries_UnknownSubscription", "Verify an unknown subscription has no deliveries")
+
+	resp, err := s.webhookClient.ListWebhookDeliveries(context.Background(), connect.NewRequest(&orderv1.ListWebhookDeliveriesRequest{
+		SubscriptionId: uuid.New().String(),
+	}))
+	s.Require().NoError(err)
+	s.Require().Empty(resp.Msg.Deliveries)
+	s.Require().Empty(resp.Msg.NextPageToken)
+}
+
+func (s *ListWebhookDeliveriesSuite) TestListWebhookDeliveries_InvalidArgument() {
+	s.WithAllure("ListWebhookDeliveries_InvalidArgument", "Verify invalid filters are rejected")
+
+	ctx := context.Background()
+	requests := map[string]*orderv1.ListWebhookDeliveriesRequest{
+		"negative page size": {PageSize: -1},
+		"unknown status":     {Status: "FAILED"},
+		"invalid page token": {PageToken: "not-a-token"},
+	}
+
+	for name, req := range requests {
+		_, err := s.webhookClient.ListWebhookDeliveries(ctx, connect.NewRequest(req))
+		s.Require().Error(err, name)
+		s.Require().Equal(connect.CodeInvalidArgument, connect.CodeOf(err), name)
+	}
+}
@@ -0,0 +1,42 @@
+package isolation
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MetricsSuite struct {
+	Suite
+}
+
+func TestMetricsSuite(t *testing.T) {
+	suite.Run(t, new(MetricsSuite))
+}
+
+func (s *MetricsSuite) scrape() string {
+	resp, err := http.Get(s.baseURL + "/metrics")
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	return string(body)
+}
+
+func (s *MetricsSuite) TestMetrics_RecordsRPCsAndOrders() {
+	s.WithAllure("Metrics_RecordsRPCsAndOrders", "Verify /metrics exports RPC, store and order metrics in the Prometheus text format")
+
+	s.CreateOrder(context.Background(), s.GenerateUserID(), "Laptop", 1299.99)
+
+	metrics := s.scrape()
+	s.Require().Contains(metrics, `order_rpc_requests_total{code="ok",procedure="/order.v1.OrderService/CreateOrder"}`)
+	s.Require().Contains(metrics, `order_rpc_duration_seconds_bucket{procedure="/order.v1.OrderService/CreateOrder"`)
+	s.Require().Contains(metrics, `order_store_query_duration_seconds_count{method="Create"}`)
+	s.Require().Contains(metrics, `order_orders_total{status="NEW"}`)
+	s.Require().Contains(metrics, `order_orders_created_amount_total{currency="EUR"}`)
+	s.Require().Contains(metrics, "go_goroutines")
+}
@@ -0,0 +1,115 @@
+package isolation
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/stretchr/testify/suite"
+)
+
+type OwnershipSuite struct {
+	Suite
+}
+
+func TestOwnershipSuite(t *testing.T) {
+	suite.Run(t, new(OwnershipSuite))
+}
+
+func (s *OwnershipSuite) TestOwnership_OwnOrders() {
+	s.WithAllure("Ownership_OwnOrders", "Verify users see their own orders and only those")
+	s.requireAuth()
+
+	ctx := context.Background()
+	userID := s.GenerateUserID()
+	client := s.orderClientAs(userID)
+
+	created, err := client.CreateOrder(ctx, connect.NewRequest(&orderv1.CreateOrderRequest{
+		UserId: userID,
+		Item:   "Own Item",
+		Amount: 10.00,
+	}))
+	s.Require().NoError(err)
+	s.CreateOrder(ctx, s.GenerateUserID(), "Other Item", 20.00)
+
+	got, err := client.GetOrder(ctx, connect.NewRequest(&orderv1.GetOrderRequest{Id: created.Msg.Order.Id}))
+	s.Require().NoError(err)
+	s.Require().Equal(userID, got.Msg.Order.UserId)
+
+	// Without a user filter, only the orders of the caller are listed.
+	list, err := client.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{}))
+	s.Require().NoError(err)
+	s.Require().Len(list.Msg.Orders, 1)
+	s.Require().Equal(created.Msg.Order.Id, list.Msg.Orders[0].Id)
+}
+
+func (s *OwnershipSuite) TestOwnership_OtherUsersOrders() {
+	s.WithAllure("Ownership_OtherUsersOrders", "Verify users cannot access the orders of other users")
+	s.requireAuth()
+
+	ctx := context.Background()
+	ownerID := s.GenerateUserID()
+	order := s.CreateOrder(ctx, ownerID, "Test Item", 50.00)
+	client := s.orderClientAs(s.GenerateUserID())
+
+	// The order looks like it does not exist, so that its existence is not leaked.
+	_, err := client.GetOrder(ctx, connect.NewRequest(&orderv1.GetOrderRequest{Id: order.Id}))
+	s.Require().Equal(connect.CodeNotFound, connect.CodeOf(err))
+
+	_, err = client.CancelOrder(ctx, connect.NewRequest(&orderv1.CancelOrderRequest{
+		Id:          order.Id,
+		Reason:      "CREATED_BY_MISTAKE",
+		CancelledBy: "someone-else",
+	}))
+	s.Require().Equal(connect.CodeNotFound, connect.CodeOf(err))
+
+	_, err = client.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{UserId: ownerID}))
+	s.Require().Equal(connect.CodePermissionDenied, connect.CodeOf(err))
+
+	// The order is left untouched.
+	got, err := s.orderClient.GetOrder(ctx, connect.NewRequest(&orderv1.GetOrderRequest{Id: order.Id}))
+	s.Require().NoError(err)
+	s.Require().Equal("NEW", got.Msg.Order.Status)
+}
+
+func (s *OwnershipSuite) TestOwnership_Support() {
+	s.WithAllure("Ownership_Support", "Verify support sees the orders of every user")
+	s.requireAuth()
+
+	ctx := context.Background()
+	ownerID := s.GenerateUserID()
+	order := s.CreateOrder(ctx, ownerID, "Test Item", 50.00)
+	client := s.orderClientAs("support-agent", "support")
+
+	got, err := client.GetOrder(ctx, connect.NewRequest(&orderv1.GetOrderRequest{Id: order.Id}))
+	s.Require().NoError(err)
+	s.Require().Equal(ownerID, got.Msg.Order.UserId)
+
+	list, err := client.ListOrders(ctx, connect.NewRequest(&orderv1.ListOrdersRequest{UserId: ownerID}))
+	s.Require().NoError(err)
+	s.Require().Len(list.Msg.Orders, 1)
+}
+
+func (s *OwnershipSuite) TestOwnership_StatusChangedBySupportOnly() {
+	s.WithAllure("Ownership_StatusChangedBySupportOnly", "Verify users cannot change the status of their own orders, support can")
+	s.requireAuth()
+
+	ctx := context.Background()
+	userID := s.GenerateUserID()
+	order := s.CreateOrder(ctx, userID, "Test Item", 50.00)
+
+	_, err := s.orderClientAs(userID).UpdateOrderStatus(ctx, connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+		Id:     order.Id,
+		Status: "IN_PROGRESS",
+	}))
+	s.Require().Equal(connect.CodePermissionDenied, connect.CodeOf(err))
+
+	updated, err := s.orderClientAs("support-agent", "support").UpdateOrderStatus(ctx,
+		connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+			Id:     order.Id,
+			Status: "IN_PROGRESS",
+		}))
+	s.Require().NoError(err)
+	s.Require().Equal("IN_PROGRESS", updated.Msg.Order.Status)
+}
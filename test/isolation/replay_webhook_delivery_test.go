@@ -0,0 +1,45 @@
+package isolation
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReplayWebhookDeliverySuite struct {
+	Suite
+}
+
+func TestReplayWebhookDeliverySuite(t *testing.T) {
+	suite.Run(t, new(ReplayWebhookDeliverySuite))
+}
+
+// SetupTest skips the tests when the service runs with auth disabled, since
+// WebhookService is not served then.
+func (s *ReplayWebhookDeliverySuite) SetupTest() {
+	s.requireAuth()
+}
+
+func (s *ReplayWebhookDeliverySuite) TestReplayWebhookDelivery_NotFound() {
+	s.WithAllure("ReplayWebhookDelivery_NotFound", "Verify NotFound error for non-existent delivery")
+
+	_, err := s.webhookClient.ReplayWebhookDelivery(context.Background(), connect.NewRequest(&orderv1.ReplayWebhookDeliveryRequest{
+		Id: "9223372036854775807",
+	}))
+	s.Require().Equal(connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func (s *ReplayWebhookDeliverySuite) TestReplayWebhookDelivery_InvalidID() {
+	s.WithAllure("ReplayWebhookDelivery_InvalidID", "Verify InvalidArgument error for malformed delivery ID")
+
+	ctx := context.Background()
+	for _, id := range []string{"", "abc", "-1"} {
+		_, err := s.webhookClient.ReplayWebhookDelivery(ctx, connect.NewRequest(&orderv1.ReplayWebhookDeliveryRequest{
+			Id: id,
+		}))
+		s.Require().Equal(connect.CodeInvalidArgument, connect.CodeOf(err), "id %q", id)
+	}
+}
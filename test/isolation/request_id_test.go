@@ -0,0 +1,40 @@
+package isolation
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/stretchr/testify/suite"
+)
+
+type RequestIDSuite struct {
+	Suite
+}
+
+func TestRequestIDSuite(t *testing.T) {
+	suite.Run(t, new(RequestIDSuite))
+}
+
+func (s *RequestIDSuite) TestRequestID_Generated() {
+	s.WithAllure("RequestID_Generated", "Verify a request ID is generated and returned when the caller sends none")
+
+	order := s.CreateOrder(context.Background(), s.GenerateUserID(), "Laptop", 1299.99)
+	resp, err := s.orderClient.GetOrder(context.Background(), connect.NewRequest(&orderv1.GetOrderRequest{Id: order.Id}))
+	s.Require().NoError(err)
+	s.Require().NotEmpty(resp.Header().Get("X-Request-Id"))
+}
+
+func (s *RequestIDSuite) TestRequestID_FromCaller() {
+	s.WithAllure("RequestID_FromCaller", "Verify the request ID sent by the caller is returned, also with errors")
+
+	req := connect.NewRequest(&orderv1.GetOrderRequest{Id: "does-not-exist"})
+	req.Header().Set("X-Request-Id", "isolation-request-1")
+	_, err := s.orderClient.GetOrder(context.Background(), req)
+
+	var connectErr *connect.Error
+	s.Require().ErrorAs(err, &connectErr)
+	s.Require().Equal(connect.CodeNotFound, connectErr.Code())
+	s.Require().Equal("isolation-request-1", connectErr.Meta().Get("X-Request-Id"))
+}
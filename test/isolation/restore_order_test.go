@@ -0,0 +1,79 @@
+package isolation
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/contracts/gen/go/order/v1/orderv1connect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type RestoreOrderSuite struct {
+	Suite
+}
+
+func TestRestoreOrderSuite(t *testing.T) {
+	suite.Run(t, new(RestoreOrderSuite))
+}
+
+func (s *RestoreOrderSuite) TestRestoreOrder_Success() {
+	s.WithAllure("RestoreOrder_Success", "Verify a restored order is visible and can change again")
+
+	ctx := context.Background()
+	order := s.CreateOrder(ctx, s.GenerateUserID(), "Lamp", 30.00)
+	_, err := s.orderClient.DeleteOrder(ctx, connect.NewRequest(&orderv1.DeleteOrderRequest{Id: order.Id}))
+	s.Require().NoError(err)
+
+	resp, err := s.orderClient.RestoreOrder(ctx, connect.NewRequest(&orderv1.RestoreOrderRequest{Id: order.Id}))
+	s.Require().NoError(err)
+	s.Require().Empty(resp.Msg.Order.DeletedAt)
+
+	got, err := s.orderClient.GetOrder(ctx, connect.NewRequest(&orderv1.GetOrderRequest{Id: order.Id}))
+	s.Require().NoError(err)
+	s.Require().Empty(got.Msg.Order.DeletedAt)
+	_, err = s.orderClient.UpdateOrderStatus(ctx, connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+		Id:     order.Id,
+		Status: "IN_PROGRESS",
+	}))
+	s.Require().NoError(err)
+}
+
+func (s *RestoreOrderSuite) TestRestoreOrder_NotDeleted() {
+	s.WithAllure("RestoreOrder_NotDeleted", "Verify restoring an order that is not deleted returns FailedPrecondition")
+
+	order := s.CreateOrder(context.Background(), s.GenerateUserID(), "Lamp", 30.00)
+
+	_, err := s.orderClient.RestoreOrder(context.Background(), connect.NewRequest(&orderv1.RestoreOrderRequest{Id: order.Id}))
+	s.Require().Equal(connect.CodeFailedPrecondition, connect.CodeOf(err))
+}
+
+func (s *RestoreOrderSuite) TestRestoreOrder_NotFound() {
+	s.WithAllure("RestoreOrder_NotFound", "Verify restoring a non-existent order returns NotFound")
+
+	_, err := s.orderClient.RestoreOrder(context.Background(), connect.NewRequest(&orderv1.RestoreOrderRequest{
+		Id: uuid.New().String(),
+	}))
+	s.Require().Equal(connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func (s *RestoreOrderSuite) TestRestoreOrder_AdminOnly() {
+	s.WithAllure("RestoreOrder_AdminOnly", "Verify only admins restore deleted orders")
+	s.requireAuth()
+
+	ctx := context.Background()
+	userID := s.GenerateUserID()
+	order := s.CreateOrder(ctx, userID, "Lamp", 30.00)
+	_, err := s.orderClient.DeleteOrder(ctx, connect.NewRequest(&orderv1.DeleteOrderRequest{Id: order.Id}))
+	s.Require().NoError(err)
+
+	for _, client := range []orderv1connect.OrderServiceClient{
+		s.orderClientAs(userID),
+		s.orderClientAs("support-agent", "support"),
+	} {
+		_, err = client.RestoreOrder(ctx, connect.NewRequest(&orderv1.RestoreOrderRequest{Id: order.Id}))
+		s.Require().Equal(connect.CodePermissionDenied, connect.CodeOf(err))
+	}
+}
@@ -9,12 +9,19 @@ import (
 	"connectrpc.com/connect"
 	orderv1 "github.com/demo/contracts/gen/go/order/v1"
 	"github.com/demo/contracts/gen/go/order/v1/orderv1connect"
+	"github.com/demo/order/internal/auth/authtest"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 )
 
 const (
 	defaultBaseURL = "http://localhost:8081"
+	// defaultHMACSecret signs the tokens of the tests unless ORDER_AUTH_HMAC_SECRET
+	// is set. It must match auth.hmac_secret of the service when auth is enabled.
+	defaultHMACSecret = "isolation-tests-secret-0123456789"
+	// defaultSubject is the user of the tokens sent by the clients. It has the
+	// admin role, so that the tests can manage the orders of any user.
+	defaultSubject = "isolation-tests"
 )
 
 // Suite is the base test suite for isolation tests.
@@ -24,9 +31,13 @@ type Suite struct {
 
 	// Connect RPC client for Order Service
 	orderClient orderv1connect.OrderServiceClient
+	// Connect RPC client for the admin Webhook Service
+	webhookClient orderv1connect.WebhookServiceClient
 
 	// Base URL for the service
 	baseURL string
+	// issuer mints the bearer tokens sent by the clients
+	issuer *authtest.Issuer
 }
 
 // SetupSuite initializes the test suite with RPC client.
@@ -36,13 +47,69 @@ func (s *Suite) SetupSuite() {
 		s.baseURL = defaultBaseURL
 	}
 
-	// Create HTTP client for Connect RPC
+	secret := os.Getenv("ORDER_AUTH_HMAC_SECRET")
+	if secret == "" {
+		secret = defaultHMACSecret
+	}
+	s.issuer = authtest.NewHMACIssuer(secret)
+
+	// Initialize the clients, authenticated with an admin token of defaultSubject
+	s.orderClient = s.orderClientAs(defaultSubject, "admin")
+	s.webhookClient = s.webhookClientAs(defaultSubject, "admin")
+}
+
+// orderClientAs returns an Order Service client authenticated as subject with roles.
+func (s *Suite) orderClientAs(subject string, roles ...string) orderv1connect.OrderServiceClient {
 	httpClient := &http.Client{
 		Timeout: 10 * time.Second,
 	}
+	return orderv1connect.NewOrderServiceClient(httpClient, s.baseURL,
+		connect.WithInterceptors(bearerToken(s.issuer.Token(subject, roles...))))
+}
+
+// webhookClientAs returns a Webhook Service client authenticated as subject with roles.
+func (s *Suite) webhookClientAs(subject string, roles ...string) orderv1connect.WebhookServiceClient {
+	httpClient := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+	return orderv1connect.NewWebhookServiceClient(httpClient, s.baseURL,
+		connect.WithInterceptors(bearerToken(s.issuer.Token(subject, roles...))))
+}
+
+// authEnabled reports whether the service requires a token.
+func (s *Suite) authEnabled() bool {
+	client := orderv1connect.NewOrderServiceClient(http.DefaultClient, s.baseURL)
+	_, err := client.GetOrder(context.Background(), connect.NewRequest(&orderv1.GetOrderRequest{Id: "does-not-exist"}))
+	return connect.CodeOf(err) == connect.CodeUnauthenticated
+}
+
+// requireAuth skips the test when the service runs with auth disabled.
+func (s *Suite) requireAuth() {
+	if !s.authEnabled() {
+		s.T().Skip("Authentication is disabled in the service")
+	}
+}
+
+// bearerToken sends a token in the Authorization header of every call.
+type bearerToken string
+
+func (t bearerToken) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		req.Header().Set("Authorization", "Bearer "+string(t))
+		return next(ctx, req)
+	}
+}
+
+func (t bearerToken) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		conn := next(ctx, spec)
+		conn.RequestHeader().Set("Authorization", "Bearer "+string(t))
+		return conn
+	}
+}
 
-	// Initialize Order Service client
-	s.orderClient = orderv1connect.NewOrderServiceClient(httpClient, s.baseURL)
+func (t bearerToken) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
 }
 
 // WithAllure logs test metadata for Allure reporting.
@@ -0,0 +1,132 @@
+package isolation
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/validation"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type UpdateOrderStatusSuite struct {
+	Suite
+}
+
+func TestUpdateOrderStatusSuite(t *testing.T) {
+	suite.Run(t, new(UpdateOrderStatusSuite))
+}
+
+func (s *UpdateOrderStatusSuite) TestUpdateOrderStatus_FullLifecycle() {
+	s.WithAllure("UpdateOrderStatus_FullLifecycle", "Verify order moves NEW -> IN_PROGRESS -> FINISHED")
+
+	ctx := context.Background()
+	order := s.CreateOrder(ctx, s.GenerateUserID(), "Keyboard", 49.99)
+	s.Require().Equal("NEW", order.Status)
+
+	// Move order to IN_PROGRESS
+	resp, err := s.orderClient.UpdateOrderStatus(ctx, connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+		Id:     order.Id,
+		Status: "IN_PROGRESS",
+	}))
+	s.Require().NoError(err)
+	s.Require().Equal("IN_PROGRESS", resp.Msg.Order.Status)
+
+	// Move order to FINISHED
+	resp, err = s.orderClient.UpdateOrderStatus(ctx, connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+		Id:     order.Id,
+		Status: "FINISHED",
+	}))
+	s.Require().NoError(err)
+	s.Require().Equal("FINISHED", resp.Msg.Order.Status)
+
+	// Verify the new status is persisted
+	getResp, err := s.orderClient.GetOrder(ctx, connect.NewRequest(&orderv1.GetOrderRequest{
+		Id: order.Id,
+	}))
+	s.Require().NoError(err)
+	s.Require().Equal("FINISHED", getResp.Msg.Order.Status)
+}
+
+func (s *UpdateOrderStatusSuite) TestUpdateOrderStatus_IllegalTransition() {
+	s.WithAllure("UpdateOrderStatus_IllegalTransition", "Verify FailedPrecondition when skipping IN_PROGRESS")
+
+	ctx := context.Background()
+	order := s.CreateOrder(ctx, s.GenerateUserID(), "Mouse", 19.99)
+
+	_, err := s.orderClient.UpdateOrderStatus(ctx, connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+		Id:     order.Id,
+		Status: "FINISHED",
+	}))
+
+	s.Require().Error(err)
+	var connectErr *connect.Error
+	s.Require().ErrorAs(err, &connectErr)
+	s.Require().Equal(connect.CodeFailedPrecondition, connectErr.Code())
+}
+
+func (s *UpdateOrderStatusSuite) TestUpdateOrderStatus_ValidationErrors() {
+	s.WithAllure("UpdateOrderStatus_ValidationErrors", "Verify validation errors for invalid input")
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name    string
+		id      string
+		status  string
+		wantErr connect.Code
+		// wantViolations are the field violations of invalid arguments.
+		wantViolations map[string]string
+	}{
+		{
+			name:    "empty_id",
+			id:      "",
+			status:  "IN_PROGRESS",
+			wantErr: connect.CodeInvalidArgument,
+			wantViolations: map[string]string{
+				"id": "must be set",
+			},
+		},
+		{
+			name:    "unknown_status",
+			id:      uuid.New().String(),
+			status:  "SHIPPED",
+			wantErr: connect.CodeInvalidArgument,
+			wantViolations: map[string]string{
+				"status": "must be one of NEW, IN_PROGRESS, FINISHED, CANCELLED",
+			},
+		},
+		{
+			name:    "cancelled_status",
+			id:      uuid.New().String(),
+			status:  "CANCELLED",
+			wantErr: connect.CodeInvalidArgument,
+			wantViolations: map[string]string{
+				"status": "orders are cancelled via CancelOrder",
+			},
+		},
+		{
+			name:    "order_not_found",
+			id:      uuid.New().String(),
+			status:  "IN_PROGRESS",
+			wantErr: connect.CodeNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			_, err := s.orderClient.UpdateOrderStatus(ctx, connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+				Id:     tc.id,
+				Status: tc.status,
+			}))
+
+			s.Require().Error(err, "Expected error for %s", tc.name)
+			var connectErr *connect.Error
+			s.Require().ErrorAs(err, &connectErr)
+			s.Require().Equal(tc.wantErr, connectErr.Code(), "Expected %v error code", tc.wantErr)
+			s.Require().Equal(tc.wantViolations, validation.FieldViolations(err))
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package isolation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	orderv1 "github.com/demo/contracts/gen/go/order/v1"
+	"github.com/demo/order/internal/validation"
+	"github.com/stretchr/testify/suite"
+)
+
+type WatchOrdersSuite struct {
+	Suite
+}
+
+func TestWatchOrdersSuite(t *testing.T) {
+	suite.Run(t, new(WatchOrdersSuite))
+}
+
+// watch opens a WatchOrders stream. The call returns once the server is
+// subscribed, so updates made afterwards are streamed. The stream never ends on
+// its own, so it is closed by cancelling ctx rather than with Close, which
+// reads the stream to the end.
+func (s *WatchOrdersSuite) watch(
+	ctx context.Context,
+	req *orderv1.WatchOrdersRequest,
+) *connect.ServerStreamForClient[orderv1.WatchOrdersResponse] {
+	stream, err := s.orderClient.WatchOrders(ctx, connect.NewRequest(req))
+	s.Require().NoError(err)
+	return stream
+}
+
+// receive waits for the next update on the stream.
+func (s *WatchOrdersSuite) receive(stream *connect.ServerStreamForClient[orderv1.WatchOrdersResponse]) *orderv1.WatchOrdersResponse {
+	s.Require().True(stream.Receive(), "Expected an update, stream ended with: %v", stream.Err())
+	return stream.Msg()
+}
+
+func (s *WatchOrdersSuite) TestWatchOrders_StreamsUpdatesOfUser() {
+	s.WithAllure("WatchOrders_StreamsUpdatesOfUser", "Verify the stream pushes created and updated orders of the watched user")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	userID := s.GenerateUserID()
+
+	stream := s.watch(ctx, &orderv1.WatchOrdersRequest{UserId: userID})
+
+	// An order of another user is not streamed
+	s.CreateOrder(ctx, s.GenerateUserID(), "Mouse", 19.99)
+	order := s.CreateOrder(ctx, userID, "Keyboard", 49.99)
+	_, err := s.orderClient.UpdateOrderStatus(ctx, connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+		Id:     order.Id,
+		Status: "IN_PROGRESS",
+	}))
+	s.Require().NoError(err)
+
+	created := s.receive(stream)
+	s.Require().Equal("ORDER_CREATED", created.EventType)
+	s.Require().Equal(order.Id, created.Order.Id)
+	s.Require().Equal(userID, created.Order.UserId)
+	s.Require().Equal("NEW", created.Order.Status)
+	s.Require().Equal(49.99, created.Order.Amount)
+	s.Require().NotEmpty(created.Cursor)
+
+	updated := s.receive(stream)
+	s.Require().Equal("ORDER_STATUS_CHANGED", updated.EventType)
+	s.Require().Equal(order.Id, updated.Order.Id)
+	s.Require().Equal("IN_PROGRESS", updated.Order.Status)
+	s.Require().NotEqual(created.Cursor, updated.Cursor)
+}
+
+func (s *WatchOrdersSuite) TestWatchOrders_FiltersByStatus() {
+	s.WithAllure("WatchOrders_FiltersByStatus", "Verify only updates leaving the order in the watched status are streamed")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	userID := s.GenerateUserID()
+
+	stream := s.watch(ctx, &orderv1.WatchOrdersRequest{UserId: userID, Status: "CANCELLED"})
+
+	order := s.CreateOrder(ctx, userID, "Monitor", 199.00)
+	_, err := s.orderClient.CancelOrder(ctx, connect.NewRequest(&orderv1.CancelOrderRequest{
+		Id:          order.Id,
+		Reason:      "CUSTOMER_REQUEST",
+		CancelledBy: userID,
+	}))
+	s.Require().NoError(err)
+
+	cancelled := s.receive(stream)
+	s.Require().Equal("ORDER_CANCELLED", cancelled.EventType)
+	s.Require().Equal(order.Id, cancelled.Order.Id)
+	s.Require().Equal("CANCELLED", cancelled.Order.Status)
+	s.Require().Equal("CUSTOMER_REQUEST", cancelled.Order.CancelReason)
+}
+
+func (s *WatchOrdersSuite) TestWatchOrders_ResumesFromCursor() {
+	s.WithAllure("WatchOrders_ResumesFromCursor", "Verify updates made while disconnected are received after reconnecting with the cursor")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	userID := s.GenerateUserID()
+
+	streamCtx, disconnect := context.WithCancel(ctx)
+	stream := s.watch(streamCtx, &orderv1.WatchOrdersRequest{UserId: userID})
+	order := s.CreateOrder(ctx, userID, "Headphones", 89.00)
+	created := s.receive(stream)
+	s.Require().Equal(order.Id, created.Order.Id)
+	disconnect()
+
+	// The order changes while the client is disconnected
+	_, err := s.orderClient.UpdateOrderStatus(ctx, connect.NewRequest(&orderv1.UpdateOrderStatusRequest{
+		Id:     order.Id,
+		Status: "IN_PROGRESS",
+	}))
+	s.Require().NoError(err)
+
+	stream = s.watch(ctx, &orderv1.WatchOrdersRequest{UserId: userID, Cursor: created.Cursor})
+
+	missed := s.receive(stream)
+	s.Require().Equal("ORDER_STATUS_CHANGED", missed.EventType)
+	s.Require().Equal(order.Id, missed.Order.Id)
+	s.Require().Equal("IN_PROGRESS", missed.Order.Status)
+}
+
+func (s *WatchOrdersSuite) TestWatchOrders_InvalidArgument() {
+	s.WithAllure("WatchOrders_InvalidArgument", "Verify invalid filters and cursors are rejected")
+
+	testCases := []struct {
+		name string
+		req  *orderv1.WatchOrdersRequest
+		// wantField is the field with a violation.
+		wantField string
+	}{
+		{name: "unknown status", req: &orderv1.WatchOrdersRequest{Status: "UNKNOWN"}, wantField: "status"},
+		{name: "malformed cursor", req: &orderv1.WatchOrdersRequest{Cursor: "abc"}, wantField: "cursor"},
+		{name: "negative cursor", req: &orderv1.WatchOrdersRequest{Cursor: "-1"}, wantField: "cursor"},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			stream, err := s.orderClient.WatchOrders(ctx, connect.NewRequest(tc.req))
+			s.Require().NoError(err)
+			defer stream.Close()
+
+			s.Require().False(stream.Receive())
+			s.Require().Equal(connect.CodeInvalidArgument, connect.CodeOf(stream.Err()))
+			s.Require().Contains(validation.FieldViolations(stream.Err()), tc.wantField)
+		})
+	}
+}